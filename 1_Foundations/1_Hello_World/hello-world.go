@@ -1,30 +1,173 @@
 // Package main demonstrates a simple Go program that prints a greeting message to the console.
+//
+// It starts from the classic "Hello, World!" and grows into a small but complete
+// command-line tool that can be used as a template for new CLIs:
+//
+//	go run hello-world.go                              # Hello, World!
+//	go run hello-world.go --name Gopher --greeting Hi  # Hi, Gopher!
+//	go run hello-world.go --format json                # {"greeting":"Hello","name":"World","message":"Hello, World!"}
+//	printf "Alice\nBob\n" | go run hello-world.go --stdin
+//	go test *.go                                       # every format, --stdin and the usage errors
+//
+// The i18n project renders the greeting in the learner's language:
+//
+//	cd ../../4_Projects/1_I18n && go run main.go i18n.go lesson.go plural.go --locale fr --lesson ../../1_Foundations/1_Hello_World
+//
+// Exit codes:
+//   - 0: every greeting was printed successfully.
+//   - 1: a runtime failure occurred (for example, reading stdin or writing output failed).
+//   - 2: the command line was invalid (unknown flag, bad --format value, unexpected arguments).
 package main
 
-// Importing the "fmt" package, which provides functions for formatted I/O operations.
+// Importing the packages used by the program.
 // The fmt package is used to print output, read input, and format strings.
+// The bufio, encoding/json, errors, flag, io and os packages turn the greeting into a real CLI.
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 )
 
+// Exit codes returned by run. Keeping them as named constants documents the
+// contract callers (shell scripts, CI jobs) can rely on.
+const (
+	exitOK      = 0 // Everything worked.
+	exitFailure = 1 // Runtime error such as an I/O failure.
+	exitUsage   = 2 // Invalid flags or arguments.
+)
+
+// Supported values for the --format flag.
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// options holds the parsed command-line flags.
+type options struct {
+	Name     string
+	Greeting string
+	Format   string
+	Stdin    bool
+}
+
+// greetingMessage is the JSON shape written when --format=json is used.
+type greetingMessage struct {
+	Greeting string `json:"greeting"`
+	Name     string `json:"name"`
+	Message  string `json:"message"`
+}
+
 // The main function is the entry point of the Go program.
 // Every Go program must have a main function in the "main" package to execute.
+// All of the real work happens in run, which returns an exit code instead of
+// calling os.Exit itself so that it stays easy to reuse and to test.
 func main() {
-	// Declaring and initializing a variable 'name' with the value "World".
-	// The ':=' syntax is used to declare a variable with automatic type inference.
-	// Here, 'name' will be of type string because "World" is a string literal.
-	// Go automatically infers the variable type based on the value assigned.
-	name := "World"
-
-	// The fmt.Printf function is used to print formatted output to the console.
-	// The "%s" format specifier is used to insert the value of 'name' (a string) into the output string.
-	// In this case, it prints the message: "Hello, World!"
-	// The '\n' at the end ensures the cursor moves to the next line after printing.
-	// fmt.Printf allows for more advanced formatting options if needed.
-	fmt.Printf("Hello, %s!\n", name)
-
-	// Notes on fmt.Printf:
-	// - "%s" is used to print a string.
-	// - "\n" represents a newline character, ensuring the output appears cleanly on its own line.
-	// - The '%s' format specifier is useful when inserting variables like strings into a formatted output.
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// Run prints the default "Hello, World!" greeting to w, the same output as
+// running the program without flags, so the lesson can be captured like every other one.
+func Run(w io.Writer) error {
+	return writeGreeting(w, options{Name: "World", Greeting: "Hello", Format: formatText}, "World")
+}
+
+// run parses args, prints one greeting per name and returns the process exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts, err := parseFlags(args, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return exitUsage
+	}
+
+	// Buffer the output and flush it once at the end; a failed flush is reported like any other write error.
+	out := bufio.NewWriter(stdout)
+	if err := greetAll(out, stdin, opts); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return exitFailure
+	}
+	if err := out.Flush(); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+// parseFlags defines the command-line flags and validates their values.
+// The standard flag package accepts both "-name" and "--name" spellings.
+func parseFlags(args []string, stderr io.Writer) (options, error) {
+	var opts options
+
+	fs := flag.NewFlagSet("hello-world", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	// Declaring the flags with their default values.
+	// Before this program became a CLI, the name was hardcoded as name := "World".
+	fs.StringVar(&opts.Name, "name", "World", "name to greet")
+	fs.StringVar(&opts.Greeting, "greeting", "Hello", "greeting word to use")
+	fs.StringVar(&opts.Format, "format", formatText, "output format: text or json")
+	fs.BoolVar(&opts.Stdin, "stdin", false, "read names from standard input, one per line")
+
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	if opts.Format != formatText && opts.Format != formatJSON {
+		return opts, fmt.Errorf("invalid --format %q: must be %q or %q", opts.Format, formatText, formatJSON)
+	}
+	return opts, nil
+}
+
+// greetAll writes a greeting for the --name flag, or for every non-empty line of stdin when --stdin is set.
+func greetAll(w io.Writer, stdin io.Reader, opts options) error {
+	if !opts.Stdin {
+		return writeGreeting(w, opts, opts.Name)
+	}
+
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" {
+			continue // Skip blank lines instead of printing "Hello, !".
+		}
+		if err := writeGreeting(w, opts, name); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading stdin: %w", err)
+	}
+	return nil
+}
+
+// writeGreeting prints a single greeting in the requested format.
+func writeGreeting(w io.Writer, opts options, name string) error {
+	// The "%s" format specifier inserts a string into the output, and '\n' ends the line.
+	// With the default flags this prints exactly: "Hello, World!"
+	message := fmt.Sprintf("%s, %s!", opts.Greeting, name)
+
+	if opts.Format == formatJSON {
+		// json.Encoder writes one JSON object per line, which is easy to pipe into other tools.
+		return json.NewEncoder(w).Encode(greetingMessage{
+			Greeting: opts.Greeting,
+			Name:     name,
+			Message:  message,
+		})
+	}
+	_, err := fmt.Fprintln(w, message)
+	return err
 }
+
+// Notes on fmt.Printf and friends:
+// - "%s" is used to print a string.
+// - "\n" represents a newline character, ensuring the output appears cleanly on its own line.
+// - fmt.Fprintf / fmt.Fprintln write to any io.Writer, not just the console, which is why
+//   this program can send its output to stdout, a file or a buffer without changes.
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		stdin  string
+		code   int
+		stdout string
+		stderr string // A substring of stderr; empty means stderr must be empty.
+	}{
+		{"default", nil, "", exitOK, "Hello, World!\n", ""},
+		{"flags", []string{"--name", "Gopher", "--greeting", "Hi"}, "", exitOK, "Hi, Gopher!\n", ""},
+		{"single dash", []string{"-name=Gopher"}, "", exitOK, "Hello, Gopher!\n", ""},
+		{"text", []string{"--format", "text"}, "", exitOK, "Hello, World!\n", ""},
+		{
+			"json", []string{"--format", "json", "--name", "Gopher"}, "", exitOK,
+			`{"greeting":"Hello","name":"Gopher","message":"Hello, Gopher!"}` + "\n", "",
+		},
+		{"stdin", []string{"--stdin"}, "Alice\n\n  Bob  \n", exitOK, "Hello, Alice!\nHello, Bob!\n", ""},
+		{"stdin without a final newline", []string{"--stdin", "--greeting", "Hey"}, "Alice", exitOK, "Hey, Alice!\n", ""},
+		{"empty stdin", []string{"--stdin"}, "", exitOK, "", ""},
+		{
+			"stdin json", []string{"--stdin", "--format", "json"}, "Alice\nBob\n", exitOK,
+			`{"greeting":"Hello","name":"Alice","message":"Hello, Alice!"}` + "\n" +
+				`{"greeting":"Hello","name":"Bob","message":"Hello, Bob!"}` + "\n", "",
+		},
+		{"help", []string{"--help"}, "", exitOK, "", "-greeting"},
+		{"unknown flag", []string{"--loud"}, "", exitUsage, "", "flag provided but not defined: -loud"},
+		{"bad format", []string{"--format", "xml"}, "", exitUsage, "", `invalid --format "xml"`},
+		{"extra arguments", []string{"Gopher", "Alice"}, "", exitUsage, "", "unexpected arguments: Gopher Alice"},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+		if code != tt.code {
+			t.Errorf("%s: exit code %d, want %d", tt.name, code, tt.code)
+		}
+		if got := stdout.String(); got != tt.stdout {
+			t.Errorf("%s: stdout = %q, want %q", tt.name, got, tt.stdout)
+		}
+		if tt.stderr == "" && stderr.Len() > 0 || !strings.Contains(stderr.String(), tt.stderr) {
+			t.Errorf("%s: stderr = %q, want %q", tt.name, &stderr, tt.stderr)
+		}
+	}
+}
+
+// failingWriter fails every write, like stdout closed by the reader of a pipe.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, io.ErrClosedPipe }
+
+func TestRunWriteError(t *testing.T) {
+	var stderr bytes.Buffer
+	if code := run(nil, strings.NewReader(""), failingWriter{}, &stderr); code != exitFailure {
+		t.Errorf("exit code %d, want %d", code, exitFailure)
+	}
+	if !strings.Contains(stderr.String(), io.ErrClosedPipe.Error()) {
+		t.Errorf("stderr = %q, want the write error", &stderr)
+	}
+}
+
+func TestRunLesson(t *testing.T) {
+	var b strings.Builder
+	if err := Run(&b); err != nil || b.String() != "Hello, World!\n" {
+		t.Errorf("Run = %q, %v; want Hello, World!", b.String(), err)
+	}
+}
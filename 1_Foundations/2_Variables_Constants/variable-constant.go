@@ -1,10 +1,46 @@
 // Package main demonstrates the use of data types, variables, constants,
 // type inference, and default values in Go with practical examples.
+//
+// The Priority enum's methods live in the generated priority_enum.go, so run
+// the lesson with both files: go run *.go
 package main
 
-import "fmt"
-
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Priority levels in task management systems.
+// Declaring a named type for an iota block lets the compiler tell priorities
+// apart from plain integers, and lets us attach methods such as String().
+// Run "go generate" to regenerate its methods after changing the constants.
+//
+//go:generate go run ../../4_Projects/3_Enumgen/enumgen.go -type=Priority
+type Priority int
+
+// The blank identifier reserves the zero value, so an unset Priority is invalid.
+const (
+	_ Priority = iota
+	Low
+	Medium
+	High
+)
+
+// main runs the lesson and writes its output to the console.
 func main() {
+	if err := Run(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// Run declares variables with explicit and inferred types, prints their zero
+// values, and goes through constants, the Priority enum, conversions, booleans
+// and strings. Besides write errors it fails only if ParsePriority rejects a
+// known name.
+func Run(out io.Writer) error {
+	w := &errWriter{w: out}
 	// SECTION 1: Variables with Explicit Types
 	// Declaring variables with explicit types ensures clarity about the variable's purpose and data type.
 	var name string = "Alice"
@@ -12,8 +48,8 @@ func main() {
 	var height float64 = 5.9
 	var isStudent bool = false
 
-	fmt.Println("SECTION 1: Explicit Type Variables")
-	fmt.Printf("Name: %s, Age: %d, Height: %.1f, Is Student: %t\n\n", name, age, height, isStudent)
+	fmt.Fprintln(w, "SECTION 1: Explicit Type Variables")
+	fmt.Fprintf(w, "Name: %s, Age: %d, Height: %.1f, Is Student: %t\n\n", name, age, height, isStudent)
 	// Example Use Case: Defining user profile fields explicitly in a config.
 
 	// SECTION 2: Type Inference
@@ -22,8 +58,8 @@ func main() {
 	inferredHeight := 6.2
 	inferredStudent := true
 
-	fmt.Println("SECTION 2: Type Inference")
-	fmt.Printf("Name: %s, Age: %d, Height: %.1f, Is Student: %t\n\n", inferredName, inferredAge, inferredHeight, inferredStudent)
+	fmt.Fprintln(w, "SECTION 2: Type Inference")
+	fmt.Fprintf(w, "Name: %s, Age: %d, Height: %.1f, Is Student: %t\n\n", inferredName, inferredAge, inferredHeight, inferredStudent)
 	// Example Use Case: Local function variables inferred based on returned data.
 
 	// SECTION 3: Default Values (Zero Values)
@@ -32,26 +68,27 @@ func main() {
 	var zeroFloat float64
 	var zeroBool bool
 
-	fmt.Println("SECTION 3: Default (Zero) Values")
-	fmt.Printf("String: '%s', Int: %d, Float: %.1f, Bool: %t\n\n", zeroString, zeroInt, zeroFloat, zeroBool)
+	fmt.Fprintln(w, "SECTION 3: Default (Zero) Values")
+	fmt.Fprintf(w, "String: '%s', Int: %d, Float: %.1f, Bool: %t\n\n", zeroString, zeroInt, zeroFloat, zeroBool)
 	// Example Use Case: Default values in HTTP struct configurations.
 
 	// SECTION 4: Constants
 	const pi float64 = 3.14159
 	const gravity = 9.8
 
-	fmt.Println("SECTION 4: Constants")
-	fmt.Printf("Pi: %.5f, Gravity: %.1f\n\n", pi, gravity)
-
-	// Constant block using iota
-	const (
-		_ = iota
-		Low
-		Medium
-		High
-	)
-	fmt.Println("SECTION 4B: Constants with iota")
-	fmt.Printf("Low: %d, Medium: %d, High: %d\n\n", Low, Medium, High)
+	fmt.Fprintln(w, "SECTION 4: Constants")
+	fmt.Fprintf(w, "Pi: %.5f, Gravity: %.1f\n\n", pi, gravity)
+
+	// Constant block using iota (see the Priority type at the top of the file)
+	fmt.Fprintln(w, "SECTION 4B: Constants with iota")
+	fmt.Fprintf(w, "Low: %d, Medium: %d, High: %d\n", Low, Medium, High)
+	// The generated String method makes %s and %v print names instead of numbers.
+	fmt.Fprintf(w, "Low: %s, Medium: %s, High: %s\n", Low, Medium, High)
+	parsed, err := ParsePriority("high")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Parsed %q: %v (valid: %t), Priority(0) valid: %t\n\n", "high", parsed, parsed.IsValid(), Priority(0).IsValid())
 	// Example Use Case: Priority levels in task management systems.
 
 	// SECTION 5: Data Types in Go
@@ -63,15 +100,15 @@ func main() {
 	var aRune rune = 'A'
 	var aByte byte = 'B'
 
-	fmt.Println("SECTION 5: Data Types")
-	fmt.Printf("Small Int: %d, Large Int: %d\n", smallInt, largeInt)
-	fmt.Printf("Float32: %.2f, Float64: %.10f\n", singlePrecision, doublePrecision)
-	fmt.Printf("Complex: %.1f + %.1fi\n", real(complexNum), imag(complexNum))
-	fmt.Printf("Rune: %c (Unicode: %U), Byte: %c\n\n", aRune, aRune, aByte)
+	fmt.Fprintln(w, "SECTION 5: Data Types")
+	fmt.Fprintf(w, "Small Int: %d, Large Int: %d\n", smallInt, largeInt)
+	fmt.Fprintf(w, "Float32: %.2f, Float64: %.10f\n", singlePrecision, doublePrecision)
+	fmt.Fprintf(w, "Complex: %.1f + %.1fi\n", real(complexNum), imag(complexNum))
+	fmt.Fprintf(w, "Rune: %c (Unicode: %U), Byte: %c\n\n", aRune, aRune, aByte)
 
 	emoji := "🚀"
 	runes := []rune(emoji)
-	fmt.Printf("Rune Count in '%s': %d, Unicode: %U\n", emoji, len(runes), runes[0])
+	fmt.Fprintf(w, "Rune Count in '%s': %d, Unicode: %U\n", emoji, len(runes), runes[0])
 	// Example Use Case: Logging emoji status in APIs or converting UTF-8 streams.
 
 	// SECTION 6: Conversion between Data Types
@@ -79,29 +116,29 @@ func main() {
 	floatVal := float64(intVal)
 	uintVal := uint(floatVal)
 
-	fmt.Println("SECTION 6: Type Conversion")
-	fmt.Printf("Int: %d, Float64: %.2f, Uint: %d\n\n", intVal, floatVal, uintVal)
+	fmt.Fprintln(w, "SECTION 6: Type Conversion")
+	fmt.Fprintf(w, "Int: %d, Float64: %.2f, Uint: %d\n\n", intVal, floatVal, uintVal)
 	// Be cautious: Converting float64 to uint truncates the decimal part.
 	// Example Use Case: Parsing JSON numbers or converting between types in API handlers.
 
 	// SECTION 7: Working with Booleans
 	isAdult := age >= 18
-	fmt.Println("SECTION 7: Booleans")
-	fmt.Printf("Is Adult (Age >= 18): %t\n", isAdult)
+	fmt.Fprintln(w, "SECTION 7: Booleans")
+	fmt.Fprintf(w, "Is Adult (Age >= 18): %t\n", isAdult)
 	// Example Use Case: Authorization logic based on user properties.
 
 	// SECTION 8: String Operations
 	greeting := "Hello"
 	audience := "World"
 	combined := greeting + ", " + audience + "!"
-	fmt.Println("SECTION 8: Strings")
-	fmt.Printf("Combined String: %s\n", combined)
+	fmt.Fprintln(w, "SECTION 8: Strings")
+	fmt.Fprintf(w, "Combined String: %s\n", combined)
 
 	config := `{
 	"env": "prod",
 	"debug": false
 	}`
-	fmt.Println("Raw Config:\n", config)
+	fmt.Fprintln(w, "Raw Config:\n", config)
 	// Example Use Case: Generating raw config templates for cloud deployment.
 
 	// SECTION 9: Best Practices Summary
@@ -110,4 +147,22 @@ func main() {
 	// - Use 'const' for values that never change.
 	// - Always convert types explicitly to avoid silent bugs.
 	// - Understand zero values — they're idiomatic in Go.
+	return w.err
+}
+
+// errWriter passes writes through to w until one fails. Its err field starts
+// at the zero value nil, so Run can return w.err whether or not anything went
+// wrong.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	n, err := ew.w.Write(p)
+	ew.err = err
+	return n, err
 }
@@ -1,131 +1,164 @@
 // Package main demonstrates conditional statements (if-else), loops (for), and switch-case constructs in Go.
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+)
 
+// main runs the lesson and writes its output to the console.
 func main() {
+	if err := Run(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// Run goes through if/else with an init statement, the three for loop forms
+// with break and continue, and the four kinds of switch.
+func Run(out io.Writer) error {
+	w := &errWriter{w: out}
 	// SECTION 1: Conditional Statements (if-else)
-	fmt.Println("SECTION 1: Conditional Statements")
+	fmt.Fprintln(w, "SECTION 1: Conditional Statements")
 
 	age := 17 // A sample variable for age
 	if age > 18 {
-		fmt.Println("You are an Adult.")
+		fmt.Fprintln(w, "You are an Adult.")
 	} else if age == 18 {
-		fmt.Println("You just turned 18!")
+		fmt.Fprintln(w, "You just turned 18!")
 	} else {
-		fmt.Println("You are a Minor.")
+		fmt.Fprintln(w, "You are a Minor.")
 	}
 
 	// Real-world example: Access control
 	userLoggedIn := true
 	userRole := "admin"
 	if userLoggedIn && userRole == "admin" {
-		fmt.Println("Welcome Admin! Access granted.")
+		fmt.Fprintln(w, "Welcome Admin! Access granted.")
 	} else {
-		fmt.Println("Access Denied.")
+		fmt.Fprintln(w, "Access Denied.")
 	}
 
 	isWeekend := false
 	if isWeekend {
-		fmt.Println("Relax, it's the weekend!")
+		fmt.Fprintln(w, "Relax, it's the weekend!")
 	} else {
-		fmt.Println("Time to work!")
+		fmt.Fprintln(w, "Time to work!")
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 2: Loops in Go
-	fmt.Println("SECTION 2: Loops")
+	fmt.Fprintln(w, "SECTION 2: Loops")
 
-	fmt.Println("Basic for loop:")
+	fmt.Fprintln(w, "Basic for loop:")
 	for i := 0; i < 5; i++ {
-		fmt.Printf("Iteration %d\n", i)
+		fmt.Fprintf(w, "Iteration %d\n", i)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
-	fmt.Println("Using 'for' as a while loop:")
+	fmt.Fprintln(w, "Using 'for' as a while loop:")
 	counter := 3
 	for counter > 0 {
-		fmt.Printf("Counter: %d\n", counter)
+		fmt.Fprintf(w, "Counter: %d\n", counter)
 		counter--
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
-	fmt.Println("Infinite loop with break:")
+	fmt.Fprintln(w, "Infinite loop with break:")
 	count := 0
 	for {
 		if count == 3 {
-			fmt.Println("Breaking out of the loop!")
+			fmt.Fprintln(w, "Breaking out of the loop!")
 			break
 		}
-		fmt.Printf("Count: %d\n", count)
+		fmt.Fprintf(w, "Count: %d\n", count)
 		count++
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
-	fmt.Println("Loop with continue statement:")
+	fmt.Fprintln(w, "Loop with continue statement:")
 	for i := 1; i <= 5; i++ {
 		if i%2 == 0 {
 			continue
 		}
-		fmt.Printf("Odd Number: %d\n", i)
+		fmt.Fprintf(w, "Odd Number: %d\n", i)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// Real-world loop example: Sum of first N numbers
 	sum := 0
 	for i := 1; i <= 100; i++ {
 		sum += i
 	}
-	fmt.Printf("Sum of 1 to 100: %d\n\n", sum)
+	fmt.Fprintf(w, "Sum of 1 to 100: %d\n\n", sum)
 
 	// SECTION 3: Switch Statement
-	fmt.Println("SECTION 3: Switch Statement")
+	fmt.Fprintln(w, "SECTION 3: Switch Statement")
 
 	day := 3
 	switch day {
 	case 1:
-		fmt.Println("Monday")
+		fmt.Fprintln(w, "Monday")
 	case 2:
-		fmt.Println("Tuesday")
+		fmt.Fprintln(w, "Tuesday")
 	case 3:
-		fmt.Println("Wednesday")
+		fmt.Fprintln(w, "Wednesday")
 	case 4, 5:
-		fmt.Println("Thursday or Friday")
+		fmt.Fprintln(w, "Thursday or Friday")
 	default:
-		fmt.Println("Weekend")
+		fmt.Fprintln(w, "Weekend")
 	}
 
-	fmt.Println("Switch with no condition:")
+	fmt.Fprintln(w, "Switch with no condition:")
 	ageCategory := 25
 	switch {
 	case ageCategory < 18:
-		fmt.Println("Underage")
+		fmt.Fprintln(w, "Underage")
 	case ageCategory >= 18 && ageCategory < 60:
-		fmt.Println("Working age")
+		fmt.Fprintln(w, "Working age")
 	default:
-		fmt.Println("Senior citizen")
+		fmt.Fprintln(w, "Senior citizen")
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
-	fmt.Println("Switch with fallthrough:")
+	fmt.Fprintln(w, "Switch with fallthrough:")
 	switch level := 2; level {
 	case 1:
-		fmt.Println("Level 1")
+		fmt.Fprintln(w, "Level 1")
 		fallthrough
 	case 2:
-		fmt.Println("Level 2")
+		fmt.Fprintln(w, "Level 2")
 	case 3:
-		fmt.Println("Level 3")
+		fmt.Fprintln(w, "Level 3")
 	default:
-		fmt.Println("Unknown level")
+		fmt.Fprintln(w, "Unknown level")
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
-	fmt.Println("Switch with variable declaration:")
+	fmt.Fprintln(w, "Switch with variable declaration:")
 	switch num := 15; {
 	case num%2 == 0:
-		fmt.Println("Even number")
+		fmt.Fprintln(w, "Even number")
 	case num%2 != 0:
-		fmt.Println("Odd number")
+		fmt.Fprintln(w, "Odd number")
+	}
+	return w.err
+}
+
+// errWriter lets the loops and switches in Run print freely: once a write
+// fails, the if at the top of Write turns every later write into a no-op that
+// returns the same error, and Run checks it once at the end.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
 	}
+	n, err := ew.w.Write(p)
+	ew.err = err
+	return n, err
 }
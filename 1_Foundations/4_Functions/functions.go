@@ -2,7 +2,11 @@
 // variadic, anonymous, closures, recursion, and function types.
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+)
 
 // SECTION 1: Basic Function
 // greet returns a greeting message with the provided name.
@@ -30,20 +34,20 @@ func sumAll(numbers ...int) int {
 
 // SECTION 4: Anonymous Function
 // Demonstrates how to use an unnamed function.
-func demonstrateAnonymousFunction() {
+func demonstrateAnonymousFunction(w io.Writer) {
 	anonymous := func(a, b int) int {
 		return a * b
 	}
-	fmt.Printf("Multiplication of 3 and 4: %d\n", anonymous(3, 4))
+	fmt.Fprintf(w, "Multiplication of 3 and 4: %d\n", anonymous(3, 4))
 }
 
 // SECTION 5: Closure
 // showClosure demonstrates a function that captures and uses an external variable.
-func showClosure() func() {
+func showClosure(w io.Writer) func() {
 	counter := 0
 	return func() {
 		counter++
-		fmt.Printf("Counter: %d\n", counter)
+		fmt.Fprintf(w, "Counter: %d\n", counter)
 	}
 }
 
@@ -70,8 +74,8 @@ func multiply(x, y int) int {
 }
 
 // demonstrateFunctionType shows how to use a function type as a parameter.
-func demonstrateFunctionType(op operation, a, b int) {
-	fmt.Printf("Result of operation: %d\n", op(a, b))
+func demonstrateFunctionType(w io.Writer, op operation, a, b int) {
+	fmt.Fprintf(w, "Result of operation: %d\n", op(a, b))
 }
 
 // SECTION 8: Higher-Order Functions
@@ -80,61 +84,88 @@ func higherOrder(a, b int, fn func(int, int) int) int {
 	return fn(a, b)
 }
 
-// main is the entry point for the program.
+// main runs the lesson and writes its output to the console.
 func main() {
+	if err := Run(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// Run calls each function defined above, from greet to higherOrder, and
+// prints what it returns.
+func Run(out io.Writer) error {
+	w := &errWriter{w: out}
 	// SECTION 1: Calling a Basic Function
-	fmt.Println("SECTION 1: Basic Function")
+	fmt.Fprintln(w, "SECTION 1: Basic Function")
 	message := greet("Alice")
-	fmt.Println(message)
+	fmt.Fprintln(w, message)
 	// Practice: Try calling greet() with your own name.
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 2: Using a Function with Multiple Return Values
-	fmt.Println("SECTION 2: Function with Multiple Return Values")
+	fmt.Fprintln(w, "SECTION 2: Function with Multiple Return Values")
 	sum, diff := calculate(10, 5)
-	fmt.Printf("Sum: %d, Difference: %d\n", sum, diff)
+	fmt.Fprintf(w, "Sum: %d, Difference: %d\n", sum, diff)
 	// Practice: Create a new function that returns product and quotient of two numbers.
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 3: Variadic Function
-	fmt.Println("SECTION 3: Variadic Function")
+	fmt.Fprintln(w, "SECTION 3: Variadic Function")
 	total := sumAll(1, 2, 3, 4, 5)
-	fmt.Printf("Sum of numbers: %d\n", total)
+	fmt.Fprintf(w, "Sum of numbers: %d\n", total)
 	// Practice: Modify sumAll to return the average as well.
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 4: Anonymous Function
-	fmt.Println("SECTION 4: Anonymous Function")
-	demonstrateAnonymousFunction()
+	fmt.Fprintln(w, "SECTION 4: Anonymous Function")
+	demonstrateAnonymousFunction(w)
 	// Practice: Write an anonymous function that returns square of a number.
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 5: Closure
-	fmt.Println("SECTION 5: Closure")
-	increment := showClosure()
+	fmt.Fprintln(w, "SECTION 5: Closure")
+	increment := showClosure(w)
 	increment()
 	increment()
 	// Practice: Try creating a closure that accumulates sum.
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 6: Recursive Function
-	fmt.Println("SECTION 6: Recursive Function")
-	fmt.Printf("Factorial of 5: %d\n", factorial(5))
+	fmt.Fprintln(w, "SECTION 6: Recursive Function")
+	fmt.Fprintf(w, "Factorial of 5: %d\n", factorial(5))
 	// Practice: Write a recursive function to compute Fibonacci numbers.
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 7: Function Types
-	fmt.Println("SECTION 7: Function Types")
-	demonstrateFunctionType(add, 10, 5)
-	demonstrateFunctionType(multiply, 10, 5)
+	fmt.Fprintln(w, "SECTION 7: Function Types")
+	demonstrateFunctionType(w, add, 10, 5)
+	demonstrateFunctionType(w, multiply, 10, 5)
 	// Practice: Create a new operation type function that divides two numbers.
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 8: Higher-Order Functions
-	fmt.Println("SECTION 8: Higher-Order Functions")
+	fmt.Fprintln(w, "SECTION 8: Higher-Order Functions")
 	result := higherOrder(3, 4, func(x, y int) int {
 		return x * y
 	})
-	fmt.Printf("Result of higher-order function: %d\n", result)
+	fmt.Fprintf(w, "Result of higher-order function: %d\n", result)
 	// Practice: Use higherOrder with subtraction logic.
+	return w.err
+}
+
+// errWriter spares Run from checking the second result of every Fprintln
+// call: Write records the first error instead, and Run returns it at the end.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	n, err := ew.w.Write(p)
+	ew.err = err
+	return n, err
 }
@@ -2,86 +2,101 @@
 // along with their key operations, properties, and use cases.
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+)
 
+// main runs the lesson and writes its output to the console.
 func main() {
+	if err := Run(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// Run builds arrays, slices and maps, appends, copies and deletes, and ends
+// with a side-by-side comparison of the three.
+func Run(out io.Writer) error {
+	w := &errWriter{w: out}
 	// SECTION 1: Arrays
-	fmt.Println("SECTION 1: Arrays")
+	fmt.Fprintln(w, "SECTION 1: Arrays")
 	// Arrays have a fixed size and store elements of the same type.
 	var arr [5]int // Declare an array of integers with a fixed size of 5.
 	arr[0] = 10    // Assign a value to the first index.
 	arr[1] = 20    // Assign a value to the second index.
 
-	fmt.Println("Array:", arr)
-	fmt.Printf("Length of array: %d\n", len(arr)) // Get the length of the array.
+	fmt.Fprintln(w, "Array:", arr)
+	fmt.Fprintf(w, "Length of array: %d\n", len(arr)) // Get the length of the array.
 
 	// Declare and initialize an array.
 	initializedArray := [3]int{1, 2, 3}
-	fmt.Println("Initialized Array:", initializedArray)
+	fmt.Fprintln(w, "Initialized Array:", initializedArray)
 
 	// Loop through an array.
 	for i, value := range initializedArray {
-		fmt.Printf("Index: %d, Value: %d\n", i, value)
+		fmt.Fprintf(w, "Index: %d, Value: %d\n", i, value)
 	}
 	// Practice: Create an array of strings and print each character in reverse order.
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 2: Slices
-	fmt.Println("SECTION 2: Slices")
+	fmt.Fprintln(w, "SECTION 2: Slices")
 	// Slices are dynamic and can grow or shrink. They are built on top of arrays.
 
 	slice := []int{10, 20, 30} // Declare and initialize a slice.
-	fmt.Println("Slice:", slice)
-	fmt.Printf("Length of slice: %d, Capacity of slice: %d\n", len(slice), cap(slice))
+	fmt.Fprintln(w, "Slice:", slice)
+	fmt.Fprintf(w, "Length of slice: %d, Capacity of slice: %d\n", len(slice), cap(slice))
 
 	// Append elements to a slice.
 	slice = append(slice, 40, 50)
-	fmt.Println("After appending elements:", slice)
+	fmt.Fprintln(w, "After appending elements:", slice)
 
 	// Create a slice from an array.
 	array := [5]int{1, 2, 3, 4, 5}
 	sliceFromArray := array[1:4] // Create a slice of elements from index 1 to 3.
-	fmt.Println("Slice from array:", sliceFromArray)
+	fmt.Fprintln(w, "Slice from array:", sliceFromArray)
 
 	// Modifying a slice modifies the underlying array.
 	sliceFromArray[0] = 99
-	fmt.Println("Modified Slice:", sliceFromArray)
-	fmt.Println("Underlying Array:", array)
+	fmt.Fprintln(w, "Modified Slice:", sliceFromArray)
+	fmt.Fprintln(w, "Underlying Array:", array)
 
 	// Copy slices.
 	newSlice := make([]int, len(slice))
 	copy(newSlice, slice) // Copy contents of one slice to another.
-	fmt.Println("Copied Slice:", newSlice)
+	fmt.Fprintln(w, "Copied Slice:", newSlice)
 	// Practice: Use append and copy to merge two slices.
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 3: Maps
-	fmt.Println("SECTION 3: Maps")
+	fmt.Fprintln(w, "SECTION 3: Maps")
 	// Maps are key-value pairs.
 
 	myMap := make(map[string]int) // Declare and initialize an empty map.
 	myMap["Alice"] = 25           // Add a key-value pair.
 	myMap["Bob"] = 30             // Add another key-value pair.
-	fmt.Println("Map:", myMap)
+	fmt.Fprintln(w, "Map:", myMap)
 
 	// Access a value by its key.
-	fmt.Printf("Age of Alice: %d\n", myMap["Alice"])
+	fmt.Fprintf(w, "Age of Alice: %d\n", myMap["Alice"])
 
 	// Check if a key exists.
 	value, exists := myMap["Charlie"]
 	if exists {
-		fmt.Printf("Age of Charlie: %d\n", value)
+		fmt.Fprintf(w, "Age of Charlie: %d\n", value)
 	} else {
-		fmt.Println("Key 'Charlie' does not exist in the map.")
+		fmt.Fprintln(w, "Key 'Charlie' does not exist in the map.")
 	}
 
 	// Delete a key-value pair.
 	delete(myMap, "Bob")
-	fmt.Println("Map after deleting 'Bob':", myMap)
+	fmt.Fprintln(w, "Map after deleting 'Bob':", myMap)
 
 	// Iterate over a map.
 	for key, value := range myMap {
-		fmt.Printf("Key: %s, Value: %d\n", key, value)
+		fmt.Fprintf(w, "Key: %s, Value: %d\n", key, value)
 	}
 
 	// Nested maps.
@@ -89,34 +104,51 @@ func main() {
 		"GroupA": {"Alice": 25, "Bob": 30},
 		"GroupB": {"Charlie": 35},
 	}
-	fmt.Println("Nested Map:", nestedMap)
+	fmt.Fprintln(w, "Nested Map:", nestedMap)
 	// Practice: Create a map of countries with nested maps of cities and populations.
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 4: Advanced Slice Operations
-	fmt.Println("SECTION 4: Advanced Slice Operations")
+	fmt.Fprintln(w, "SECTION 4: Advanced Slice Operations")
 	// Reslicing
 	advancedSlice := []int{1, 2, 3, 4, 5}
-	fmt.Println("Original Slice:", advancedSlice)
-	fmt.Println("Resliced (1:3):", advancedSlice[1:3])
-	fmt.Println("Resliced (2:):", advancedSlice[2:])
-	fmt.Println("Resliced (:3):", advancedSlice[:3])
+	fmt.Fprintln(w, "Original Slice:", advancedSlice)
+	fmt.Fprintln(w, "Resliced (1:3):", advancedSlice[1:3])
+	fmt.Fprintln(w, "Resliced (2:):", advancedSlice[2:])
+	fmt.Fprintln(w, "Resliced (:3):", advancedSlice[:3])
 
 	// Appending beyond capacity
 	extendedSlice := make([]int, 2, 3)
 	extendedSlice[0] = 10
 	extendedSlice[1] = 20
-	fmt.Printf("Before appending beyond capacity: %v (len: %d, cap: %d)\n", extendedSlice, len(extendedSlice), cap(extendedSlice))
+	fmt.Fprintf(w, "Before appending beyond capacity: %v (len: %d, cap: %d)\n", extendedSlice, len(extendedSlice), cap(extendedSlice))
 	extendedSlice = append(extendedSlice, 30, 40) // Appends beyond initial capacity.
-	fmt.Printf("After appending beyond capacity: %v (len: %d, cap: %d)\n", extendedSlice, len(extendedSlice), cap(extendedSlice))
+	fmt.Fprintf(w, "After appending beyond capacity: %v (len: %d, cap: %d)\n", extendedSlice, len(extendedSlice), cap(extendedSlice))
 	// Practice: Create a slice, append till it doubles its capacity and print it at each step.
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 5: Comparison Between Arrays, Slices, and Maps
-	fmt.Println("SECTION 5: Comparison")
-	fmt.Println("1. Arrays are fixed in size, while slices are dynamic.")
-	fmt.Println("2. Arrays cannot be resized, but slices can grow/shrink.")
-	fmt.Println("3. Maps are unordered collections of key-value pairs, while arrays/slices are ordered.")
-	fmt.Println("4. Slices and maps are reference types; arrays are value types.")
+	fmt.Fprintln(w, "SECTION 5: Comparison")
+	fmt.Fprintln(w, "1. Arrays are fixed in size, while slices are dynamic.")
+	fmt.Fprintln(w, "2. Arrays cannot be resized, but slices can grow/shrink.")
+	fmt.Fprintln(w, "3. Maps are unordered collections of key-value pairs, while arrays/slices are ordered.")
+	fmt.Fprintln(w, "4. Slices and maps are reference types; arrays are value types.")
 	// Practice: Summarize pros and cons of each data structure in your own words.
+	return w.err
+}
+
+// errWriter hands each byte slice to w until a write fails, then rejects
+// every later slice with that error, which Run returns at the end.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	n, err := ew.w.Write(p)
+	ew.err = err
+	return n, err
 }
@@ -2,7 +2,12 @@
 // It covers struct definition, initialization, embedding, methods, and advanced usage.
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
 
 // Person defines a structure with fields Name and Age.
 type Person struct {
@@ -30,10 +35,27 @@ type Employee struct {
 	Department string  // Department of the employee
 }
 
-// DisplayDetails is a method of Employee that prints detailed information.
-func (e Employee) DisplayDetails() {
-	fmt.Printf("Name: %s\nAge: %d\nPosition: %s\nSalary: %.2f\nDepartment: %s\n",
-		e.Name, e.Age, e.Position, e.Salary, e.Department)
+// DisplayDetails is a method of Employee that prints detailed information to w.
+func (e Employee) DisplayDetails(w io.Writer) {
+	fmt.Fprintf(w, "Name: %s\nAge: %d\nPosition: %s\nSalary: %s\nDepartment: %s\n",
+		e.Name, e.Age, e.Position, formatSalary(e.Salary), e.Department)
+}
+
+// formatSalary writes an amount in US dollars with thousands separators,
+// e.g. "$75,000.50". The Number Format project (4_Projects/9_Number_Format)
+// does the same for other locales, currencies and rounding modes.
+func formatSalary(amount float64) string {
+	cents := int64(math.Round(amount * 100))
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	digits := fmt.Sprint(cents / 100)
+	grouped := digits[:(len(digits)-1)%3+1]
+	for i := len(grouped); i < len(digits); i += 3 {
+		grouped += "," + digits[i:i+3]
+	}
+	return fmt.Sprintf("%s$%s.%02d", sign, grouped, cents%100)
 }
 
 // Company defines a struct with nested fields.
@@ -47,35 +69,46 @@ func (c *Company) AddEmployee(e Employee) {
 	c.Employees = append(c.Employees, e)
 }
 
+// main runs the lesson and writes its output to the console.
 func main() {
+	if err := Run(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// Run creates structs, calls value and pointer receiver methods on them, and
+// shows embedding, anonymous and nested structs and struct comparison.
+func Run(out io.Writer) error {
+	w := &errWriter{w: out}
 	// SECTION 1: Basic Struct Usage
-	fmt.Println("SECTION 1: Basic Struct Usage")
+	fmt.Fprintln(w, "SECTION 1: Basic Struct Usage")
 	person := Person{Name: "Alice", Age: 25} // Initializing a struct with field names
-	fmt.Println("Person Name:", person.Name)
-	fmt.Println("Person Age:", person.Age)
-	fmt.Println("Greeting:", person.Greet()) // Call a method on the struct
-	fmt.Println()
+	fmt.Fprintln(w, "Person Name:", person.Name)
+	fmt.Fprintln(w, "Person Age:", person.Age)
+	fmt.Fprintln(w, "Greeting:", person.Greet()) // Call a method on the struct
+	fmt.Fprintln(w)
 
 	// SECTION 2: Pointer Receiver and Modifying Structs
-	fmt.Println("SECTION 2: Pointer Receiver and Modifying Structs")
+	fmt.Fprintln(w, "SECTION 2: Pointer Receiver and Modifying Structs")
 	person.UpdateAge(30) // Using a pointer receiver to modify the struct
-	fmt.Println("Updated Age:", person.Age)
-	fmt.Println()
+	fmt.Fprintln(w, "Updated Age:", person.Age)
+	fmt.Fprintln(w)
 
 	// SECTION 3: Struct Embedding
-	fmt.Println("SECTION 3: Struct Embedding")
+	fmt.Fprintln(w, "SECTION 3: Struct Embedding")
 	employee := Employee{
 		Person:     Person{Name: "Bob", Age: 35},
 		Position:   "Software Engineer",
 		Salary:     75000.50,
 		Department: "IT",
 	}
-	fmt.Println("Employee Details:")
-	employee.DisplayDetails()
-	fmt.Println()
+	fmt.Fprintln(w, "Employee Details:")
+	employee.DisplayDetails(w)
+	fmt.Fprintln(w)
 
 	// SECTION 4: Anonymous Structs
-	fmt.Println("SECTION 4: Anonymous Structs")
+	fmt.Fprintln(w, "SECTION 4: Anonymous Structs")
 	anonymous := struct {
 		Name  string
 		Email string
@@ -83,11 +116,11 @@ func main() {
 		Name:  "Charlie",
 		Email: "charlie@example.com",
 	}
-	fmt.Printf("Anonymous Struct - Name: %s, Email: %s\n", anonymous.Name, anonymous.Email)
-	fmt.Println()
+	fmt.Fprintf(w, "Anonymous Struct - Name: %s, Email: %s\n", anonymous.Name, anonymous.Email)
+	fmt.Fprintln(w)
 
 	// SECTION 5: Nested Structs and Composition
-	fmt.Println("SECTION 5: Nested Structs and Composition")
+	fmt.Fprintln(w, "SECTION 5: Nested Structs and Composition")
 	company := Company{Name: "Tech Corp"}
 	company.AddEmployee(employee)
 	company.AddEmployee(Employee{
@@ -97,29 +130,46 @@ func main() {
 		Department: "Analytics",
 	})
 
-	fmt.Printf("Company: %s\n", company.Name)
-	fmt.Println("Employees:")
+	fmt.Fprintf(w, "Company: %s\n", company.Name)
+	fmt.Fprintln(w, "Employees:")
 	for _, emp := range company.Employees {
-		emp.DisplayDetails()
-		fmt.Println()
+		emp.DisplayDetails(w)
+		fmt.Fprintln(w)
 	}
 
 	// SECTION 6: Comparison of Structs
-	fmt.Println("SECTION 6: Comparison of Structs")
+	fmt.Fprintln(w, "SECTION 6: Comparison of Structs")
 	person1 := Person{Name: "John", Age: 40}
 	person2 := Person{Name: "John", Age: 40}
 	person3 := Person{Name: "Jane", Age: 40}
-	fmt.Println("person1 == person2:", person1 == person2) // True if all fields match
-	fmt.Println("person1 == person3:", person1 == person3) // False due to different Name
-	fmt.Println()
+	fmt.Fprintln(w, "person1 == person2:", person1 == person2) // True if all fields match
+	fmt.Fprintln(w, "person1 == person3:", person1 == person3) // False due to different Name
+	fmt.Fprintln(w)
 
 	// SECTION 7: Advanced Struct Concepts
-	fmt.Println("SECTION 7: Advanced Struct Concepts")
+	fmt.Fprintln(w, "SECTION 7: Advanced Struct Concepts")
 	// Zero Value of a Struct
 	var defaultPerson Person
-	fmt.Printf("Default Person: Name: %q, Age: %d\n", defaultPerson.Name, defaultPerson.Age)
+	fmt.Fprintf(w, "Default Person: Name: %q, Age: %d\n", defaultPerson.Name, defaultPerson.Age)
 
 	// Creating a pointer to a struct
 	personPointer := &Person{Name: "Diana", Age: 22}
-	fmt.Printf("Pointer to Struct - Name: %s, Age: %d\n", personPointer.Name, personPointer.Age)
+	fmt.Fprintf(w, "Pointer to Struct - Name: %s, Age: %d\n", personPointer.Name, personPointer.Age)
+	return w.err
+}
+
+// errWriter is a struct with a pointer-receiver Write method, like UpdateAge:
+// the error it records has to land in the errWriter Run holds, not in a copy.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	n, err := ew.w.Write(p)
+	ew.err = err
+	return n, err
 }
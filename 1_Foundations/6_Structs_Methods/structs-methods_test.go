@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestFormatSalary(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   string
+	}{
+		{75000.50, "$75,000.50"},
+		{90000, "$90,000.00"},
+		{0, "$0.00"},
+		{999.999, "$1,000.00"},
+		{1234567.891, "$1,234,567.89"},
+		{100, "$100.00"},
+		{-1500.5, "-$1,500.50"},
+		{-0.001, "$0.00"}, // Rounds to zero cents: no minus sign.
+	}
+	for _, tt := range tests {
+		if got := formatSalary(tt.amount); got != tt.want {
+			t.Errorf("formatSalary(%v) = %q, want %q", tt.amount, got, tt.want)
+		}
+	}
+}
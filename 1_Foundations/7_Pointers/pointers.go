@@ -11,88 +11,120 @@
 // - No pointer arithmetic in Go (design decision)
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"os"
+)
 
+// main runs the lesson and writes its output to the console.
 func main() {
+	if err := Run(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// Run takes addresses, passes and dereferences pointers to values, structs,
+// pointers and arrays, and shows how a nil pointer is checked before use.
+func Run(out io.Writer) error {
+	w := &errWriter{w: out}
 	// SECTION 1: Pointer Basics
-	fmt.Println("SECTION 1: Pointer Basics")
+	fmt.Fprintln(w, "SECTION 1: Pointer Basics")
 	var x int = 42
 	var ptr *int = &x
-	fmt.Printf("Value of x: %d, Address of x: %p\n", x, &x)
-	fmt.Printf("Value of ptr: %p, Value at ptr: %d\n", ptr, *ptr)
+	fmt.Fprintf(w, "Value of x: %d, Address of x: %p\n", x, &x)
+	fmt.Fprintf(w, "Value of ptr: %p, Value at ptr: %d\n", ptr, *ptr)
 	*ptr = 100
-	fmt.Println("Updated value of x:", x)
-	fmt.Println()
+	fmt.Fprintln(w, "Updated value of x:", x)
+	fmt.Fprintln(w)
 
 	// SECTION 2: Pointers and Functions
-	fmt.Println("SECTION 2: Pointers and Functions")
+	fmt.Fprintln(w, "SECTION 2: Pointers and Functions")
 	num := 10
-	fmt.Println("Before increment:", num)
+	fmt.Fprintln(w, "Before increment:", num)
 	increment(&num)
-	fmt.Println("After increment:", num)
+	fmt.Fprintln(w, "After increment:", num)
 	newPtr := createPointer(20)
-	fmt.Printf("Returned pointer value: %d\n", *newPtr)
-	fmt.Println()
+	fmt.Fprintf(w, "Returned pointer value: %d\n", *newPtr)
+	fmt.Fprintln(w)
 
 	// SECTION 3: Pointers and Structs
-	fmt.Println("SECTION 3: Pointers and Structs")
+	fmt.Fprintln(w, "SECTION 3: Pointers and Structs")
 	type Person struct {
 		name string
 		age  int
 	}
 	person := Person{name: "Alice", age: 25}
 	personPtr := &person
-	fmt.Printf("Original Struct: %+v\n", person)
+	fmt.Fprintf(w, "Original Struct: %+v\n", person)
 	personPtr.age = 30
-	fmt.Printf("Updated Struct: %+v\n", person)
-	fmt.Println()
+	fmt.Fprintf(w, "Updated Struct: %+v\n", person)
+	fmt.Fprintln(w)
 
 	// SECTION 4: Pointer to Pointer
-	fmt.Println("SECTION 4: Pointer to Pointer")
+	fmt.Fprintln(w, "SECTION 4: Pointer to Pointer")
 	a := 5
 	p1 := &a
 	p2 := &p1
-	fmt.Printf("Value of a: %d, Address of a: %p\n", a, &a)
-	fmt.Printf("Value of p1: %p, Value at p1: %d\n", p1, *p1)
-	fmt.Printf("Value of p2: %p, Value at p2: %p, Value at *p2: %d\n", p2, *p2, **p2)
-	fmt.Println()
+	fmt.Fprintf(w, "Value of a: %d, Address of a: %p\n", a, &a)
+	fmt.Fprintf(w, "Value of p1: %p, Value at p1: %d\n", p1, *p1)
+	fmt.Fprintf(w, "Value of p2: %p, Value at p2: %p, Value at *p2: %d\n", p2, *p2, **p2)
+	fmt.Fprintln(w)
 
 	// SECTION 5: Nil Pointers
-	fmt.Println("SECTION 5: Nil Pointers")
+	fmt.Fprintln(w, "SECTION 5: Nil Pointers")
 	var nilPtr *int
-	fmt.Println("Value of nilPtr:", nilPtr)
+	fmt.Fprintln(w, "Value of nilPtr:", nilPtr)
 	if nilPtr == nil {
-		fmt.Println("nilPtr is nil")
+		fmt.Fprintln(w, "nilPtr is nil")
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 6: Pointers and Arrays
-	fmt.Println("SECTION 6: Pointers and Arrays")
+	fmt.Fprintln(w, "SECTION 6: Pointers and Arrays")
 	array := [3]int{10, 20, 30}
 	arrayPtr := &array
-	fmt.Printf("Original Array: %v\n", array)
+	fmt.Fprintf(w, "Original Array: %v\n", array)
 	arrayPtr[1] = 99
-	fmt.Printf("Updated Array: %v\n", array)
-	fmt.Println()
+	fmt.Fprintf(w, "Updated Array: %v\n", array)
+	fmt.Fprintln(w)
 
 	// SECTION 7: Advanced Pointer Concepts
-	fmt.Println("SECTION 7: Advanced Pointer Concepts")
+	fmt.Fprintln(w, "SECTION 7: Advanced Pointer Concepts")
 	slice := []int{1, 2, 3, 4, 5}
 	ptrToSlice := &slice[0]
-	fmt.Printf("First element of slice: %d, Address: %p\n", *ptrToSlice, ptrToSlice)
+	fmt.Fprintf(w, "First element of slice: %d, Address: %p\n", *ptrToSlice, ptrToSlice)
 	for i := range slice {
-		fmt.Printf("Element %d: %d, Address: %p\n", i, slice[i], &slice[i])
+		fmt.Fprintf(w, "Element %d: %d, Address: %p\n", i, slice[i], &slice[i])
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 8: Comparison and Key Points
-	fmt.Println("SECTION 8: Comparison and Key Points")
-	fmt.Println("1. Pointers allow efficient modification without copying values.")
-	fmt.Println("2. Use & to get the address of a variable.")
-	fmt.Println("3. Use * to dereference a pointer and access the value.")
-	fmt.Println("4. Nil pointers must be checked before dereferencing.")
-	fmt.Println("5. Pointers are safe in Go due to the lack of pointer arithmetic.")
-	fmt.Println()
+	fmt.Fprintln(w, "SECTION 8: Comparison and Key Points")
+	fmt.Fprintln(w, "1. Pointers allow efficient modification without copying values.")
+	fmt.Fprintln(w, "2. Use & to get the address of a variable.")
+	fmt.Fprintln(w, "3. Use * to dereference a pointer and access the value.")
+	fmt.Fprintln(w, "4. Nil pointers must be checked before dereferencing.")
+	fmt.Fprintln(w, "5. Pointers are safe in Go due to the lack of pointer arithmetic.")
+	fmt.Fprintln(w)
+	return w.err
+}
+
+// errWriter is used through a pointer, &errWriter{w: out}, so the first
+// error Write stores in ew.err is still there when Run reads w.err.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	n, err := ew.w.Write(p)
+	ew.err = err
+	return n, err
 }
 
 // increment modifies a value using a pointer.
@@ -4,60 +4,93 @@ package main
 import (
 	"errors"
 	"fmt"
-	"log"
+	"io"
+	"os"
 )
 
+// main runs the lesson and writes its output to the console.
 func main() {
+	if err := Run(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// Run demonstrates each way of creating, wrapping and inspecting errors.
+// Section 6 ends with fetchMetadata failing on purpose, so Run returns that
+// error, wrapped once per layer, for main to report.
+func Run(out io.Writer) error {
+	w := &errWriter{w: out}
 	// SECTION 1: Basic Error Handling
-	fmt.Println("SECTION 1: Basic Error Handling")
+	fmt.Fprintln(w, "SECTION 1: Basic Error Handling")
 	result, err := divide(10, 0)
 	if err != nil {
-		fmt.Println("Error:", err)
+		fmt.Fprintln(w, "Error:", err)
 	} else {
-		fmt.Println("Result:", result)
+		fmt.Fprintln(w, "Result:", result)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 2: Creating and Wrapping Errors
-	fmt.Println("SECTION 2: Creating and Wrapping Errors")
+	fmt.Fprintln(w, "SECTION 2: Creating and Wrapping Errors")
 	err = readConfig()
 	if err != nil {
 		wrappedErr := fmt.Errorf("loadApp failed: %w", err)
-		fmt.Println("Wrapped Error:", wrappedErr)
+		fmt.Fprintln(w, "Wrapped Error:", wrappedErr)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 3: Sentinel Errors
-	fmt.Println("SECTION 3: Sentinel Errors")
+	fmt.Fprintln(w, "SECTION 3: Sentinel Errors")
 	err = findUser(42)
 	if errors.Is(err, ErrNotFound) {
-		fmt.Println("User not found (sentinel error)")
+		fmt.Fprintln(w, "User not found (sentinel error)")
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 4: Custom Error Types
-	fmt.Println("SECTION 4: Custom Error Types")
+	fmt.Fprintln(w, "SECTION 4: Custom Error Types")
 	err = fetch()
 	if httpErr, ok := err.(*HTTPError); ok {
-		fmt.Printf("Custom Error - Status code: %d, Message: %s\n", httpErr.Code, httpErr.Message)
+		fmt.Fprintf(w, "Custom Error - Status code: %d, Message: %s\n", httpErr.Code, httpErr.Message)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 5: Anti-Patterns (for demonstration only)
-	fmt.Println("SECTION 5: Anti-Patterns")
+	fmt.Fprintln(w, "SECTION 5: Anti-Patterns")
 	// Don't do this: ignoring errors
 	_, err = divide(1, 0)
 	// _ = err // BAD: error ignored
 	if err != nil {
-		fmt.Println("Handled error instead of ignoring:", err)
+		fmt.Fprintln(w, "Handled error instead of ignoring:", err)
 	}
-	fmt.Println()
+	fmt.Fprintln(w)
 
 	// SECTION 6: Real-World Example: Error Propagation
-	fmt.Println("SECTION 6: Real-World Example: Error Propagation")
+	fmt.Fprintln(w, "SECTION 6: Real-World Example: Error Propagation")
+	// Return the error instead of calling log.Fatal, so the caller (main) decides how to report it.
 	if err := process(); err != nil {
-		log.Fatalf("process failed: %v", err)
+		return fmt.Errorf("process failed: %w", err)
 	}
+	return w.err
+}
+
+// errWriter wraps a writer and remembers the first error it returns; after
+// that every write fails with the same error without reaching w. It is the
+// "errors are values" pattern: instead of checking each of dozens of
+// fmt.Fprintln calls, Run writes freely and checks err once at the end.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	n, err := ew.w.Write(p)
+	ew.err = err
+	return n, err
 }
 
 // divide returns the result of a / b, or an error if b is zero.
@@ -122,4 +155,4 @@ func process() error {
 		return fmt.Errorf("db save failed: %w", err)
 	}
 	return nil
-} 
\ No newline at end of file
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+type editKind byte
+
+const (
+	editEqual  editKind = ' '
+	editDelete editKind = '-'
+	editInsert editKind = '+'
+)
+
+type edit struct {
+	kind editKind
+	line string
+	a, b int // Line indexes in the old and new text.
+}
+
+// UnifiedDiff compares two texts line by line and returns a unified diff, or
+// "" if they are equal. Rendered configs are small, so the quadratic
+// longest-common-subsequence table is fine.
+func UnifiedDiff(oldName, newName, oldText, newText string) string {
+	a, b := splitLines(oldText), splitLines(newText)
+	edits := diffLines(a, b)
+
+	var out strings.Builder
+	for start := 0; start < len(edits); {
+		// Find the next change and the hunk of changes within 2*context of each other.
+		first := start
+		for first < len(edits) && edits[first].kind == editEqual {
+			first++
+		}
+		if first == len(edits) {
+			break
+		}
+		last := first
+		for i := first; i < len(edits); i++ {
+			if edits[i].kind != editEqual {
+				last = i
+			} else if i-last > 2*diffContext {
+				break
+			}
+		}
+		lo := maxInt(first-diffContext, start)
+		hi := minInt(last+diffContext+1, len(edits))
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		writeHunk(&out, edits[lo:hi])
+		start = hi
+	}
+	return out.String()
+}
+
+func writeHunk(out *strings.Builder, hunk []edit) {
+	oldStart, newStart, oldCount, newCount := hunk[0].a+1, hunk[0].b+1, 0, 0
+	for _, e := range hunk {
+		if e.kind != editInsert {
+			oldCount++
+		}
+		if e.kind != editDelete {
+			newCount++
+		}
+	}
+	// An empty range starts at the line before it, as in diff -u.
+	if oldCount == 0 {
+		oldStart--
+	}
+	if newCount == 0 {
+		newStart--
+	}
+	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+	for _, e := range hunk {
+		fmt.Fprintf(out, "%c%s\n", e.kind, e.line)
+	}
+}
+
+// diffLines returns the edit script turning a into b.
+func diffLines(a, b []string) []edit {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = maxInt(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var edits []edit
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{editEqual, a[i], i, j})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{editDelete, a[i], i, j})
+			i++
+		default:
+			edits = append(edits, edit{editInsert, b[j], i, j})
+			j++
+		}
+	}
+	return edits
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,242 @@
+// Package main renders configuration files from templates and per-environment
+// value sets, growing the "raw config templates for cloud deployment" idea from
+// SECTION 8 of the Variables and Constants lesson into a small tool.
+//
+// Values live in profiles/: base.json is shared, and dev.json, staging.json and
+// prod.json override it key by key. Templates live in templates/ and use
+// text/template with a few helpers (see Renderer). config.json renders the
+// lesson's {"env": ..., "debug": ...} document in the shape the Config Loader
+// project reads.
+//
+//	go run main.go diff.go render.go values.go envs                 # environments with a profile
+//	go run main.go diff.go render.go values.go render -env prod     # print every rendered file
+//	go run main.go diff.go render.go values.go render -env dev -out build/dev
+//	go run main.go diff.go render.go values.go values -env staging  # the merged values a template sees
+//	go run main.go diff.go render.go values.go diff dev prod        # unified diff of the rendered output
+//	go test *.go                                                    # merging, required values and the diff output
+//
+// Use -templates and -profiles to read directories instead of the built-in set.
+// Exit codes: 0 on success, 1 on runtime errors (including missing required
+// values), 2 on invalid usage.
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// builtin holds the example templates and profiles so the demo runs from anywhere.
+//
+//go:embed templates/*.tmpl profiles/*.json
+var builtin embed.FS
+
+// usageError marks errors caused by invalid command-line input (exit code 2).
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usageErrorf(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("cfgtmpl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	templateDir := flags.String("templates", "", "directory of *.tmpl files (default: built-in templates)")
+	profileDir := flags.String("profiles", "", "directory of <env>.json value sets (default: built-in profiles)")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: cfgtmpl [-templates dir] [-profiles dir] envs|render|values|diff [args]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	r := Renderer{Templates: subFS("templates"), Profiles: subFS("profiles")}
+	if *templateDir != "" {
+		r.Templates = os.DirFS(*templateDir)
+	}
+	if *profileDir != "" {
+		r.Profiles = os.DirFS(*profileDir)
+	}
+
+	err := dispatch(r, flags.Arg(0), flags.Args()[1:], stdout, stderr)
+	var usageErr *usageError
+	switch {
+	case errors.As(err, &usageErr), errors.Is(err, ErrUnknownEnv):
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	case err != nil:
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
+
+func subFS(dir string) fs.FS {
+	sub, err := fs.Sub(builtin, dir)
+	if err != nil {
+		panic(err) // The embedded directories are fixed at compile time.
+	}
+	return sub
+}
+
+func dispatch(r Renderer, cmd string, args []string, stdout, stderr io.Writer) error {
+	switch cmd {
+	case "envs":
+		if len(args) > 0 {
+			return usageErrorf("envs takes no arguments")
+		}
+		envs, err := Envs(r.Profiles)
+		if err != nil {
+			return err
+		}
+		for _, env := range envs {
+			fmt.Fprintln(stdout, env)
+		}
+		return nil
+	case "render":
+		return cmdRender(r, args, stdout, stderr)
+	case "values":
+		return cmdValues(r, args, stdout, stderr)
+	case "diff":
+		return cmdDiff(r, args, stdout)
+	default:
+		return usageErrorf("unknown command %q", cmd)
+	}
+}
+
+func cmdRender(r Renderer, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	env := flags.String("env", "dev", "environment to render")
+	out := flags.String("out", "", "write files to this directory instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf("%v", err)
+	}
+	if err := noArgs(flags); err != nil {
+		return err
+	}
+
+	files, err := r.Render(*env)
+	if err != nil {
+		return err
+	}
+	for i, f := range files {
+		if *out == "" {
+			if i > 0 {
+				fmt.Fprintln(stdout)
+			}
+			fmt.Fprintf(stdout, "==> %s <==\n%s", f.Name, f.Content)
+			continue
+		}
+		if err := os.MkdirAll(*out, 0o755); err != nil {
+			return err
+		}
+		path := filepath.Join(*out, f.Name)
+		if err := os.WriteFile(path, []byte(f.Content), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, "wrote", path)
+	}
+	return nil
+}
+
+func cmdValues(r Renderer, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("values", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	env := flags.String("env", "dev", "environment whose merged values to print")
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf("%v", err)
+	}
+	if err := noArgs(flags); err != nil {
+		return err
+	}
+
+	values, err := LoadValues(r.Profiles, *env)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "\t")
+	return enc.Encode(values)
+}
+
+// noArgs rejects positional arguments after a subcommand's flags, so that
+// "render prod" fails instead of quietly rendering the default environment.
+func noArgs(flags *flag.FlagSet) error {
+	if flags.NArg() == 0 {
+		return nil
+	}
+	return usageErrorf("%s: unexpected argument %q (use -env to choose the environment)", flags.Name(), flags.Arg(0))
+}
+
+// cmdDiff renders two environments and prints a unified diff per file.
+func cmdDiff(r Renderer, args []string, stdout io.Writer) error {
+	if len(args) != 2 {
+		return usageErrorf("diff needs exactly two environments")
+	}
+	from, to := args[0], args[1]
+	oldFiles, err := r.Render(from)
+	if err != nil {
+		return err
+	}
+	newFiles, err := r.Render(to)
+	if err != nil {
+		return err
+	}
+
+	// Every template renders in both environments, but keep the lookup general.
+	contents := map[string][2]string{}
+	for _, f := range oldFiles {
+		c := contents[f.Name]
+		c[0] = f.Content
+		contents[f.Name] = c
+	}
+	for _, f := range newFiles {
+		c := contents[f.Name]
+		c[1] = f.Content
+		contents[f.Name] = c
+	}
+	names := make([]string, 0, len(contents))
+	for name := range contents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	same := true
+	for _, name := range names {
+		c := contents[name]
+		if d := UnifiedDiff(from+"/"+name, to+"/"+name, c[0], c[1]); d != "" {
+			fmt.Fprint(stdout, d)
+			same = false
+		}
+	}
+	if same {
+		fmt.Fprintf(stdout, "%s and %s render identically\n", from, to)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// File is one rendered output, named after its template without ".tmpl".
+type File struct {
+	Name    string
+	Content string
+}
+
+// MissingValuesError lists every required value an environment lacks, so a
+// profile can be fixed in one pass instead of one error per run.
+type MissingValuesError struct {
+	Env   string
+	Paths []string // Sorted dotted paths, e.g. "database.url".
+}
+
+func (e *MissingValuesError) Error() string {
+	return fmt.Sprintf("environment %q is missing required values: %s", e.Env, strings.Join(e.Paths, ", "))
+}
+
+// Renderer renders every template in Templates with the values of one environment.
+//
+// Templates are *.tmpl files; those starting with "_" only hold partials
+// (define blocks) and produce no output. Besides the text/template builtins,
+// templates can call:
+//
+//	required "a.b"        the value at a.b; reported as missing if unset
+//	optional "a.b" x      the value at a.b, or x if unset
+//	include "name" data   render a partial to a string, for use in pipelines
+//	indent n s            prefix every line but the first with n tabs
+//	quote s               s as a JSON string literal
+//	toJSON v              v as compact JSON
+//
+// Plain field access such as .server.port fails on missing keys rather than
+// printing "<no value>".
+type Renderer struct {
+	Templates fs.FS
+	Profiles  fs.FS
+}
+
+// Render renders all templates for env in name order.
+func (r Renderer) Render(env string) ([]File, error) {
+	values, err := LoadValues(r.Profiles, env)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := map[string]bool{}
+	tmpl := template.New("").Option("missingkey=error")
+	tmpl.Funcs(template.FuncMap{
+		"required": func(path string) any {
+			v, ok := values.Lookup(path)
+			if !ok {
+				missing[path] = true
+				return ""
+			}
+			return v
+		},
+		"optional": func(path string, fallback any) any {
+			if v, ok := values.Lookup(path); ok {
+				return v
+			}
+			return fallback
+		},
+		"include": func(name string, data any) (string, error) {
+			var b strings.Builder
+			err := tmpl.ExecuteTemplate(&b, name, data)
+			return b.String(), err
+		},
+		"indent": indent,
+		"quote":  quote,
+		"toJSON": toJSON,
+	})
+	if _, err := tmpl.ParseFS(r.Templates, "*.tmpl"); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, t := range tmpl.Templates() {
+		if strings.HasSuffix(t.Name(), ".tmpl") && !strings.HasPrefix(t.Name(), "_") {
+			names = append(names, t.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var files []File
+	for _, name := range names {
+		var b strings.Builder
+		if err := tmpl.ExecuteTemplate(&b, name, values); err != nil {
+			return nil, err
+		}
+		files = append(files, File{Name: strings.TrimSuffix(name, ".tmpl"), Content: b.String()})
+	}
+
+	if len(missing) > 0 {
+		paths := make([]string, 0, len(missing))
+		for p := range missing {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		return nil, &MissingValuesError{Env: env, Paths: paths}
+	}
+	return files, nil
+}
+
+func indent(n int, s string) string {
+	return strings.ReplaceAll(s, "\n", "\n"+strings.Repeat("\t", n))
+}
+
+func quote(v any) (string, error) {
+	return toJSON(fmt.Sprint(v))
+}
+
+func toJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// renderer builds a Renderer over in-memory templates and profiles.
+func renderer(templates, profileFiles map[string]string) Renderer {
+	tfs := fstest.MapFS{}
+	for name, data := range templates {
+		tfs[name] = &fstest.MapFile{Data: []byte(data)}
+	}
+	return Renderer{Templates: tfs, Profiles: profiles(profileFiles)}
+}
+
+func TestRenderHelpers(t *testing.T) {
+	r := renderer(map[string]string{
+		"_helpers.tmpl": `{{define "addr"}}{{.host}}:{{.port}}{{end}}`,
+		"app.conf.tmpl": `env={{.env}}
+addr={{include "addr" .server | quote}}
+url={{required "database.url"}}
+level={{optional "log.level" "info"}}
+tags={{toJSON .tags}}
+block={{indent 1 "a\nb"}}
+`,
+	}, map[string]string{
+		"base.json": `{"server": {"host": "localhost", "port": 8080}, "tags": ["x", "y"]}`,
+		"dev.json":  `{"database": {"url": "postgres://dev"}}`,
+	})
+	files, err := r.Render("dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []File{{Name: "app.conf", Content: `env=dev
+addr="localhost:8080"
+url=postgres://dev
+level=info
+tags=["x","y"]
+block=a
+	b
+`}}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("Render =\n%q\nwant\n%q", files, want)
+	}
+}
+
+func TestRenderMissingValues(t *testing.T) {
+	r := renderer(map[string]string{
+		"a.tmpl": `{{required "database.url"}} {{required "server.port"}}`,
+		"b.tmpl": `{{required "api.key"}} {{required "database.url"}} {{optional "log.level" "info"}}`,
+	}, map[string]string{
+		"base.json": `{}`,
+		"prod.json": `{"server": {"port": 443}}`,
+	})
+	_, err := r.Render("prod")
+	var missing *MissingValuesError
+	if !errors.As(err, &missing) {
+		t.Fatalf("err = %v, want a MissingValuesError", err)
+	}
+	// Every template is rendered before giving up, and each path appears once.
+	if missing.Env != "prod" || !reflect.DeepEqual(missing.Paths, []string{"api.key", "database.url"}) {
+		t.Errorf("missing = %+v, want prod and [api.key database.url]", missing)
+	}
+	if want := `environment "prod" is missing required values: api.key, database.url`; err.Error() != want {
+		t.Errorf("err = %q, want %q", err, want)
+	}
+}
+
+func TestRenderMissingKey(t *testing.T) {
+	r := renderer(map[string]string{
+		"a.tmpl": `port={{.server.prot}}`,
+	}, map[string]string{
+		"base.json": `{"server": {"port": 8080}}`,
+		"dev.json":  `{}`,
+	})
+	_, err := r.Render("dev")
+	if err == nil || !strings.Contains(err.Error(), `map has no entry for key "prot"`) {
+		t.Errorf("err = %v, want a missing key error instead of <no value>", err)
+	}
+}
+
+// TestRenderBuiltin renders the shipped templates for every shipped profile.
+func TestRenderBuiltin(t *testing.T) {
+	r := Renderer{Templates: subFS("templates"), Profiles: subFS("profiles")}
+	envs, err := Envs(r.Profiles)
+	if err != nil || len(envs) == 0 {
+		t.Fatalf("Envs = %v, %v", envs, err)
+	}
+	for _, env := range envs {
+		files, err := r.Render(env)
+		if err != nil {
+			t.Errorf("%s: %v", env, err)
+			continue
+		}
+		for _, f := range files {
+			if strings.HasPrefix(f.Name, "_") || strings.Contains(f.Content, "<no value>") {
+				t.Errorf("%s: bad output %s:\n%s", env, f.Name, f.Content)
+			}
+		}
+	}
+}
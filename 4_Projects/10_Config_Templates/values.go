@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// baseProfile holds the values shared by every environment.
+const baseProfile = "base"
+
+// ErrUnknownEnv is returned for an environment without a profile file.
+var ErrUnknownEnv = errors.New("unknown environment")
+
+// Values is a tree of JSON values: maps, slices, strings, bools and json.Number.
+type Values map[string]any
+
+// Envs lists the environments that have a profile, e.g. [dev prod staging].
+func Envs(profiles fs.FS) ([]string, error) {
+	matches, err := fs.Glob(profiles, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	var envs []string
+	for _, m := range matches {
+		if env := strings.TrimSuffix(m, ".json"); env != baseProfile {
+			envs = append(envs, env)
+		}
+	}
+	sort.Strings(envs)
+	return envs, nil
+}
+
+// LoadValues merges base.json with <env>.json; the environment wins on
+// conflicts. The environment name itself is available as .env.
+func LoadValues(profiles fs.FS, env string) (Values, error) {
+	if env == "" || env == baseProfile || strings.ContainsAny(env, `/\`) {
+		return nil, fmt.Errorf("%w %q", ErrUnknownEnv, env)
+	}
+	base, err := readProfile(profiles, baseProfile+".json")
+	if errors.Is(err, fs.ErrNotExist) {
+		base, err = Values{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	overlay, err := readProfile(profiles, env+".json")
+	if errors.Is(err, fs.ErrNotExist) {
+		envs, _ := Envs(profiles)
+		return nil, fmt.Errorf("%w %q (known: %s)", ErrUnknownEnv, env, strings.Join(envs, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	values := merge(base, overlay)
+	values["env"] = env
+	return values, nil
+}
+
+func readProfile(profiles fs.FS, name string) (Values, error) {
+	data, err := fs.ReadFile(profiles, name)
+	if err != nil {
+		return nil, err
+	}
+	// UseNumber keeps 8080 as "8080" instead of a float64 that may print as 8.08e+03.
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var values Values
+	if err := dec.Decode(&values); err != nil {
+		return nil, fmt.Errorf("profile %s: %w", name, err)
+	}
+	return values, nil
+}
+
+// merge returns a deep copy of base with overlay applied on top. Nested
+// objects are merged key by key; everything else, including lists, is replaced.
+// Neither input shares a map or slice with the result.
+func merge(base, overlay map[string]any) Values {
+	out := make(Values, len(base)+len(overlay))
+	for k, v := range base {
+		out[k] = deepCopy(v)
+	}
+	for k, v := range overlay {
+		baseMap, baseIsMap := out[k].(map[string]any)
+		overMap, overIsMap := v.(map[string]any)
+		if baseIsMap && overIsMap {
+			out[k] = map[string]any(merge(baseMap, overMap))
+			continue
+		}
+		out[k] = deepCopy(v)
+	}
+	return out
+}
+
+// deepCopy copies the objects and lists that encoding/json decodes into;
+// other values are immutable and returned as they are.
+func deepCopy(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[k] = deepCopy(e)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, e := range v {
+			s[i] = deepCopy(e)
+		}
+		return s
+	}
+	return v
+}
+
+// Lookup finds a dotted path such as "database.url". Null values count as missing.
+func (v Values) Lookup(dotted string) (any, bool) {
+	var cur any = map[string]any(v)
+	for _, key := range strings.Split(dotted, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return cur, cur != nil
+}
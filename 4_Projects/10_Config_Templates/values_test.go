@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// profiles builds an in-memory profiles directory from name → JSON.
+func profiles(files map[string]string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for name, data := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(data)}
+	}
+	return fsys
+}
+
+func TestMergePrecedence(t *testing.T) {
+	base := map[string]any{
+		"debug":  false,
+		"server": map[string]any{"host": "0.0.0.0", "port": json.Number("8080")},
+		"tags":   []any{"a", "b"},
+		"only":   "base",
+	}
+	overlay := map[string]any{
+		"debug":  true,
+		"server": map[string]any{"port": json.Number("9090"), "tls": true},
+		"tags":   []any{"c"},
+		"extra":  "env",
+	}
+	want := Values{
+		"debug":  true,
+		"server": map[string]any{"host": "0.0.0.0", "port": json.Number("9090"), "tls": true},
+		"tags":   []any{"c"}, // Lists are replaced, not merged.
+		"only":   "base",
+		"extra":  "env",
+	}
+	if got := merge(base, overlay); !reflect.DeepEqual(got, want) {
+		t.Errorf("merge =\n%v\nwant\n%v", got, want)
+	}
+
+	// A scalar and an object replace each other in either direction.
+	got := merge(map[string]any{"a": "x", "b": map[string]any{"c": 1}}, map[string]any{"a": map[string]any{"d": 2}, "b": "y"})
+	if !reflect.DeepEqual(got, Values{"a": map[string]any{"d": 2}, "b": "y"}) {
+		t.Errorf("merge of mismatched kinds = %v", got)
+	}
+}
+
+func TestMergeDeepCopies(t *testing.T) {
+	base := map[string]any{
+		"server": map[string]any{"host": "base"},
+		"list":   []any{map[string]any{"name": "first"}},
+	}
+	overlay := map[string]any{
+		"db":    map[string]any{"url": "postgres://"},
+		"hosts": []any{"a"},
+	}
+	out := merge(base, overlay)
+
+	out["server"].(map[string]any)["host"] = "changed"
+	out["list"].([]any)[0].(map[string]any)["name"] = "changed"
+	out["db"].(map[string]any)["url"] = "changed"
+	out["hosts"].([]any)[0] = "changed"
+
+	if base["server"].(map[string]any)["host"] != "base" ||
+		base["list"].([]any)[0].(map[string]any)["name"] != "first" {
+		t.Errorf("changing the result changed base: %v", base)
+	}
+	if overlay["db"].(map[string]any)["url"] != "postgres://" || overlay["hosts"].([]any)[0] != "a" {
+		t.Errorf("changing the result changed overlay: %v", overlay)
+	}
+}
+
+func TestLoadValues(t *testing.T) {
+	fsys := profiles(map[string]string{
+		"base.json": `{"server": {"port": 8080, "host": "0.0.0.0"}, "debug": false}`,
+		"dev.json":  `{"debug": true, "server": {"port": 3000}}`,
+		"prod.json": `{"database": {"url": null}}`,
+	})
+	values, err := LoadValues(fsys, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]any{
+		"env":         "dev",
+		"debug":       true,
+		"server.port": json.Number("3000"),
+		"server.host": "0.0.0.0",
+	} {
+		if got, ok := values.Lookup(path); !ok || !reflect.DeepEqual(got, want) {
+			t.Errorf("Lookup(%q) = %v, %v; want %v", path, got, ok, want)
+		}
+	}
+
+	prod, err := LoadValues(fsys, "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"database.url", "database.url.x", "server.port.x", "nope"} {
+		if v, ok := prod.Lookup(path); ok {
+			t.Errorf("Lookup(%q) = %v, want missing", path, v)
+		}
+	}
+
+	envs, err := Envs(fsys)
+	if err != nil || !reflect.DeepEqual(envs, []string{"dev", "prod"}) {
+		t.Errorf("Envs = %v, %v; want [dev prod]", envs, err)
+	}
+	for _, env := range []string{"qa", "base", "", "../dev"} {
+		if _, err := LoadValues(fsys, env); !errors.Is(err, ErrUnknownEnv) {
+			t.Errorf("LoadValues(%q): err = %v, want ErrUnknownEnv", env, err)
+		}
+	}
+}
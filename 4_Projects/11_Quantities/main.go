@@ -0,0 +1,130 @@
+// Package main implements unit-safe physical quantities.
+//
+// SECTION 4 of the Variables and Constants lesson declares
+//
+//	const gravity = 9.8
+//
+// with no unit, so nothing stops it being added to a height or read as ft/s².
+// Here every quantity carries its dimension. The typed Length, Mass, Time,
+// Velocity and Acceleration catch mistakes at compile time. Quantity values
+// parsed from strings like "9.8 m/s^2" are checked at run time. The demo
+// uses the lesson's gravity for free-fall kinematics.
+//
+//	go run main.go quantity.go typed.go units.go                                 # falls from a few heights under the lesson's g
+//	go run main.go quantity.go typed.go units.go -height "100 ft" -g "32.174 ft/s^2"
+//	go run main.go quantity.go typed.go units.go -convert "9.8 m/s^2" -to "ft/s^2"
+//	go run main.go quantity.go typed.go units.go -convert "75 kg" -to lb
+//	go run main.go quantity.go typed.go units.go -convert "9.8 m/s" -to "m/s^2"  # dimension mismatch, exit code 1
+//	go test *.go                                                                 # parsing, unit round trips and dimension checks
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// gravity is the lesson's constant, now with its unit attached.
+const gravity Acceleration = 9.8
+
+// demoHeights are dropped when no -height is given.
+var demoHeights = []Length{1 * Meter, 10 * Meter, 100 * Foot, 324 * Meter}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("quantities", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	heightText := flags.String("height", "", `drop height, e.g. "100 ft" (default: a few sample heights)`)
+	gravityText := flags.String("g", gravity.String(), "gravitational acceleration")
+	convert := flags.String("convert", "", `quantity to convert, e.g. "9.8 m/s^2"`)
+	to := flags.String("to", "", "target unit for -convert, e.g. ft/s^2")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "error: unexpected arguments: %s\n", strings.Join(flags.Args(), " "))
+		return 2
+	}
+
+	if *convert != "" {
+		if *to == "" {
+			fmt.Fprintln(stderr, "error: -convert needs -to")
+			return 2
+		}
+		if err := runConvert(stdout, *convert, *to); err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+		return 0
+	}
+
+	g, err := parseAs(*gravityText, Quantity.AsAcceleration)
+	if err != nil {
+		fmt.Fprintln(stderr, "error: -g:", err)
+		return 2
+	}
+	heights := demoHeights
+	if *heightText != "" {
+		h, err := parseAs(*heightText, Quantity.AsLength)
+		if err != nil {
+			fmt.Fprintln(stderr, "error: -height:", err)
+			return 2
+		}
+		heights = []Length{h}
+	}
+
+	fmt.Fprintf(stdout, "Free fall from rest under g = %v (%.2f ft/s^2), no air resistance:\n", g, g.FeetPerSecondSquared())
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "HEIGHT (m)\tHEIGHT (ft)\tTIME (s)\tIMPACT (m/s)\tIMPACT (ft/s)\tIMPACT (mph)\t")
+	for _, h := range heights {
+		f, err := Drop(h, g)
+		if err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+		mph, _ := f.Velocity.Quantity().In("mph")
+		fmt.Fprintf(tw, "%.2f\t%.2f\t%.3f\t%.2f\t%.2f\t%.1f\t\n",
+			h.Meters(), h.Feet(), f.Time.Seconds(), f.Velocity.MetersPerSecond(), f.Velocity.FeetPerSecond(), mph)
+	}
+	tw.Flush()
+
+	// Halfway through the fall in time, only a quarter of the height is covered.
+	f, _ := Drop(heights[len(heights)-1], g)
+	half := f.Time / 2
+	fmt.Fprintf(stdout, "\nAfter %.3f s (half the fall time) the object has fallen %.2f m of %.2f m.\n",
+		half.Seconds(), f.DistanceAfter(half).Meters(), f.Height.Meters())
+	return 0
+}
+
+// parseAs parses s and checks its dimension with one of the Quantity.As* methods.
+func parseAs[T any](s string, as func(Quantity) (T, error)) (T, error) {
+	q, err := Parse(s)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return as(q)
+}
+
+func runConvert(w io.Writer, quantity, unit string) error {
+	q, err := Parse(quantity)
+	if err != nil {
+		return err
+	}
+	v, err := q.In(unit)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s = %.6g %s (%v, %v in SI units)\n", quantity, v, unit, q.Dim, q)
+	return nil
+}
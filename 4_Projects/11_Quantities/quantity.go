@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrDimension is returned when quantities of different dimensions are mixed,
+// e.g. adding a length to a time or reading "9.8 m/s" as an acceleration.
+var ErrDimension = errors.New("dimension mismatch")
+
+// Dimension holds the exponents of the SI base dimensions a quantity is built
+// from: acceleration is length¹·time⁻², so Dimension{L: 1, T: -2}.
+type Dimension struct {
+	L, M, T int // Length, mass and time.
+}
+
+var (
+	Dimensionless   = Dimension{}
+	LengthDim       = Dimension{L: 1}
+	MassDim         = Dimension{M: 1}
+	TimeDim         = Dimension{T: 1}
+	VelocityDim     = Dimension{L: 1, T: -1}
+	AccelerationDim = Dimension{L: 1, T: -2}
+)
+
+// dimensionNames gives the familiar name of common dimensions for messages.
+var dimensionNames = map[Dimension]string{
+	Dimensionless:   "dimensionless",
+	LengthDim:       "length",
+	MassDim:         "mass",
+	TimeDim:         "time",
+	VelocityDim:     "velocity",
+	AccelerationDim: "acceleration",
+}
+
+func (d Dimension) Mul(o Dimension) Dimension {
+	return Dimension{d.L + o.L, d.M + o.M, d.T + o.T}
+}
+
+func (d Dimension) Div(o Dimension) Dimension {
+	return Dimension{d.L - o.L, d.M - o.M, d.T - o.T}
+}
+
+// String returns the name of a known dimension, or its SI base unit
+// expression such as "kg·m/s^2".
+func (d Dimension) String() string {
+	if name, ok := dimensionNames[d]; ok {
+		return name
+	}
+	return d.siUnit()
+}
+
+// siUnit writes d in SI base units: {L:1, T:-2} → "m/s^2". With nothing in
+// the numerator it uses negative exponents, {T:-1} → "s^-1", because
+// ParseUnit has no "1" to put above the line.
+func (d Dimension) siUnit() string {
+	var num, den, neg []string
+	for _, part := range []struct {
+		symbol string
+		exp    int
+	}{{"kg", d.M}, {"m", d.L}, {"s", d.T}} {
+		switch {
+		case part.exp > 0:
+			num = append(num, withExponent(part.symbol, part.exp))
+		case part.exp < 0:
+			den = append(den, withExponent(part.symbol, -part.exp))
+			neg = append(neg, withExponent(part.symbol, part.exp))
+		}
+	}
+	if len(num) == 0 {
+		return strings.Join(neg, "·")
+	}
+	s := strings.Join(num, "·")
+	if len(den) > 0 {
+		s += "/" + strings.Join(den, "·")
+	}
+	return s
+}
+
+func withExponent(symbol string, exp int) string {
+	if exp == 1 {
+		return symbol
+	}
+	return symbol + "^" + strconv.Itoa(exp)
+}
+
+// Quantity is a value in SI base units together with its dimension. It is the
+// dynamic counterpart of the typed Length, Mass, Time, Velocity and
+// Acceleration: parsing produces a Quantity, and the As* methods check its
+// dimension before handing out a typed value.
+type Quantity struct {
+	Value float64 // In SI base units (m, kg, s).
+	Dim   Dimension
+}
+
+// DimensionError reports a quantity that does not have the expected dimension.
+type DimensionError struct {
+	Got, Want Dimension
+}
+
+func (e *DimensionError) Error() string {
+	return fmt.Sprintf("%v: got %v, want %v", ErrDimension, e.Got, e.Want)
+}
+
+func (e *DimensionError) Unwrap() error { return ErrDimension }
+
+// Add returns q + o; both must have the same dimension.
+func (q Quantity) Add(o Quantity) (Quantity, error) {
+	if q.Dim != o.Dim {
+		return Quantity{}, &DimensionError{Got: o.Dim, Want: q.Dim}
+	}
+	return Quantity{q.Value + o.Value, q.Dim}, nil
+}
+
+// Sub returns q - o; both must have the same dimension.
+func (q Quantity) Sub(o Quantity) (Quantity, error) {
+	if q.Dim != o.Dim {
+		return Quantity{}, &DimensionError{Got: o.Dim, Want: q.Dim}
+	}
+	return Quantity{q.Value - o.Value, q.Dim}, nil
+}
+
+// Mul and Div combine dimensions, so they never fail.
+func (q Quantity) Mul(o Quantity) Quantity {
+	return Quantity{q.Value * o.Value, q.Dim.Mul(o.Dim)}
+}
+
+func (q Quantity) Div(o Quantity) Quantity {
+	return Quantity{q.Value / o.Value, q.Dim.Div(o.Dim)}
+}
+
+// In converts q to the given unit expression, e.g. q.In("ft/s^2").
+func (q Quantity) In(unit string) (float64, error) {
+	u, err := ParseUnit(unit)
+	if err != nil {
+		return 0, err
+	}
+	if u.Dim != q.Dim {
+		return 0, &DimensionError{Got: q.Dim, Want: u.Dim}
+	}
+	return q.Value / u.Factor, nil
+}
+
+// String formats q in SI base units, e.g. "9.8 m/s^2".
+func (q Quantity) String() string {
+	value := strconv.FormatFloat(q.Value, 'g', -1, 64)
+	if q.Dim == Dimensionless {
+		return value
+	}
+	return value + " " + q.Dim.siUnit()
+}
+
+func (q Quantity) want(d Dimension) error {
+	if q.Dim != d {
+		return &DimensionError{Got: q.Dim, Want: d}
+	}
+	return nil
+}
+
+func (q Quantity) AsLength() (Length, error)     { return Length(q.Value), q.want(LengthDim) }
+func (q Quantity) AsMass() (Mass, error)         { return Mass(q.Value), q.want(MassDim) }
+func (q Quantity) AsTime() (Time, error)         { return Time(q.Value), q.want(TimeDim) }
+func (q Quantity) AsVelocity() (Velocity, error) { return Velocity(q.Value), q.want(VelocityDim) }
+func (q Quantity) AsAcceleration() (Acceleration, error) {
+	return Acceleration(q.Value), q.want(AccelerationDim)
+}
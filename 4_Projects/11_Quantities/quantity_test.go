@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestStringRoundTrips checks that every quantity String prints is read back
+// by Parse, and every unit it prints by ParseUnit.
+func TestStringRoundTrips(t *testing.T) {
+	tests := []struct {
+		q    Quantity
+		want string
+	}{
+		{Quantity{9.8, AccelerationDim}, "9.8 m/s^2"},
+		{Quantity{3, VelocityDim}, "3 m/s"},
+		{Quantity{2, Dimension{T: -1}}, "2 s^-1"},
+		{Quantity{0.5, Dimension{L: -1, T: -2}}, "0.5 m^-1·s^-2"},
+		{Quantity{7, Dimension{M: 1, L: 2, T: -2}}, "7 kg·m^2/s^2"},
+		{Quantity{1e6, Dimension{M: 1, T: -3}}, "1e+06 kg/s^3"},
+		{Quantity{1.5, Dimensionless}, "1.5"},
+	}
+	for _, tt := range tests {
+		s := tt.q.String()
+		if s != tt.want {
+			t.Errorf("%#v.String() = %q, want %q", tt.q, s, tt.want)
+		}
+		q, err := Parse(s)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", s, err)
+			continue
+		}
+		if q != tt.q {
+			t.Errorf("Parse(%q) = %#v, want %#v", s, q, tt.q)
+		}
+		if tt.q.Dim == Dimensionless {
+			continue
+		}
+		unit := tt.q.Dim.siUnit()
+		if u, err := ParseUnit(unit); err != nil || u.Factor != 1 || u.Dim != tt.q.Dim {
+			t.Errorf("ParseUnit(%q) = %v, %v; want factor 1 and %v", unit, u, err, tt.q.Dim)
+		}
+	}
+}
+
+func TestDimensionString(t *testing.T) {
+	tests := []struct {
+		d    Dimension
+		want string
+	}{
+		{Dimensionless, "dimensionless"},
+		{AccelerationDim, "acceleration"},
+		{Dimension{T: -1}, "s^-1"},
+		{Dimension{M: 1, L: 1, T: -2}, "kg·m/s^2"},
+	}
+	for _, tt := range tests {
+		if got := tt.d.String(); got != tt.want {
+			t.Errorf("%#v.String() = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestIn(t *testing.T) {
+	g := Quantity{9.80665, AccelerationDim}
+	if got, err := g.In("g0"); err != nil || !near(got, 1) {
+		t.Errorf("In(g0) = %v, %v; want 1", got, err)
+	}
+	if got, err := g.In("ft/s^2"); err != nil || !near(got, 9.80665/0.3048) {
+		t.Errorf("In(ft/s^2) = %v, %v; want %v", got, err, 9.80665/0.3048)
+	}
+	_, err := g.In("m/s")
+	var de *DimensionError
+	if !errors.As(err, &de) || !errors.Is(err, ErrDimension) || de.Got != AccelerationDim || de.Want != VelocityDim {
+		t.Errorf("In(m/s): err = %v, want a DimensionError from acceleration to velocity", err)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	d := Quantity{100, LengthDim}
+	tm := Quantity{4, TimeDim}
+	if v := d.Div(tm); v != (Quantity{25, VelocityDim}) {
+		t.Errorf("Div = %v, want 25 m/s", v)
+	}
+	if _, err := d.Add(tm); !errors.Is(err, ErrDimension) {
+		t.Errorf("length + time: err = %v, want ErrDimension", err)
+	}
+	if _, err := d.Sub(tm); !errors.Is(err, ErrDimension) {
+		t.Errorf("length - time: err = %v, want ErrDimension", err)
+	}
+	if sum, err := d.Add(d); err != nil || sum != (Quantity{200, LengthDim}) {
+		t.Errorf("Add = %v, %v; want 200 m", sum, err)
+	}
+	if _, err := d.AsTime(); !errors.Is(err, ErrDimension) {
+		t.Errorf("AsTime on a length: err = %v, want ErrDimension", err)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// The typed quantities below let the compiler check dimensions: each is a
+// float64 in SI base units, and only physically meaningful operations exist
+// as methods. Length + Time does not compile, and Length.Div(Time) returns a
+// Velocity. Same-dimension arithmetic (a + b, 2 * a) uses Go's operators.
+type (
+	Length       float64 // Meters.
+	Mass         float64 // Kilograms.
+	Time         float64 // Seconds.
+	Velocity     float64 // Meters per second.
+	Acceleration float64 // Meters per second squared.
+)
+
+// Unit constants, so lengths read like 100 * Foot.
+const (
+	Meter       Length = 1
+	Kilometer   Length = 1000
+	Foot        Length = 0.3048
+	Inch        Length = 0.0254
+	Mile        Length = 1609.344
+	Kilogram    Mass   = 1
+	Gram        Mass   = 0.001
+	Pound       Mass   = 0.45359237
+	Second      Time   = 1
+	Millisecond Time   = 0.001
+	Minute      Time   = 60
+	Hour        Time   = 3600
+)
+
+// StandardGravity is g₀, the conventional acceleration of free fall at sea level.
+const StandardGravity Acceleration = 9.80665
+
+func (l Length) Meters() float64 { return float64(l) }
+func (l Length) Feet() float64   { return float64(l / Foot) }
+
+// Div returns the average velocity of covering l in t.
+func (l Length) Div(t Time) Velocity { return Velocity(float64(l) / float64(t)) }
+
+func (m Mass) Kilograms() float64 { return float64(m) }
+func (m Mass) Pounds() float64    { return float64(m / Pound) }
+
+func (t Time) Seconds() float64 { return float64(t) }
+
+func (v Velocity) MetersPerSecond() float64 { return float64(v) }
+func (v Velocity) FeetPerSecond() float64   { return float64(v) / float64(Foot) }
+
+// Mul returns the distance covered at velocity v during t.
+func (v Velocity) Mul(t Time) Length { return Length(float64(v) * float64(t)) }
+
+// Div returns the constant acceleration that reaches v after t.
+func (v Velocity) Div(t Time) Acceleration { return Acceleration(float64(v) / float64(t)) }
+
+func (a Acceleration) MetersPerSecondSquared() float64 { return float64(a) }
+func (a Acceleration) FeetPerSecondSquared() float64   { return float64(a) / float64(Foot) }
+
+// Mul returns the velocity gained by accelerating at a for t.
+func (a Acceleration) Mul(t Time) Velocity { return Velocity(float64(a) * float64(t)) }
+
+// Quantity converts each typed value back to the dynamic form.
+func (l Length) Quantity() Quantity       { return Quantity{float64(l), LengthDim} }
+func (m Mass) Quantity() Quantity         { return Quantity{float64(m), MassDim} }
+func (t Time) Quantity() Quantity         { return Quantity{float64(t), TimeDim} }
+func (v Velocity) Quantity() Quantity     { return Quantity{float64(v), VelocityDim} }
+func (a Acceleration) Quantity() Quantity { return Quantity{float64(a), AccelerationDim} }
+
+func (l Length) String() string       { return l.Quantity().String() }
+func (m Mass) String() string         { return m.Quantity().String() }
+func (t Time) String() string         { return t.Quantity().String() }
+func (v Velocity) String() string     { return v.Quantity().String() }
+func (a Acceleration) String() string { return a.Quantity().String() }
+
+// FreeFall describes an object dropped from rest, ignoring air resistance.
+type FreeFall struct {
+	Height   Length
+	Gravity  Acceleration
+	Time     Time     // Time to reach the ground: t = √(2h/g).
+	Velocity Velocity // Impact speed: v = g·t.
+}
+
+// Drop computes a free fall from height h under gravity g.
+func Drop(h Length, g Acceleration) (FreeFall, error) {
+	if h < 0 {
+		return FreeFall{}, fmt.Errorf("height must not be negative, got %v", h)
+	}
+	if g <= 0 {
+		return FreeFall{}, fmt.Errorf("gravity must be positive, got %v", g)
+	}
+	t := Time(math.Sqrt(2 * float64(h) / float64(g)))
+	return FreeFall{Height: h, Gravity: g, Time: t, Velocity: g.Mul(t)}, nil
+}
+
+// DistanceAfter returns how far the object has fallen after t, capped at the ground.
+func (f FreeFall) DistanceAfter(t Time) Length {
+	if t >= f.Time {
+		return f.Height
+	}
+	// d = ½·g·t², written with typed steps: (g·t)·t is a Velocity times a Time.
+	return f.Gravity.Mul(t).Mul(t) / 2
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownUnit is returned for a unit symbol that is not in the table.
+var ErrUnknownUnit = errors.New("unknown unit")
+
+// Unit is a named scale of a dimension: Factor converts one unit to SI base
+// units, so a foot is Unit{0.3048, LengthDim}.
+type Unit struct {
+	Factor float64
+	Dim    Dimension
+}
+
+// units maps symbols to their definitions. The imperial factors are exact by
+// the 1959 international yard and pound agreement.
+var units = map[string]Unit{
+	// Length.
+	"m":  {1, LengthDim},
+	"km": {1000, LengthDim},
+	"cm": {0.01, LengthDim},
+	"mm": {0.001, LengthDim},
+	"in": {0.0254, LengthDim},
+	"ft": {0.3048, LengthDim},
+	"yd": {0.9144, LengthDim},
+	"mi": {1609.344, LengthDim},
+	// Mass.
+	"kg": {1, MassDim},
+	"g":  {0.001, MassDim},
+	"lb": {0.45359237, MassDim},
+	"oz": {0.028349523125, MassDim},
+	// Time.
+	"s":   {1, TimeDim},
+	"ms":  {0.001, TimeDim},
+	"min": {60, TimeDim},
+	"h":   {3600, TimeDim},
+	// Common compound units.
+	"mph": {1609.344 / 3600, VelocityDim},
+	"kph": {1000.0 / 3600, VelocityDim},
+	"g0":  {9.80665, AccelerationDim}, // Standard gravity.
+}
+
+// UnitSymbols returns every known unit symbol, sorted.
+func UnitSymbols() []string {
+	symbols := make([]string, 0, len(units))
+	for s := range units {
+		symbols = append(symbols, s)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// ParseUnit parses a unit expression such as "m/s^2", "ft/s/s" or "kg*m/s^2".
+// Factors are separated by "*", "·" or "/"; everything after the first "/"
+// is in the denominator. An empty expression is dimensionless.
+func ParseUnit(expr string) (Unit, error) {
+	u := Unit{Factor: 1}
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return u, nil
+	}
+	denominator := false
+	for _, term := range splitTerms(expr) {
+		if term == "/" {
+			denominator = true
+			continue
+		}
+		symbol, exp, err := splitExponent(term)
+		if err != nil {
+			return Unit{}, fmt.Errorf("unit %q: %w", expr, err)
+		}
+		base, ok := units[symbol]
+		if !ok && symbol == expr {
+			return Unit{}, fmt.Errorf("%w %q", ErrUnknownUnit, symbol)
+		}
+		if !ok {
+			return Unit{}, fmt.Errorf("%w %q in %q", ErrUnknownUnit, symbol, expr)
+		}
+		if denominator {
+			exp = -exp
+		}
+		for i := 0; i < abs(exp); i++ {
+			if exp > 0 {
+				u.Factor *= base.Factor
+				u.Dim = u.Dim.Mul(base.Dim)
+			} else {
+				u.Factor /= base.Factor
+				u.Dim = u.Dim.Div(base.Dim)
+			}
+		}
+	}
+	return u, nil
+}
+
+// splitTerms splits "kg*m/s^2" into ["kg", "m", "/", "s^2"].
+func splitTerms(expr string) []string {
+	return strings.FieldsFunc(strings.ReplaceAll(expr, "/", " / "), func(r rune) bool {
+		return r == '*' || r == '·' || r == ' '
+	})
+}
+
+// splitExponent splits "s^2" into ("s", 2); "s" alone has exponent 1.
+func splitExponent(term string) (string, int, error) {
+	symbol, expText, ok := strings.Cut(term, "^")
+	if !ok {
+		return term, 1, nil
+	}
+	exp, err := strconv.Atoi(expText)
+	if err != nil || exp == 0 {
+		return "", 0, fmt.Errorf("invalid exponent in %q", term)
+	}
+	return symbol, exp, nil
+}
+
+// Parse reads a quantity such as "9.8 m/s^2", "100ft" or "3.5 lb" and
+// converts it to SI base units.
+func Parse(s string) (Quantity, error) {
+	s = strings.TrimSpace(s)
+	// The number ends where the unit begins: at the first letter that is not
+	// part of an exponent ("1e3 m" keeps its "e3").
+	end := len(s)
+	for i, r := range s {
+		if (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') && !isExponentMark(s, i) {
+			end = i
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(s[:end]), 64)
+	if err != nil {
+		return Quantity{}, fmt.Errorf("quantity %q: invalid number", s)
+	}
+	u, err := ParseUnit(s[end:])
+	if err != nil {
+		return Quantity{}, err
+	}
+	return Quantity{Value: value * u.Factor, Dim: u.Dim}, nil
+}
+
+// isExponentMark reports whether the 'e' at s[i] belongs to a float like "1e3".
+func isExponentMark(s string, i int) bool {
+	if s[i] != 'e' && s[i] != 'E' || i == 0 || i+1 >= len(s) {
+		return false
+	}
+	prev, next := s[i-1], s[i+1]
+	return (prev >= '0' && prev <= '9' || prev == '.') && (next >= '0' && next <= '9' || next == '-' || next == '+')
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// near reports whether a and b agree to about 12 significant digits.
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-12*math.Max(math.Abs(a), math.Abs(b))
+}
+
+func TestParseUnit(t *testing.T) {
+	tests := []struct {
+		expr   string
+		factor float64
+		dim    Dimension
+	}{
+		{"", 1, Dimensionless},
+		{"m", 1, LengthDim},
+		{"km", 1000, LengthDim},
+		{"ft/s^2", 0.3048, AccelerationDim},
+		{"ft/s/s", 0.3048, AccelerationDim},
+		{"kg*m/s^2", 1, Dimension{L: 1, M: 1, T: -2}},
+		{"kg·m/s^2", 1, Dimension{L: 1, M: 1, T: -2}},
+		{"s^-1", 1, Dimension{T: -1}},
+		{"min^-1", 1.0 / 60, Dimension{T: -1}},
+		{"kg^-1·s^-2", 1, Dimension{M: -1, T: -2}},
+		{"mph", 1609.344 / 3600, VelocityDim},
+		{"mi/h", 1609.344 / 3600, VelocityDim},
+		{" g0 ", 9.80665, AccelerationDim},
+	}
+	for _, tt := range tests {
+		u, err := ParseUnit(tt.expr)
+		if err != nil {
+			t.Errorf("ParseUnit(%q): %v", tt.expr, err)
+			continue
+		}
+		if !near(u.Factor, tt.factor) || u.Dim != tt.dim {
+			t.Errorf("ParseUnit(%q) = %v %v, want %v %v", tt.expr, u.Factor, u.Dim, tt.factor, tt.dim)
+		}
+	}
+}
+
+func TestParseUnitErrors(t *testing.T) {
+	for _, expr := range []string{"parsec", "m/furlong", "s^0", "s^x", "1/s"} {
+		if _, err := ParseUnit(expr); err == nil {
+			t.Errorf("ParseUnit(%q) succeeded, want an error", expr)
+		}
+	}
+	if _, err := ParseUnit("furlong"); !errors.Is(err, ErrUnknownUnit) {
+		t.Errorf("ParseUnit(furlong): err = %v, want ErrUnknownUnit", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Quantity
+	}{
+		{"9.8 m/s^2", Quantity{9.8, AccelerationDim}},
+		{"100ft", Quantity{30.48, LengthDim}},
+		{"3.5 lb", Quantity{3.5 * 0.45359237, MassDim}},
+		{"1e3 m", Quantity{1000, LengthDim}},
+		{"2.5E-3 km", Quantity{2.5, LengthDim}},
+		{"-4 s", Quantity{-4, TimeDim}},
+		{"60 min^-1", Quantity{1, Dimension{T: -1}}},
+		{"42", Quantity{42, Dimensionless}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if !near(q.Value, tt.want.Value) || q.Dim != tt.want.Dim {
+			t.Errorf("Parse(%q) = %v, want %v", tt.in, q, tt.want)
+		}
+	}
+	for _, in := range []string{"", "m", "fast", "3 parsec"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", in)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+// ErrNotPowerOfTwo is returned for FFT input whose length is not 1, 2, 4, 8, …
+var ErrNotPowerOfTwo = errors.New("length is not a power of two")
+
+// FFT returns the discrete Fourier transform of x,
+//
+//	X_k = Σ_n x_n · e^(−2πi·kn/N)
+//
+// using the iterative radix-2 Cooley–Tukey algorithm in O(N log N). x is not modified.
+func FFT(x []complex128) ([]complex128, error) {
+	return transform(x, -1)
+}
+
+// IFFT inverts FFT, including the 1/N scaling, so IFFT(FFT(x)) ≈ x.
+func IFFT(x []complex128) ([]complex128, error) {
+	out, err := transform(x, 1)
+	if err != nil {
+		return nil, err
+	}
+	scale := complex(1/float64(len(out)), 0)
+	for i := range out {
+		out[i] *= scale
+	}
+	return out, nil
+}
+
+// transform runs the butterfly passes; sign is -1 for the forward transform.
+func transform(x []complex128, sign float64) ([]complex128, error) {
+	n := len(x)
+	if n == 0 || n&(n-1) != 0 {
+		return nil, fmt.Errorf("fft of %d values: %w", n, ErrNotPowerOfTwo)
+	}
+	out := make([]complex128, n)
+	copy(out, x)
+
+	// Reorder into bit-reversed index order so the passes can work in place.
+	shift := 64 - bits.Len(uint(n-1))
+	for i := range out {
+		if j := int(bits.Reverse64(uint64(i)) >> shift); i < j {
+			out[i], out[j] = out[j], out[i]
+		}
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Rect(1, sign*2*math.Pi/float64(size)) // Principal size-th root of unity.
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even, odd := out[start+k], w*out[start+k+size/2]
+				out[start+k] = even + odd
+				out[start+k+size/2] = even - odd
+				w *= step
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"math/rand/v2"
+	"testing"
+)
+
+// closeTo reports whether got and want agree element by element within tol.
+func closeTo(got, want []complex128, tol float64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if cmplx.Abs(got[i]-want[i]) > tol {
+			return false
+		}
+	}
+	return true
+}
+
+// dft is the O(N²) definition FFT must agree with.
+func dft(x []complex128) []complex128 {
+	n := len(x)
+	out := make([]complex128, n)
+	for k := range out {
+		for j, v := range x {
+			out[k] += v * cmplx.Rect(1, -2*math.Pi*float64(k*j)/float64(n))
+		}
+	}
+	return out
+}
+
+func TestFFTKnown(t *testing.T) {
+	tests := []struct {
+		name    string
+		in, out []complex128
+	}{
+		{"single value", []complex128{3 + 4i}, []complex128{3 + 4i}},
+		{"impulse", []complex128{1, 0, 0, 0}, []complex128{1, 1, 1, 1}},
+		{"constant", []complex128{1, 1, 1, 1}, []complex128{4, 0, 0, 0}},
+		{"delayed impulse", []complex128{0, 1, 0, 0}, []complex128{1, -1i, -1, 1i}},
+		{"cosine", []complex128{1, 0, -1, 0}, []complex128{0, 2, 0, 2}},
+		{"complex exponential", []complex128{1, 1i, -1, -1i}, []complex128{0, 4, 0, 0}},
+	}
+	for _, tt := range tests {
+		got, err := FFT(tt.in)
+		if err != nil || !closeTo(got, tt.out, 1e-12) {
+			t.Errorf("%s: FFT(%v) = %v, %v; want %v", tt.name, tt.in, got, err, tt.out)
+		}
+	}
+}
+
+func TestFFTMatchesDFT(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, n := range []int{2, 8, 64, 256} {
+		x := make([]complex128, n)
+		for i := range x {
+			x[i] = complex(r.NormFloat64(), r.NormFloat64())
+		}
+		orig := append([]complex128(nil), x...)
+		got, err := FFT(x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !closeTo(got, dft(x), 1e-9) {
+			t.Errorf("n=%d: FFT differs from the DFT", n)
+		}
+		if !closeTo(x, orig, 0) {
+			t.Errorf("n=%d: FFT modified its input", n)
+		}
+
+		back, err := IFFT(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !closeTo(back, x, 1e-12) {
+			t.Errorf("n=%d: IFFT(FFT(x)) = %v, want %v", n, back, x)
+		}
+	}
+}
+
+func TestFFTNotPowerOfTwo(t *testing.T) {
+	for _, n := range []int{0, 3, 6, 12, 100} {
+		x := make([]complex128, n)
+		if _, err := FFT(x); !errors.Is(err, ErrNotPowerOfTwo) {
+			t.Errorf("FFT of %d values: err = %v, want ErrNotPowerOfTwo", n, err)
+		}
+		if _, err := IFFT(x); !errors.Is(err, ErrNotPowerOfTwo) {
+			t.Errorf("IFFT of %d values: err = %v, want ErrNotPowerOfTwo", n, err)
+		}
+	}
+}
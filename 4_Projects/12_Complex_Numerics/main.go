@@ -0,0 +1,276 @@
+// Package main is a small numerics toolkit built on complex128, the type
+// SECTION 5 of the Variables and Constants lesson declares as complex(5, 2)
+// and never uses again.
+//
+// Numbers are read from stdin, separated by spaces, commas or newlines, in
+// any form strconv.ParseComplex accepts: 3, -2.5, 4i, 1+2i, (1-1e-3i).
+//
+//	go build -o cnum main.go fft.go matrix.go poly.go
+//	echo "1 0 -1" | ./cnum roots                      # roots of x² − 1
+//	echo "1 -3 2" | ./cnum eval -x 1+1i               # evaluate x² − 3x + 2 at 1+i
+//	echo "1 1 1 1 0 0 0 0" | ./cnum fft               # length must be a power of two
+//	echo "1 1 1 1 0 0 0 0" | ./cnum -precision 17 fft | ./cnum ifft
+//	printf "1 2\n3 4\n\n5i 6\n7 8\n" | ./cnum matmul  # matrices separated by a blank line
+//	go test *.go                                      # roots, FFT against the DFT, matrix shapes
+//
+// Polynomial coefficients are given highest degree first.
+// Exit codes: 0 on success, 1 on runtime errors, 2 on invalid usage or input.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/cmplx"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// usageError marks errors caused by invalid command-line input (exit code 2).
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usageErrorf(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("cnum", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	precision := flags.Int("precision", 6, "significant digits in the output")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: cnum [-precision n] eval|roots|fft|ifft|matmul [args] < numbers")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	f := formatter{precision: *precision}
+	err := dispatch(flags.Arg(0), flags.Args()[1:], stdin, stdout, stderr, f)
+	var usageErr *usageError
+	switch {
+	case errors.As(err, &usageErr):
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	case err != nil:
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
+
+func dispatch(cmd string, args []string, stdin io.Reader, stdout, stderr io.Writer, f formatter) error {
+	switch cmd {
+	case "eval":
+		flags := flag.NewFlagSet("eval", flag.ContinueOnError)
+		flags.SetOutput(stderr)
+		xText := flags.String("x", "0", "point to evaluate at, e.g. 1+2i")
+		if err := flags.Parse(args); err != nil {
+			return usageErrorf("%v", err)
+		}
+		x, err := parseComplex(*xText)
+		if err != nil {
+			return usageErrorf("-x: %v", err)
+		}
+		p, err := readNumbers(stdin)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, f.format(Poly(p).Eval(x)))
+		return nil
+
+	case "roots":
+		p, err := readNumbers(stdin)
+		if err != nil {
+			return err
+		}
+		roots, err := Poly(p).Roots(RootOptions{})
+		if errors.Is(err, ErrDegree) {
+			return usageErrorf("%v", err)
+		}
+		if err != nil {
+			return err
+		}
+		for _, r := range roots {
+			// The residual |p(r)| shows how good each root is.
+			fmt.Fprintf(stdout, "%s\t|p(x)| = %.2g\n", f.format(r), cmplx.Abs(Poly(p).Eval(r)))
+		}
+		return nil
+
+	case "fft", "ifft":
+		x, err := readNumbers(stdin)
+		if err != nil {
+			return err
+		}
+		transform := FFT
+		if cmd == "ifft" {
+			transform = IFFT
+		}
+		out, err := transform(x)
+		if errors.Is(err, ErrNotPowerOfTwo) {
+			return usageErrorf("%v", err)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(stdout, f.join(out))
+		return nil
+
+	case "matmul":
+		a, b, err := readMatrices(stdin)
+		if err != nil {
+			return err
+		}
+		product, err := a.Mul(b)
+		if err != nil {
+			return usageErrorf("%v", err)
+		}
+		for _, row := range product {
+			fmt.Fprintln(stdout, f.join(row))
+		}
+		return nil
+
+	default:
+		return usageErrorf("unknown command %q", cmd)
+	}
+}
+
+// readNumbers reads every number from r.
+func readNumbers(r io.Reader) ([]complex128, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	nums, err := parseLine(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(nums) == 0 {
+		return nil, usageErrorf("no numbers on stdin")
+	}
+	return nums, nil
+}
+
+// readMatrices reads two matrices, one row per line, separated by a blank line.
+func readMatrices(r io.Reader) (Matrix, Matrix, error) {
+	var matrices []Matrix
+	var current Matrix
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			if current != nil {
+				matrices, current = append(matrices, current), nil
+			}
+			continue
+		}
+		row, err := parseLine(line)
+		if err != nil {
+			return nil, nil, err
+		}
+		current = append(current, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if current != nil {
+		matrices = append(matrices, current)
+	}
+	if len(matrices) != 2 {
+		return nil, nil, usageErrorf("matmul needs exactly two matrices separated by a blank line, got %d", len(matrices))
+	}
+	return matrices[0], matrices[1], nil
+}
+
+func parseLine(s string) ([]complex128, error) {
+	var nums []complex128
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	}) {
+		c, err := parseComplex(field)
+		if err != nil {
+			return nil, usageErrorf("%v", err)
+		}
+		nums = append(nums, c)
+	}
+	return nums, nil
+}
+
+func parseComplex(s string) (complex128, error) {
+	c, err := strconv.ParseComplex(s, 128)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a complex number", s)
+	}
+	return c, nil
+}
+
+// formatter prints complex numbers compactly. Parts smaller than the printed
+// precision, relative to the largest magnitude in the output, are treated as
+// rounding noise and dropped, so an FFT bin of 1.2e-16i next to a 4 prints as 0.
+type formatter struct {
+	precision int
+}
+
+func (f formatter) format(c complex128) string {
+	return f.formatScaled(c, cmplx.Abs(c))
+}
+
+func (f formatter) formatScaled(c complex128, scale float64) string {
+	re, im := f.clean(real(c), scale), f.clean(imag(c), scale)
+	switch {
+	case im == 0:
+		return f.float(re)
+	case re == 0:
+		return f.float(im) + "i"
+	case im < 0:
+		return f.float(re) + f.float(im) + "i"
+	default:
+		return f.float(re) + "+" + f.float(im) + "i"
+	}
+}
+
+// clean zeroes a part that is negligible relative to scale.
+func (f formatter) clean(part, scale float64) float64 {
+	if math.Abs(part) <= scale*math.Pow(10, -float64(f.precision)) {
+		return 0
+	}
+	return part
+}
+
+func (f formatter) float(v float64) string {
+	return strconv.FormatFloat(v, 'g', f.precision, 64)
+}
+
+// join formats a vector or matrix row against its largest element.
+func (f formatter) join(nums []complex128) string {
+	scale := 0.0
+	for _, c := range nums {
+		scale = math.Max(scale, cmplx.Abs(c))
+	}
+	parts := make([]string, len(nums))
+	for i, c := range nums {
+		parts[i] = f.formatScaled(c, scale)
+	}
+	return strings.Join(parts, " ")
+}
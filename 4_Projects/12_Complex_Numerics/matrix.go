@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrShape is returned for ragged matrices and mismatched dimensions.
+var ErrShape = errors.New("incompatible matrix shape")
+
+// Matrix is a dense row-major complex matrix.
+type Matrix [][]complex128
+
+// Dims returns the number of rows and columns, checking that every row has
+// the same length.
+func (m Matrix) Dims() (rows, cols int, err error) {
+	if len(m) == 0 {
+		return 0, 0, nil
+	}
+	cols = len(m[0])
+	for i, row := range m {
+		if len(row) != cols {
+			return 0, 0, fmt.Errorf("row %d has %d columns, row 1 has %d: %w", i+1, len(row), cols, ErrShape)
+		}
+	}
+	return len(m), cols, nil
+}
+
+// Mul returns the product m·o. The inner loops run over k then j so both
+// matrices are read row by row.
+func (m Matrix) Mul(o Matrix) (Matrix, error) {
+	rows, inner, err := m.Dims()
+	if err != nil {
+		return nil, err
+	}
+	oRows, cols, err := o.Dims()
+	if err != nil {
+		return nil, err
+	}
+	if inner != oRows {
+		return nil, fmt.Errorf("%dx%d times %dx%d: %w", rows, inner, oRows, cols, ErrShape)
+	}
+
+	out := make(Matrix, rows)
+	for i := range out {
+		out[i] = make([]complex128, cols)
+		for k := 0; k < inner; k++ {
+			a := m[i][k]
+			for j := 0; j < cols; j++ {
+				out[i][j] += a * o[k][j]
+			}
+		}
+	}
+	return out, nil
+}
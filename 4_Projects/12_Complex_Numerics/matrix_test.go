@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Matrix
+		want    Matrix
+		wantErr bool
+	}{
+		{
+			"square",
+			Matrix{{1, 2}, {3, 4}}, Matrix{{5i, 6}, {7, 8}},
+			Matrix{{14 + 5i, 22}, {28 + 15i, 50}}, false,
+		},
+		{
+			"complex entries",
+			Matrix{{1i, 0}, {0, 1i}}, Matrix{{1i, 1}, {2, 1i}},
+			Matrix{{-1, 1i}, {2i, -1}}, false,
+		},
+		{"row times column", Matrix{{1, 2, 3}}, Matrix{{4}, {5}, {6}}, Matrix{{32}}, false},
+		{"column times row", Matrix{{1}, {2i}}, Matrix{{3, 4}}, Matrix{{3, 4}, {6i, 8i}}, false},
+		{"identity", Matrix{{1, 0}, {0, 1}}, Matrix{{1 + 1i, 2}, {3, 4 - 1i}}, Matrix{{1 + 1i, 2}, {3, 4 - 1i}}, false},
+		{"inner dimensions differ", Matrix{{1, 2}, {3, 4}}, Matrix{{1, 2, 3}}, nil, true},
+		{"2x3 times 2x3", Matrix{{1, 2, 3}, {4, 5, 6}}, Matrix{{1, 2, 3}, {4, 5, 6}}, nil, true},
+		{"ragged left", Matrix{{1, 2}, {3}}, Matrix{{1}, {2}}, nil, true},
+		{"ragged right", Matrix{{1, 2}}, Matrix{{1, 2}, {3}}, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := tt.a.Mul(tt.b)
+		if tt.wantErr {
+			if !errors.Is(err, ErrShape) || got != nil {
+				t.Errorf("%s: Mul = %v, %v; want ErrShape", tt.name, got, err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Mul = %v, %v; want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestMulErrorNamesShapes(t *testing.T) {
+	_, err := Matrix{{1, 2}, {3, 4}}.Mul(Matrix{{1, 2, 3}})
+	if want := "2x2 times 1x3: incompatible matrix shape"; err == nil || err.Error() != want {
+		t.Errorf("err = %v, want %q", err, want)
+	}
+	_, err = Matrix{{1, 2}, {3}}.Mul(Matrix{{1}})
+	if want := "row 2 has 1 columns, row 1 has 2: incompatible matrix shape"; err == nil || err.Error() != want {
+		t.Errorf("err = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+)
+
+var (
+	// ErrDegree is returned when a polynomial has no roots to find.
+	ErrDegree = errors.New("polynomial must have degree at least 1")
+	// ErrNoConvergence is returned when root finding runs out of iterations.
+	ErrNoConvergence = errors.New("did not converge")
+)
+
+// Poly is a polynomial with complex coefficients, highest degree first:
+// Poly{1, 0, -1} is x² − 1. This is the order people write polynomials in and
+// the order Horner's scheme consumes them.
+type Poly []complex128
+
+// Degree returns the degree, ignoring leading zero coefficients. The zero
+// polynomial has degree -1.
+func (p Poly) Degree() int {
+	return len(p.trim()) - 1
+}
+
+func (p Poly) trim() Poly {
+	for len(p) > 0 && p[0] == 0 {
+		p = p[1:]
+	}
+	return p
+}
+
+// Eval evaluates p at x with Horner's scheme.
+func (p Poly) Eval(x complex128) complex128 {
+	var sum complex128
+	for _, c := range p {
+		sum = sum*x + c
+	}
+	return sum
+}
+
+// RootOptions tunes the Durand–Kerner iteration.
+type RootOptions struct {
+	MaxIterations int     // Default 500.
+	Tolerance     float64 // Stop when no root moves more than this, relative to its size; default 1e-12.
+}
+
+// Roots finds all roots of p at once with the Durand–Kerner (Weierstrass)
+// method. Each estimate z_i is refined by
+//
+//	z_i ← z_i − p(z_i) / ∏_{j≠i} (z_i − z_j)
+//
+// on the monic form of p, starting from powers of 0.4+0.9i, which is neither
+// real nor a root of unity so the estimates start out distinct.
+//
+// An estimate has settled when its last step is below Tolerance relative to
+// its magnitude, or when |p(z)| is down to the rounding error of evaluating p
+// there. The second test is what stops repeated roots: near a root of
+// multiplicity m the iteration only converges linearly and the estimates
+// scatter by about ε^(1/m), so steps never get small while p(z) already is
+// as small as float64 can tell apart from zero.
+func (p Poly) Roots(opts RootOptions) ([]complex128, error) {
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = 500
+	}
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = 1e-12
+	}
+	p = p.trim()
+	n := len(p) - 1
+	if n < 1 {
+		return nil, ErrDegree
+	}
+
+	monic := make(Poly, len(p))
+	for i, c := range p {
+		monic[i] = c / p[0]
+	}
+
+	roots := make([]complex128, n)
+	seed := complex(0.4, 0.9)
+	roots[0] = 1
+	for i := 1; i < n; i++ {
+		roots[i] = roots[i-1] * seed
+	}
+
+	for iter := 0; iter < opts.MaxIterations; iter++ {
+		settled := true
+		for i := range roots {
+			denom := complex(1, 0)
+			for j := range roots {
+				if i != j {
+					denom *= roots[i] - roots[j]
+				}
+			}
+			if denom == 0 {
+				// Two estimates collided; nudge one apart and try again next round.
+				roots[i] += complex(opts.Tolerance, opts.Tolerance)
+				settled = false
+				continue
+			}
+			value := monic.Eval(roots[i])
+			atZero := cmplx.Abs(value) <= monic.roundoff(roots[i])
+			delta := value / denom
+			roots[i] -= delta
+			if !atZero && cmplx.Abs(delta) > opts.Tolerance*math.Max(1, cmplx.Abs(roots[i])) {
+				settled = false
+			}
+		}
+		if settled {
+			return roots, nil
+		}
+	}
+	return roots, fmt.Errorf("durand-kerner after %d iterations: %w", opts.MaxIterations, ErrNoConvergence)
+}
+
+// roundoff bounds the rounding error of evaluating p at x with Horner's
+// scheme: a few units in the last place of Σ|c_k|·|x|^k per step.
+func (p Poly) roundoff(x complex128) float64 {
+	const eps = 0x1p-52
+	var sum float64
+	r := cmplx.Abs(x)
+	for _, c := range p {
+		sum = sum*r + cmplx.Abs(c)
+	}
+	return 4 * float64(len(p)) * eps * sum
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"math/cmplx"
+	"testing"
+)
+
+// nearest removes and returns the root in roots closest to want.
+func nearest(roots *[]complex128, want complex128) complex128 {
+	best := 0
+	for i, r := range *roots {
+		if cmplx.Abs(r-want) < cmplx.Abs((*roots)[best]-want) {
+			best = i
+		}
+	}
+	r := (*roots)[best]
+	*roots = append((*roots)[:best], (*roots)[best+1:]...)
+	return r
+}
+
+func TestRoots(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Poly
+		want []complex128
+		tol  float64
+	}{
+		{"x²−1", Poly{1, 0, -1}, []complex128{-1, 1}, 1e-12},
+		{"x²+1", Poly{1, 0, 1}, []complex128{-1i, 1i}, 1e-12},
+		{"(x−1)(x−2)(x−3)", Poly{1, -6, 11, -6}, []complex128{1, 2, 3}, 1e-10},
+		{"leading zeros", Poly{0, 0, 2, -4}, []complex128{2}, 1e-12},
+		{"complex coefficients", Poly{1, -3i, -2}, []complex128{1i, 2i}, 1e-10},
+
+		// Repeated roots: the estimates can only get within about ε^(1/m)
+		// of a root of multiplicity m, so the tolerance widens with m.
+		{"(x−1)²", Poly{1, -2, 1}, []complex128{1, 1}, 1e-7},
+		{"(x−1)³", Poly{1, -3, 3, -1}, []complex128{1, 1, 1}, 1e-4},
+		{"(x−1)⁴", Poly{1, -4, 6, -4, 1}, []complex128{1, 1, 1, 1}, 1e-3},
+		{"(x+2)²(x−3)", Poly{1, 1, -8, -12}, []complex128{-2, -2, 3}, 1e-6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roots, err := tt.p.Roots(RootOptions{})
+			if err != nil {
+				t.Fatalf("Roots: %v", err)
+			}
+			if len(roots) != len(tt.want) {
+				t.Fatalf("got %d roots %v, want %v", len(roots), roots, tt.want)
+			}
+			for _, want := range tt.want {
+				if r := nearest(&roots, want); cmplx.Abs(r-want) > tt.tol {
+					t.Errorf("root %v, want %v within %g", r, want, tt.tol)
+				}
+			}
+		})
+	}
+}
+
+func TestRootsRepeatedResidual(t *testing.T) {
+	// However far the estimates scatter around a repeated root, p must be
+	// zero there to within rounding.
+	for _, p := range []Poly{{1, -3, 3, -1}, {1, -4, 6, -4, 1}, {1, -5, 10, -10, 5, -1}} {
+		roots, err := p.Roots(RootOptions{})
+		if err != nil {
+			t.Fatalf("%v.Roots: %v", p, err)
+		}
+		for _, r := range roots {
+			if res := cmplx.Abs(p.Eval(r)); res > 1e-13 {
+				t.Errorf("%v: |p(%v)| = %g", p, r, res)
+			}
+		}
+	}
+}
+
+func TestRootsErrors(t *testing.T) {
+	for _, p := range []Poly{nil, {0}, {5}, {0, 0, 3}} {
+		if _, err := p.Roots(RootOptions{}); !errors.Is(err, ErrDegree) {
+			t.Errorf("%v.Roots: err = %v, want ErrDegree", p, err)
+		}
+	}
+	if _, err := (Poly{1, -6, 11, -6}).Roots(RootOptions{MaxIterations: 1}); !errors.Is(err, ErrNoConvergence) {
+		t.Errorf("one iteration: err = %v, want ErrNoConvergence", err)
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// maxResultBits keeps a typo like 9^9^9 from running for hours: results are
+// capped at 16 Mbit (about five million decimal digits).
+const maxResultBits = 1 << 24
+
+// ErrTooLarge is returned when a result would exceed maxResultBits.
+var ErrTooLarge = errors.New("result too large")
+
+// SyntaxError reports where an expression could not be parsed.
+type SyntaxError struct {
+	Expr   string
+	Offset int // Byte offset of the problem.
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at offset %d in %q: %s", e.Offset, e.Expr, e.Msg)
+}
+
+// Eval computes an integer expression exactly. It supports + - * / % with the
+// usual precedence, ^ for powers (right-associative), postfix ! for
+// factorials, unary minus and parentheses. Literals may use Go's prefixes and
+// underscores: 0xFF, 0b1010, 0o17, 1_000_000. / and % truncate like Go's.
+func Eval(expr string) (*big.Int, error) {
+	p := &parser{expr: expr}
+	v, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	if p.skipSpace(); p.pos < len(expr) {
+		return nil, p.errorf("unexpected %q", expr[p.pos:p.pos+1])
+	}
+	return v, nil
+}
+
+// parser is a recursive-descent parser that evaluates as it goes:
+//
+//	sum     = product { ("+" | "-") product }
+//	product = unary { ("*" | "/" | "%") unary }
+//	unary   = "-" unary | power
+//	power   = postfix [ "^" unary ]
+//	postfix = primary { "!" }
+//	primary = number | "(" sum ")"
+type parser struct {
+	expr string
+	pos  int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return &SyntaxError{Expr: p.expr, Offset: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.expr) && p.expr[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// accept consumes the next non-space byte if it is one of ops.
+func (p *parser) accept(ops string) (byte, bool) {
+	p.skipSpace()
+	if p.pos < len(p.expr) && strings.IndexByte(ops, p.expr[p.pos]) >= 0 {
+		p.pos++
+		return p.expr[p.pos-1], true
+	}
+	return 0, false
+}
+
+func (p *parser) sum() (*big.Int, error) {
+	v, err := p.product()
+	for err == nil {
+		op, ok := p.accept("+-")
+		if !ok {
+			break
+		}
+		var rhs *big.Int
+		if rhs, err = p.product(); err == nil {
+			v, err = Exact(v, string(op), rhs)
+		}
+	}
+	return v, err
+}
+
+func (p *parser) product() (*big.Int, error) {
+	v, err := p.unary()
+	for err == nil {
+		op, ok := p.accept("*/%")
+		if !ok {
+			break
+		}
+		var rhs *big.Int
+		if rhs, err = p.unary(); err != nil {
+			break
+		}
+		// A product has at most as many bits as its factors together, so
+		// 2^16777215 * 2^16777215 is refused before Mul allocates it.
+		if op == '*' && v.BitLen()+rhs.BitLen() > maxResultBits {
+			return nil, fmt.Errorf("product of %d-bit and %d-bit numbers: %w", v.BitLen(), rhs.BitLen(), ErrTooLarge)
+		}
+		v, err = Exact(v, string(op), rhs)
+	}
+	return v, err
+}
+
+func (p *parser) unary() (*big.Int, error) {
+	if _, ok := p.accept("-"); ok {
+		v, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return v.Neg(v), nil
+	}
+	return p.power()
+}
+
+func (p *parser) power() (*big.Int, error) {
+	base, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("^"); !ok {
+		return base, nil
+	}
+	at := p.pos
+	exp, err := p.unary() // Right-associative: 2^3^2 is 2^9.
+	if err != nil {
+		return nil, err
+	}
+	if exp.Sign() < 0 {
+		p.pos = at
+		return nil, p.errorf("negative exponent %s", exp)
+	}
+	return pow(base, exp)
+}
+
+// pow returns base^exp for exp >= 0, or ErrTooLarge if the result would have
+// more than maxResultBits bits.
+func pow(base, exp *big.Int) (*big.Int, error) {
+	if base.BitLen() <= 1 {
+		return new(big.Int).Exp(base, exp, nil), nil
+	}
+	// The result has at least exp·(BitLen−1)+1 bits, so large exponents
+	// are refused before any work is done.
+	if !exp.IsInt64() || exp.Int64() > (maxResultBits-1)/int64(base.BitLen()-1) {
+		return nil, fmt.Errorf("%s^%s: %w", base, exp, ErrTooLarge)
+	}
+	// The upper bound is exp·BitLen bits, which can be far above the cap, so
+	// square and multiply from the top bit of exp down and check as we go:
+	// no partial result is larger than the final one.
+	v := big.NewInt(1)
+	for i := exp.BitLen() - 1; i >= 0; i-- {
+		v.Mul(v, v)
+		if exp.Bit(i) == 1 {
+			v.Mul(v, base)
+		}
+		if v.BitLen() > maxResultBits {
+			return nil, fmt.Errorf("%s^%s: %w", base, exp, ErrTooLarge)
+		}
+	}
+	return v, nil
+}
+
+func (p *parser) postfix() (*big.Int, error) {
+	v, err := p.primary()
+	for err == nil {
+		if _, ok := p.accept("!"); !ok {
+			break
+		}
+		v, err = factorial(v)
+	}
+	return v, err
+}
+
+func (p *parser) primary() (*big.Int, error) {
+	if _, ok := p.accept("("); ok {
+		v, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.accept(")"); !ok {
+			return nil, p.errorf("missing )")
+		}
+		return v, nil
+	}
+
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.expr) && (isAlnum(p.expr[p.pos]) || p.expr[p.pos] == '_') {
+		p.pos++
+	}
+	if start == p.pos {
+		if p.pos == len(p.expr) {
+			return nil, p.errorf("unexpected end of expression")
+		}
+		return nil, p.errorf("expected a number, got %q", p.expr[p.pos:p.pos+1])
+	}
+	literal := p.expr[start:p.pos]
+	v, ok := new(big.Int).SetString(literal, 0)
+	if !ok {
+		p.pos = start
+		return nil, p.errorf("invalid number %q", literal)
+	}
+	return v, nil
+}
+
+func isAlnum(c byte) bool {
+	return c < unicode.MaxASCII && (unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)))
+}
+
+// factorial computes n! with MulRange, which multiplies in a balanced tree.
+func factorial(n *big.Int) (*big.Int, error) {
+	if n.Sign() < 0 {
+		return nil, fmt.Errorf("factorial of negative number %s", n)
+	}
+	// log2(n!) is about n·log2(n); 500,000! already has about 9 Mbit.
+	if !n.IsInt64() || n.Int64() > 500_000 {
+		return nil, fmt.Errorf("%s!: %w", n, ErrTooLarge)
+	}
+	return new(big.Int).MulRange(1, n.Int64()), nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		expr, want string
+	}{
+		{"2^64 - 1", "18446744073709551615"},
+		{"0xFFFF_FFFF * 3", "12884901885"},
+		{"2^3^2", "512"},
+		{"-7 / 2", "-3"},
+		{"-7 % 2", "-1"},
+		{"(1 + 2) * 3!", "18"},
+		{"20!", "2432902008176640000"},
+		{"0b1010 + 0o17", "25"},
+	}
+	for _, tt := range tests {
+		got, err := Eval(tt.expr)
+		if err != nil || got.String() != tt.want {
+			t.Errorf("Eval(%q) = %v, %v; want %s", tt.expr, got, err, tt.want)
+		}
+	}
+}
+
+func TestEvalTooLarge(t *testing.T) {
+	for _, expr := range []string{
+		"9^9^9",
+		"500001!",
+		"2^16777000 * 2^16777000",
+		"(2^8388608) * (2^8388608)",
+	} {
+		if _, err := Eval(expr); !errors.Is(err, ErrTooLarge) {
+			t.Errorf("Eval(%q): err = %v, want ErrTooLarge", expr, err)
+		}
+	}
+	// Just under the cap still works.
+	v, err := Eval("2^8388000 * 2^8388000")
+	if err != nil || v.BitLen() != 16776001 {
+		t.Errorf("Eval(2^8388000 * 2^8388000) = %d bits, %v; want 16776001 bits", v.BitLen(), err)
+	}
+}
+
+// TestEvalPowerBoundary checks powers on both sides of the cap. 3^k has
+// ⌊k·log₂3⌋+1 bits, between k·(BitLen(3)−1) and k·BitLen(3), so the last
+// cases can only be decided by computing.
+func TestEvalPowerBoundary(t *testing.T) {
+	tests := []struct {
+		expr     string
+		bits     int
+		tooLarge bool
+	}{
+		{"2^16777215", maxResultBits, false},
+		{"2^16777216", 0, true},
+		{"(-2)^16777215", maxResultBits, false},
+		{"4^8388607", maxResultBits - 1, false},
+		{"4^8388608", 0, true},
+		{"3^16777216", 0, true}, // About 26.6 Mbit.
+		{"3^10585000", 16776829, false},
+		{"3^10586000", 0, true},
+		{"1^99999999999999999999", 1, false},
+		{"0^99999999999999999999", 0, false},
+	}
+	for _, tt := range tests {
+		v, err := Eval(tt.expr)
+		switch {
+		case tt.tooLarge:
+			if !errors.Is(err, ErrTooLarge) {
+				t.Errorf("Eval(%q): err = %v, want ErrTooLarge", tt.expr, err)
+			}
+		case err != nil:
+			t.Errorf("Eval(%q): %v", tt.expr, err)
+		case v.BitLen() != tt.bits:
+			t.Errorf("Eval(%q) = %d bits, want %d", tt.expr, v.BitLen(), tt.bits)
+		}
+	}
+}
+
+func TestEvalSyntax(t *testing.T) {
+	tests := []struct {
+		expr   string
+		offset int
+	}{
+		{"1 +", 3},
+		{"(1 + 2", 6},
+		{"2^-1", 2},
+		{"12abc", 0},
+		{"1 2", 2},
+	}
+	for _, tt := range tests {
+		_, err := Eval(tt.expr)
+		var se *SyntaxError
+		if !errors.As(err, &se) || se.Offset != tt.offset {
+			t.Errorf("Eval(%q): err = %v, want a syntax error at offset %d", tt.expr, err, tt.offset)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrOperandRange is returned when an operand does not fit the fixed-width
+// type, so the native operation cannot even be written down.
+var ErrOperandRange = errors.New("operand out of range")
+
+// ErrDivideByZero is returned for / and % by zero, which panics natively.
+var ErrDivideByZero = errors.New("division by zero")
+
+// IntType describes one of Go's fixed-width integer types.
+type IntType struct {
+	Name   string
+	Bits   uint
+	Signed bool
+}
+
+// IntTypes lists the fixed-width types from narrowest to widest. int and uint
+// are omitted because their width depends on the platform.
+var IntTypes = []IntType{
+	{"int8", 8, true}, {"int16", 16, true}, {"int32", 32, true}, {"int64", 64, true},
+	{"uint8", 8, false}, {"uint16", 16, false}, {"uint32", 32, false}, {"uint64", 64, false},
+}
+
+// Min returns the smallest value of t.
+func (t IntType) Min() *big.Int {
+	if !t.Signed {
+		return new(big.Int)
+	}
+	return new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), t.Bits-1))
+}
+
+// Max returns the largest value of t.
+func (t IntType) Max() *big.Int {
+	bits := t.Bits
+	if t.Signed {
+		bits--
+	}
+	one := big.NewInt(1)
+	return new(big.Int).Sub(new(big.Int).Lsh(one, bits), one)
+}
+
+// Fits reports whether x is representable in t.
+func (t IntType) Fits(x *big.Int) bool {
+	return x.Cmp(t.Min()) >= 0 && x.Cmp(t.Max()) <= 0
+}
+
+// Wrap reduces x modulo 2^Bits into t's range, which is what Go's
+// two's-complement arithmetic does silently on overflow.
+func (t IntType) Wrap(x *big.Int) *big.Int {
+	modulus := new(big.Int).Lsh(big.NewInt(1), t.Bits)
+	wrapped := new(big.Int).Mod(x, modulus) // Mod is Euclidean, so 0 <= wrapped < modulus.
+	if t.Signed && wrapped.Cmp(t.Max()) > 0 {
+		wrapped.Sub(wrapped, modulus)
+	}
+	return wrapped
+}
+
+// NativeResult is the outcome of one operation carried out in a fixed-width type.
+type NativeResult struct {
+	Type     IntType
+	Value    *big.Int // What Go computes; nil if Err is set.
+	Overflow bool     // Value differs from the exact result.
+	Err      error
+}
+
+// Native evaluates a op b in type t using real Go arithmetic on that type, not
+// a simulation, and compares it with the exact result. op is one of + - * / %.
+func Native(t IntType, a *big.Int, op string, b *big.Int) NativeResult {
+	res := NativeResult{Type: t}
+	if !t.Fits(a) || !t.Fits(b) {
+		res.Err = ErrOperandRange
+		return res
+	}
+	exact, err := Exact(a, op, b)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	switch t.Name {
+	case "int8":
+		res.Value, res.Err = signed(int8(a.Int64()), op, int8(b.Int64()))
+	case "int16":
+		res.Value, res.Err = signed(int16(a.Int64()), op, int16(b.Int64()))
+	case "int32":
+		res.Value, res.Err = signed(int32(a.Int64()), op, int32(b.Int64()))
+	case "int64":
+		res.Value, res.Err = signed(a.Int64(), op, b.Int64())
+	case "uint8":
+		res.Value, res.Err = unsigned(uint8(a.Uint64()), op, uint8(b.Uint64()))
+	case "uint16":
+		res.Value, res.Err = unsigned(uint16(a.Uint64()), op, uint16(b.Uint64()))
+	case "uint32":
+		res.Value, res.Err = unsigned(uint32(a.Uint64()), op, uint32(b.Uint64()))
+	case "uint64":
+		res.Value, res.Err = unsigned(a.Uint64(), op, b.Uint64())
+	default:
+		res.Err = fmt.Errorf("unsupported type %s", t.Name)
+	}
+	if res.Err == nil {
+		res.Overflow = res.Value.Cmp(exact) != 0
+	}
+	return res
+}
+
+func signed[T int8 | int16 | int32 | int64](a T, op string, b T) (*big.Int, error) {
+	v, err := apply(a, op, b)
+	return big.NewInt(int64(v)), err
+}
+
+func unsigned[T uint8 | uint16 | uint32 | uint64](a T, op string, b T) (*big.Int, error) {
+	v, err := apply(a, op, b)
+	return new(big.Int).SetUint64(uint64(v)), err
+}
+
+func apply[T int8 | int16 | int32 | int64 | uint8 | uint16 | uint32 | uint64](a T, op string, b T) (T, error) {
+	switch op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/", "%":
+		if b == 0 {
+			return 0, ErrDivideByZero
+		}
+		if op == "/" {
+			return a / b, nil
+		}
+		return a % b, nil
+	}
+	return 0, fmt.Errorf("unsupported operator %q", op)
+}
+
+// Exact computes a op b with math/big. Division truncates toward zero and %
+// takes the sign of a, matching Go's native / and % rather than big.Int's
+// Euclidean Div and Mod.
+func Exact(a *big.Int, op string, b *big.Int) (*big.Int, error) {
+	switch op {
+	case "+":
+		return new(big.Int).Add(a, b), nil
+	case "-":
+		return new(big.Int).Sub(a, b), nil
+	case "*":
+		return new(big.Int).Mul(a, b), nil
+	case "/", "%":
+		if b.Sign() == 0 {
+			return nil, ErrDivideByZero
+		}
+		if op == "/" {
+			return new(big.Int).Quo(a, b), nil
+		}
+		return new(big.Int).Rem(a, b), nil
+	}
+	return nil, fmt.Errorf("unsupported operator %q", op)
+}
@@ -0,0 +1,223 @@
+// Package main is a playground for where Go's fixed-width integers end and
+// math/big begins.
+//
+// SECTION 5 of the Variables and Constants lesson stores 18446744073709551615
+// in a uint64 and stops there. This tool shows what happens one step further:
+// the limits of every fixed-width type, what each type silently computes when
+// an operation overflows, the exact answer from math/big, and conversions
+// between bases 2 to 36.
+//
+//	go run main.go calc.go fixed.go                                          # the lesson's uint64 max, plus one
+//	go run main.go calc.go fixed.go limits
+//	go run main.go calc.go fixed.go overflow 127 + 1                         # every type that can hold the operands
+//	go run main.go calc.go fixed.go overflow -9223372036854775808 / -1
+//	go run main.go calc.go fixed.go calc "2^64 - 1" "100!" "0xFFFF_FFFF * 3"
+//	go run main.go calc.go fixed.go base -from 10 -to 2 18446744073709551615
+//	go run main.go calc.go fixed.go base -from 36 -to 10 zz
+//	go test *.go                                                             # the calculator, including the size limits
+//
+// Exit codes: 0 on success, 1 on runtime errors, 2 on invalid usage.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// usageError marks errors caused by invalid command-line input (exit code 2).
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usageErrorf(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// lessonLargeInt is the value SECTION 5 assigns to largeInt.
+const lessonLargeInt = "18446744073709551615"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("bigint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: bigint [limits | overflow A OP B | calc EXPR... | base -from N -to M VALUE...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	var err error
+	if flags.NArg() == 0 {
+		err = demo(stdout)
+	} else {
+		err = dispatch(flags.Arg(0), flags.Args()[1:], stdout, stderr)
+	}
+	var usageErr *usageError
+	switch {
+	case errors.As(err, &usageErr):
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	case err != nil:
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
+
+func dispatch(cmd string, args []string, stdout, stderr io.Writer) error {
+	switch cmd {
+	case "limits":
+		printLimits(stdout)
+		return nil
+	case "overflow":
+		return cmdOverflow(args, stdout)
+	case "calc":
+		return cmdCalc(args, stdout)
+	case "base":
+		return cmdBase(args, stdout, stderr)
+	default:
+		return usageErrorf("unknown command %q", cmd)
+	}
+}
+
+// demo picks up where the lesson stops: uint64's maximum, then one more.
+func demo(w io.Writer) error {
+	fmt.Fprintf(w, "var largeInt uint64 = %s\n\n", lessonLargeInt)
+	if err := cmdOverflow([]string{lessonLargeInt, "+", "1"}, w); err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+	return cmdCalc([]string{lessonLargeInt + " + 1", "2^64", "(2^64 - 1)^2"}, w)
+}
+
+func printLimits(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tMIN\tMAX\tMAX (hex)")
+	for _, t := range IntTypes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%#x\n", t.Name, t.Min(), t.Max(), t.Max())
+	}
+	tw.Flush()
+}
+
+// cmdOverflow runs A OP B natively in every type that can hold both operands.
+func cmdOverflow(args []string, w io.Writer) error {
+	if len(args) != 3 {
+		return usageErrorf("overflow needs A OP B, e.g. overflow 127 + 1")
+	}
+	a, err := Eval(args[0])
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+	op := args[1]
+	if len(op) != 1 || !strings.Contains("+-*/%", op) {
+		return usageErrorf("operator must be one of + - * / %%, got %q", op)
+	}
+	b, err := Eval(args[2])
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+	exact, err := Exact(a, op, b)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "%s %s %s = %s exactly (math/big)\n", a, op, b, exact)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tGO COMPUTES\tNOTE")
+	for _, t := range IntTypes {
+		res := Native(t, a, op, b)
+		switch {
+		case errors.Is(res.Err, ErrOperandRange):
+			fmt.Fprintf(tw, "%s\t-\toperands do not fit\n", t.Name)
+		case res.Err != nil:
+			fmt.Fprintf(tw, "%s\t-\t%v\n", t.Name, res.Err)
+		case res.Overflow:
+			fmt.Fprintf(tw, "%s\t%s\toverflow: wrapped modulo 2^%d\n", t.Name, res.Value, t.Bits)
+		default:
+			fmt.Fprintf(tw, "%s\t%s\tok\n", t.Name, res.Value)
+		}
+	}
+	tw.Flush()
+	if !fitsAny(exact) {
+		fmt.Fprintln(w, "No fixed-width type can hold the exact result; use math/big.")
+	}
+	return nil
+}
+
+func fitsAny(x *big.Int) bool {
+	for _, t := range IntTypes {
+		if t.Fits(x) {
+			return true
+		}
+	}
+	return false
+}
+
+func cmdCalc(args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return usageErrorf("calc needs at least one expression")
+	}
+	for _, expr := range args {
+		v, err := Eval(expr)
+		var syntaxErr *SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return usageErrorf("%v", err)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s = %s", expr, v)
+		if digits := len(v.Text(10)); digits > 20 {
+			fmt.Fprintf(w, " (%d digits, %d bits)", digits, v.BitLen())
+		}
+		fmt.Fprintln(w)
+	}
+	return nil
+}
+
+func cmdBase(args []string, w io.Writer, stderr io.Writer) error {
+	flags := flag.NewFlagSet("base", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	from := flags.Int("from", 10, "base of the input, 2 to 36")
+	to := flags.Int("to", 16, "base of the output, 2 to 36")
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf("%v", err)
+	}
+	for _, base := range []int{*from, *to} {
+		if base < 2 || base > 36 {
+			return usageErrorf("base must be between 2 and 36, got %d", base)
+		}
+	}
+	if flags.NArg() == 0 {
+		return usageErrorf("base needs at least one value")
+	}
+
+	for _, s := range flags.Args() {
+		// SetString with an explicit base accepts digits 0-9a-z in either case
+		// and rejects prefixes, so "0x10" in base 10 is an error, not 16.
+		v, ok := new(big.Int).SetString(strings.ToLower(s), *from)
+		if !ok {
+			return usageErrorf("%q is not a base-%d number", s, *from)
+		}
+		fmt.Fprintf(w, "%s (base %d) = %s (base %d)\n", s, *from, v.Text(*to), *to)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Encoding identifies a character encoding.
+type Encoding int
+
+const (
+	UTF8        Encoding = iota
+	UTF16                // Byte order from the BOM; big-endian without one (RFC 2781).
+	UTF16LE              // Little-endian; only a little-endian BOM is dropped.
+	UTF16BE              // Big-endian; only a big-endian BOM is dropped.
+	Latin1               // ISO-8859-1: bytes 0x00–0xFF are U+0000–U+00FF.
+	Windows1252          // Latin-1 with printable characters in 0x80–0x9F.
+)
+
+var encodingNames = []struct {
+	enc   Encoding
+	names []string
+}{
+	{UTF8, []string{"utf-8", "utf8"}},
+	{UTF16, []string{"utf-16", "utf16"}},
+	{UTF16LE, []string{"utf-16le", "utf16le"}},
+	{UTF16BE, []string{"utf-16be", "utf16be"}},
+	{Latin1, []string{"latin-1", "latin1", "iso-8859-1"}},
+	{Windows1252, []string{"windows-1252", "cp1252"}},
+}
+
+// ParseEncoding accepts the common names of each encoding, case-insensitively.
+func ParseEncoding(name string) (Encoding, error) {
+	lower := strings.ToLower(name)
+	for _, e := range encodingNames {
+		for _, n := range e.names {
+			if n == lower {
+				return e.enc, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unknown encoding %q", name)
+}
+
+func (e Encoding) String() string {
+	for _, en := range encodingNames {
+		if en.enc == e {
+			return en.names[0]
+		}
+	}
+	return fmt.Sprintf("Encoding(%d)", int(e))
+}
+
+// ErrInvalid is wrapped by every InvalidError.
+var ErrInvalid = errors.New("invalid sequence")
+
+// InvalidError describes bytes that could not be decoded, or a rune that the
+// target encoding cannot represent. Offset counts bytes from the start of the
+// stream being read (for decoders) or written (for encoders).
+type InvalidError struct {
+	Offset   int64
+	Bytes    []byte
+	Encoding Encoding
+	Reason   string
+}
+
+func (e *InvalidError) Error() string {
+	return fmt.Sprintf("%s: %v at byte offset %d (% X): %s", e.Encoding, ErrInvalid, e.Offset, e.Bytes, e.Reason)
+}
+
+func (e *InvalidError) Unwrap() error { return ErrInvalid }
+
+// Options controls what happens on invalid input.
+type Options struct {
+	// Replace substitutes U+FFFD (decoding) or '?' (encoding) for invalid
+	// sequences instead of stopping with an *InvalidError.
+	Replace bool
+	// Report, if set, is called for every invalid sequence, including replaced ones.
+	Report func(*InvalidError)
+}
+
+// handle reports e and decides whether the stream can continue.
+func (o Options) handle(e *InvalidError) error {
+	if o.Report != nil {
+		o.Report(e)
+	}
+	if o.Replace {
+		return nil
+	}
+	return e
+}
+
+// windows1252 maps 0x80–0x9F to runes; zero marks the five undefined bytes.
+var windows1252 = [32]rune{
+	'€', 0, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0, 'Ž', 0,
+	0, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0, 'ž', 'Ÿ',
+}
+
+// windows1252Reverse maps the runes in windows1252 back to their bytes.
+var windows1252Reverse = func() map[rune]byte {
+	m := make(map[rune]byte, len(windows1252))
+	for i, r := range windows1252 {
+		if r != 0 {
+			m[r] = byte(0x80 + i)
+		}
+	}
+	return m
+}()
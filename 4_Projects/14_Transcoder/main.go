@@ -0,0 +1,124 @@
+// Package main implements a streaming character-encoding transcoder.
+//
+// SECTION 5 of the Variables and Constants lesson contrasts rune and byte with
+// 'A' and 'B', where one rune is one byte. Outside ASCII the number of bytes
+// per rune depends on the encoding: "€" is 3 bytes in UTF-8, 2 in UTF-16, 1 in
+// Windows-1252 and cannot be written in Latin-1 at all. This tool converts
+// between UTF-8, UTF-16 (LE, BE or by byte order mark) and Latin-1/Windows-1252
+// with io.Reader/io.Writer wrappers, so files of any size stream through a
+// few kilobytes of memory. Invalid input is reported with its byte offset.
+//
+//	go run main.go encoding.go transcode.go -demo                                     # one string in every encoding
+//	go run main.go encoding.go transcode.go -from utf-16 -to utf-8 < in.txt > out.txt
+//	go run main.go encoding.go transcode.go -from windows-1252 < legacy.txt           # to UTF-8
+//	go run main.go encoding.go transcode.go -to latin-1 -replace < notes.txt          # '?' for unrepresentable runes
+//	go test *.go                                                                      # byte order marks, invalid input and round trips
+//
+// Without -replace, the first invalid sequence stops the conversion with exit
+// code 1; with it, each one is replaced and listed on stderr. Undecodable input
+// is reported by its offset in the input file. Runes the output encoding cannot
+// represent are reported by their offset in the decoded UTF-8 text, since that
+// is the stream the encoder sees.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+// demoText mixes one-, two-, three- and four-byte UTF-8 runes.
+const demoText = "Aé€🚀"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("transcode", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	fromName := flags.String("from", "utf-8", "input encoding: utf-8, utf-16, utf-16le, utf-16be, latin-1, windows-1252")
+	toName := flags.String("to", "utf-8", "output encoding (same names as -from)")
+	replace := flags.Bool("replace", false, "replace invalid sequences instead of stopping")
+	bom := flags.Bool("bom", false, "write a byte order mark (utf-16 output always has one)")
+	demo := flags.Bool("demo", false, "show a sample string in every encoding")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "error: unexpected arguments: %s (input is read from stdin)\n", strings.Join(flags.Args(), " "))
+		return 2
+	}
+
+	if *demo {
+		printDemo(stdout)
+		return 0
+	}
+
+	from, err := ParseEncoding(*fromName)
+	if err != nil {
+		fmt.Fprintln(stderr, "error: -from:", err)
+		return 2
+	}
+	to, err := ParseEncoding(*toName)
+	if err != nil {
+		fmt.Fprintln(stderr, "error: -to:", err)
+		return 2
+	}
+
+	opts := Options{Replace: *replace}
+	if *replace {
+		opts.Report = func(e *InvalidError) {
+			fmt.Fprintln(stderr, "warning: replaced", e)
+		}
+	}
+
+	out := bufio.NewWriter(stdout)
+	enc := NewEncoder(out, to, opts, *bom)
+	_, err = io.Copy(enc, NewDecoder(stdin, from, opts))
+	if closeErr := enc.Close(); err == nil {
+		err = closeErr
+	}
+	// Flush what was converted before any error, so the output ends where the input went bad.
+	if flushErr := out.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
+
+// printDemo encodes demoText in each encoding and shows the bytes.
+func printDemo(w io.Writer) {
+	fmt.Fprintf(w, "%q is %d runes\n\n", demoText, utf8.RuneCountInString(demoText))
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENCODING\tBYTES\tHEX\tNOTE")
+	for _, e := range encodingNames {
+		var b strings.Builder
+		var problems []string
+		opts := Options{Replace: true, Report: func(bad *InvalidError) {
+			problems = append(problems, bad.Reason)
+		}}
+		enc := NewEncoder(&b, e.enc, opts, false)
+		io.WriteString(enc, demoText)
+		enc.Close()
+
+		note := "round-trips"
+		if len(problems) > 0 {
+			note = strings.Join(problems, "; ")
+		}
+		fmt.Fprintf(tw, "%s\t%d\t% X\t%s\n", e.enc, b.Len(), b.String(), note)
+	}
+	tw.Flush()
+}
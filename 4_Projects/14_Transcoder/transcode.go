@@ -0,0 +1,341 @@
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// chunkSize is how much a Decoder reads from its source at a time.
+const chunkSize = 4096
+
+// Decoder is an io.Reader that reads text in some encoding from src and
+// yields UTF-8. A leading byte order mark is consumed, not passed on;
+// for UTF16LE and UTF16BE only one in the declared byte order counts.
+// Sequences split across reads from src are handled. At most three
+// undecoded bytes are held back between reads.
+type Decoder struct {
+	src    io.Reader
+	enc    Encoding
+	opts   Options
+	order  binary.ByteOrder // Resolved byte order for the UTF-16 encodings.
+	in     []byte           // Undecoded input.
+	out    []byte           // Decoded UTF-8 not yet returned by Read.
+	offset int64            // Stream offset of in[0].
+	bom    bool             // Whether the BOM check has happened.
+	err    error            // Sticky error, io.EOF at the end.
+}
+
+// NewDecoder returns a reader that converts src from enc to UTF-8.
+func NewDecoder(src io.Reader, enc Encoding, opts Options) *Decoder {
+	d := &Decoder{src: src, enc: enc, opts: opts, order: binary.BigEndian}
+	if enc == UTF16LE {
+		d.order = binary.LittleEndian
+	}
+	return d
+}
+
+func (d *Decoder) Read(p []byte) (int, error) {
+	for len(d.out) == 0 && d.err == nil {
+		d.fill()
+	}
+	if len(d.out) > 0 {
+		n := copy(p, d.out)
+		d.out = d.out[n:]
+		return n, nil
+	}
+	return 0, d.err
+}
+
+// fill reads one chunk from src and decodes as much of the input as it can.
+func (d *Decoder) fill() {
+	buf := make([]byte, chunkSize)
+	n, readErr := d.src.Read(buf)
+	d.in = append(d.in, buf[:n]...)
+	if err := d.decode(readErr != nil); err != nil {
+		d.err = err
+		return
+	}
+	// Keep only the few held-back bytes so the buffer does not grow.
+	d.in = append([]byte(nil), d.in...)
+	if readErr != nil {
+		d.err = readErr
+	}
+}
+
+// decode converts complete sequences in d.in; final means no more input follows.
+func (d *Decoder) decode(final bool) error {
+	if !d.bom {
+		if !d.checkBOM(final) {
+			return nil
+		}
+		d.bom = true
+	}
+
+	for len(d.in) > 0 {
+		var (
+			r      rune
+			size   int
+			reason string
+		)
+		switch d.enc {
+		case UTF8:
+			if !utf8.FullRune(d.in) && !final {
+				return nil
+			}
+			r, size = utf8.DecodeRune(d.in)
+			if r == utf8.RuneError && size <= 1 {
+				reason = "invalid UTF-8"
+				if !utf8.FullRune(d.in) {
+					size, reason = len(d.in), "truncated UTF-8 sequence"
+				}
+			}
+		case UTF16, UTF16LE, UTF16BE:
+			if len(d.in) < 2 {
+				if !final {
+					return nil
+				}
+				size, reason = len(d.in), "odd number of bytes"
+				break
+			}
+			unit := rune(d.order.Uint16(d.in))
+			r, size = unit, 2
+			switch {
+			case utf16.IsSurrogate(unit) && unit >= 0xDC00:
+				reason = "unpaired low surrogate"
+			case utf16.IsSurrogate(unit):
+				if len(d.in) < 4 {
+					if !final {
+						return nil
+					}
+					reason = "unpaired high surrogate"
+					break
+				}
+				if r = utf16.DecodeRune(unit, rune(d.order.Uint16(d.in[2:]))); r == utf8.RuneError {
+					reason = "unpaired high surrogate"
+					break
+				}
+				size = 4
+			}
+		case Latin1:
+			r, size = rune(d.in[0]), 1
+		case Windows1252:
+			r, size = rune(d.in[0]), 1
+			if r >= 0x80 && r <= 0x9F {
+				if r = windows1252[r-0x80]; r == 0 {
+					reason = "undefined in windows-1252"
+				}
+			}
+		}
+
+		if reason != "" {
+			bad := &InvalidError{Offset: d.offset, Bytes: append([]byte(nil), d.in[:size]...), Encoding: d.enc, Reason: reason}
+			if err := d.opts.handle(bad); err != nil {
+				return err
+			}
+			r = utf8.RuneError
+		}
+		d.out = utf8.AppendRune(d.out, r)
+		d.in = d.in[size:]
+		d.offset += int64(size)
+	}
+	return nil
+}
+
+// checkBOM strips a byte order mark and, for UTF16, picks the byte order.
+// UTF16LE and UTF16BE keep their declared order: only a BOM in that order is
+// dropped, and one in the other order decodes as U+FFFE like any other text.
+// It reports false while too few bytes have arrived to decide.
+func (d *Decoder) checkBOM(final bool) bool {
+	switch d.enc {
+	case UTF8:
+		if len(d.in) < 3 && !final && isPrefix(d.in, "\xEF\xBB\xBF") {
+			return false
+		}
+		if len(d.in) >= 3 && string(d.in[:3]) == "\xEF\xBB\xBF" {
+			d.skip(3)
+		}
+	case UTF16, UTF16LE, UTF16BE:
+		if len(d.in) < 2 {
+			return final
+		}
+		if d.enc == UTF16 {
+			switch string(d.in[:2]) {
+			case "\xFE\xFF":
+				d.order = binary.BigEndian
+				d.skip(2)
+			case "\xFF\xFE":
+				d.order = binary.LittleEndian
+				d.skip(2)
+			}
+		} else if d.order.Uint16(d.in) == 0xFEFF {
+			d.skip(2)
+		}
+	case Latin1, Windows1252:
+		// Single-byte encodings have no byte order mark.
+	}
+	return true
+}
+
+func (d *Decoder) skip(n int) {
+	d.in = d.in[n:]
+	d.offset += int64(n)
+}
+
+func isPrefix(b []byte, s string) bool {
+	return len(b) <= len(s) && string(b) == s[:len(b)]
+}
+
+// Encoder is an io.WriteCloser that accepts UTF-8 and writes it to dst in
+// another encoding. Close must be called to detect a truncated final rune;
+// it does not close dst.
+type Encoder struct {
+	dst     io.Writer
+	enc     Encoding
+	opts    Options
+	bom     bool   // Whether a BOM still has to be written.
+	pending []byte // Incomplete UTF-8 sequence from the previous Write.
+	offset  int64  // Input offset of pending[0].
+	closed  bool
+	err     error // Sticky error from encoding or from dst.
+}
+
+// NewEncoder returns a writer that converts UTF-8 to enc. UTF16 output
+// always starts with a big-endian BOM; set bom to also write one for UTF8,
+// UTF16LE and UTF16BE. Latin-1 and Windows-1252 have no BOM.
+func NewEncoder(dst io.Writer, enc Encoding, opts Options, bom bool) *Encoder {
+	unicode := enc == UTF8 || enc == UTF16 || enc == UTF16LE || enc == UTF16BE
+	return &Encoder{dst: dst, enc: enc, opts: opts, bom: (bom && unicode) || enc == UTF16}
+}
+
+// ErrClosed is returned by Write after Close.
+var ErrClosed = errors.New("write to closed encoder")
+
+func (e *Encoder) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, ErrClosed
+	}
+	if e.err != nil {
+		return 0, e.err
+	}
+	held := len(e.pending)
+	e.pending = append(e.pending, p...)
+	consumed, err := e.encode(false)
+	e.pending = append([]byte(nil), e.pending[consumed:]...)
+	if err != nil {
+		e.err = err
+		return max0(consumed - held), err
+	}
+	return len(p), nil
+}
+
+// Close reports a trailing incomplete UTF-8 sequence and any earlier error.
+func (e *Encoder) Close() error {
+	if e.closed {
+		return e.err
+	}
+	e.closed = true
+	if e.err == nil {
+		_, e.err = e.encode(true)
+		e.pending = nil
+	}
+	return e.err
+}
+
+// encode converts the complete runes in e.pending and writes them out,
+// returning how many input bytes were consumed.
+func (e *Encoder) encode(final bool) (int, error) {
+	var out []byte
+	if e.bom {
+		out = e.appendRune(out, '\uFEFF')
+		e.bom = false
+	}
+
+	in, consumed := e.pending, 0
+	var err error
+	for len(in) > 0 {
+		if !utf8.FullRune(in) && !final {
+			break
+		}
+		r, size := utf8.DecodeRune(in)
+		var bad *InvalidError
+		switch {
+		case r == utf8.RuneError && size <= 1:
+			bad = &InvalidError{Offset: e.offset, Bytes: in[:size], Encoding: UTF8, Reason: "invalid UTF-8 input"}
+			if !utf8.FullRune(in) {
+				size = len(in)
+				bad.Bytes, bad.Reason = in, "truncated UTF-8 input"
+			}
+		case !e.representable(r):
+			bad = &InvalidError{Offset: e.offset, Bytes: in[:size], Encoding: e.enc, Reason: fmt.Sprintf("%U %q is not representable", r, r)}
+		}
+
+		if bad != nil {
+			bad.Bytes = append([]byte(nil), bad.Bytes...)
+			if err = e.opts.handle(bad); err != nil {
+				break
+			}
+			r = '?'
+			if e.enc != Latin1 && e.enc != Windows1252 {
+				r = utf8.RuneError
+			}
+		}
+		out = e.appendRune(out, r)
+		in = in[size:]
+		consumed += size
+		e.offset += int64(size)
+	}
+
+	if len(out) > 0 {
+		if _, werr := e.dst.Write(out); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return consumed, err
+}
+
+func (e *Encoder) representable(r rune) bool {
+	//exhaustive:ignore The Unicode encodings can represent every rune.
+	switch e.enc {
+	case Latin1:
+		return r <= 0xFF
+	case Windows1252:
+		_, ok := windows1252Reverse[r]
+		return r < 0x80 || (r >= 0xA0 && r <= 0xFF) || ok
+	}
+	return true
+}
+
+// appendRune appends r to out in the encoder's encoding; r must be representable.
+func (e *Encoder) appendRune(out []byte, r rune) []byte {
+	switch e.enc {
+	case UTF16, UTF16BE:
+		for _, u := range utf16.Encode([]rune{r}) {
+			out = binary.BigEndian.AppendUint16(out, u)
+		}
+	case UTF16LE:
+		for _, u := range utf16.Encode([]rune{r}) {
+			out = binary.LittleEndian.AppendUint16(out, u)
+		}
+	case Latin1:
+		out = append(out, byte(r))
+	case Windows1252:
+		if b, ok := windows1252Reverse[r]; ok {
+			return append(out, b)
+		}
+		out = append(out, byte(r))
+	case UTF8:
+		out = utf8.AppendRune(out, r)
+	}
+	return out
+}
+
+func max0(n int) int {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// decode reads all of in through a Decoder for enc, one byte per read so
+// every split point is exercised.
+func decode(t *testing.T, in string, enc Encoding) (string, error) {
+	t.Helper()
+	out, err := io.ReadAll(NewDecoder(iotest.OneByteReader(strings.NewReader(in)), enc, Options{}))
+	return string(out), err
+}
+
+func TestDecodeBOM(t *testing.T) {
+	tests := []struct {
+		name string
+		enc  Encoding
+		in   string
+		want string
+	}{
+		{"utf-8 BOM dropped", UTF8, "\xEF\xBB\xBFAé", "Aé"},
+		{"utf-8 without BOM", UTF8, "Aé", "Aé"},
+		{"utf-8 BOM only once", UTF8, "\xEF\xBB\xBF\xEF\xBB\xBFA", "\uFEFFA"},
+		{"utf-16 big-endian BOM", UTF16, "\xFE\xFF\x00A\x20\xAC", "A€"},
+		{"utf-16 little-endian BOM", UTF16, "\xFF\xFEA\x00\xAC\x20", "A€"},
+		{"utf-16 without BOM is big-endian", UTF16, "\x00A\x20\xAC", "A€"},
+		{"utf-16le matching BOM dropped", UTF16LE, "\xFF\xFEA\x00", "A"},
+		{"utf-16be matching BOM dropped", UTF16BE, "\xFE\xFF\x00A", "A"},
+
+		// A declared byte order wins over a BOM in the other order, which
+		// decodes as U+FFFE instead of switching the rest of the stream.
+		{"utf-16le keeps its order", UTF16LE, "\xFE\xFFA\x00", "\uFFFEA"},
+		{"utf-16be keeps its order", UTF16BE, "\xFF\xFE\x00A", "\uFFFEA"},
+
+		{"latin-1 has no BOM", Latin1, "\xEF\xBB\xBF", "ï»¿"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decode(t, tt.in, tt.enc)
+			if err != nil || got != tt.want {
+				t.Errorf("decode(%q, %s) = %q, %v; want %q", tt.in, tt.enc, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	tests := []struct {
+		enc    Encoding
+		in     string
+		offset int64
+		reason string
+	}{
+		{UTF8, "ab\xFFc", 2, "invalid UTF-8"},
+		{UTF8, "ab\xE2\x82", 2, "truncated UTF-8 sequence"},
+		{UTF16BE, "\x00A\xDC\x00", 2, "unpaired low surrogate"},
+		{UTF16LE, "A\x00\x3D\xD8", 2, "unpaired high surrogate"},
+		{UTF16LE, "\xFF\xFEA\x00B", 4, "odd number of bytes"}, // Offsets count the BOM.
+		{Windows1252, "a\x81", 1, "undefined in windows-1252"},
+	}
+	for _, tt := range tests {
+		_, err := decode(t, tt.in, tt.enc)
+		var ie *InvalidError
+		if !errors.As(err, &ie) || !errors.Is(err, ErrInvalid) || ie.Offset != tt.offset || ie.Reason != tt.reason {
+			t.Errorf("decode(%q, %s): err = %v, want %s at offset %d", tt.in, tt.enc, err, tt.reason, tt.offset)
+		}
+	}
+}
+
+func TestEncoderRoundTrip(t *testing.T) {
+	for _, enc := range []Encoding{UTF8, UTF16, UTF16LE, UTF16BE} {
+		for _, bom := range []bool{false, true} {
+			var buf bytes.Buffer
+			e := NewEncoder(&buf, enc, Options{}, bom)
+			if _, err := io.Copy(e, iotest.OneByteReader(strings.NewReader(demoText))); err != nil {
+				t.Fatal(err)
+			}
+			if err := e.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := decode(t, buf.String(), enc); err != nil || got != demoText {
+				t.Errorf("%s, bom %v: round trip gave %q, %v", enc, bom, got, err)
+			}
+		}
+	}
+}
+
+func TestEncoderReplace(t *testing.T) {
+	var buf bytes.Buffer
+	var reported []int64
+	e := NewEncoder(&buf, Latin1, Options{Replace: true, Report: func(ie *InvalidError) {
+		reported = append(reported, ie.Offset)
+	}}, false)
+	if _, err := io.WriteString(e, demoText); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "A\xE9??" {
+		t.Errorf("Latin-1 output %q, want %q", got, "A\xE9??")
+	}
+	if len(reported) != 2 || reported[0] != 3 || reported[1] != 6 {
+		t.Errorf("reported offsets %v, want [3 6]", reported)
+	}
+}
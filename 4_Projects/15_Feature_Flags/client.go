@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Client evaluates flags against the current Set. Reads are lock-free: a
+// reload swaps the whole Set atomically, so one evaluation never sees half
+// of an old file and half of a new one.
+type Client struct {
+	set atomic.Pointer[Set]
+
+	mu        sync.RWMutex
+	overrides map[string]any
+}
+
+// NewClient returns a client serving set.
+func NewClient(set Set) *Client {
+	c := &Client{overrides: map[string]any{}}
+	c.set.Store(&set)
+	return c
+}
+
+// LoadFile reads and parses a flags file.
+func LoadFile(path string) (Set, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSet(data)
+}
+
+// Set returns the flags currently being served.
+func (c *Client) Set() Set {
+	return *c.set.Load()
+}
+
+// Replace atomically swaps in a new set of flags.
+func (c *Client) Replace(set Set) {
+	c.set.Store(&set)
+}
+
+// Evaluate returns the value of name for the user key, honoring overrides.
+func (c *Client) Evaluate(name, key string) (Evaluation, error) {
+	c.mu.RLock()
+	v, ok := c.overrides[name]
+	c.mu.RUnlock()
+	if ok {
+		return Evaluation{Flag: name, Value: v, Reason: "overridden"}, nil
+	}
+	return c.Set().Evaluate(name, key)
+}
+
+// Bool evaluates a bool or percentage flag.
+func (c *Client) Bool(name, key string) (bool, error) {
+	ev, err := c.Evaluate(name, key)
+	if err != nil {
+		return false, err
+	}
+	b, ok := ev.Value.(bool)
+	if !ok {
+		return false, fmt.Errorf("%w: %q is a string flag", ErrFlagType, name)
+	}
+	return b, nil
+}
+
+// String evaluates a string flag.
+func (c *Client) String(name string) (string, error) {
+	ev, err := c.Evaluate(name, "")
+	if err != nil {
+		return "", err
+	}
+	s, ok := ev.Value.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %q is not a string flag", ErrFlagType, name)
+	}
+	return s, nil
+}
+
+// Override forces name to value (a bool or string) for every key, even if
+// the flag is not defined in the file, until the returned restore function
+// is called. Overrides survive reloads.
+func (c *Client) Override(name string, value any) (restore func(), err error) {
+	switch value.(type) {
+	case bool, string:
+	default:
+		return nil, fmt.Errorf("%w: override for %q must be a bool or string, got %T", ErrFlagType, name, value)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	previous, had := c.overrides[name]
+	c.overrides[name] = value
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if had {
+			c.overrides[name] = previous
+		} else {
+			delete(c.overrides, name)
+		}
+	}, nil
+}
+
+// TB is the part of testing.TB that OverrideForTest needs, so this package
+// does not import testing outside of tests.
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...any)
+	Cleanup(func())
+}
+
+// OverrideForTest overrides a flag for the duration of one test:
+//
+//	func TestVerboseGreeting(t *testing.T) {
+//		OverrideForTest(t, client, "debug", true)
+//		...
+//	}
+//
+// The override is removed when the test and its subtests finish. Tests that
+// override the same client must not run in parallel.
+func OverrideForTest(t TB, c *Client, name string, value any) {
+	t.Helper()
+	restore, err := c.Override(name, value)
+	if err != nil {
+		t.Fatalf("OverrideForTest: %v", err)
+	}
+	t.Cleanup(restore)
+}
+
+// Watch polls path every interval and swaps in the new flags whenever the
+// file's size or modification time changes. A file that fails to parse is
+// reported to onError and the previous flags stay in effect. Either callback
+// may be nil. Watch returns when ctx is done.
+func (c *Client) Watch(ctx context.Context, path string, interval time.Duration, onReload func(Set), onError func(error)) {
+	w := newFileWatch(path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		c.poll(w, onReload, onError)
+	}
+}
+
+// fileWatch is what Watch remembers about the file between polls.
+type fileWatch struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// newFileWatch records the current state of path, so that only later
+// changes count as reloads.
+func newFileWatch(path string) *fileWatch {
+	w := &fileWatch{path: path, size: -1}
+	if info, err := os.Stat(path); err == nil {
+		w.modTime, w.size = info.ModTime(), info.Size()
+	}
+	return w
+}
+
+// poll is one tick of Watch: it reloads the file if it changed since the
+// last poll. Nil callbacks are skipped.
+func (c *Client) poll(w *fileWatch, onReload func(Set), onError func(error)) {
+	report := func(err error) {
+		if onError != nil {
+			onError(err)
+		}
+	}
+	info, err := os.Stat(w.path)
+	if err != nil {
+		report(err)
+		return
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+	set, err := LoadFile(w.path)
+	if err != nil {
+		report(fmt.Errorf("reload %s: %w (keeping previous flags)", w.path, err))
+		return
+	}
+	c.Replace(set)
+	if onReload != nil {
+		onReload(set)
+	}
+}
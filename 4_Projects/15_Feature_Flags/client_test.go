@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testFlags = `{
+	"debug": {"type": "bool", "value": false},
+	"greeting_style": {"type": "string", "value": "formal"},
+	"new_scheduler": {"type": "percentage", "percent": 0, "include": ["alice"]}
+}`
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	set, err := ParseSet([]byte(testFlags))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewClient(set)
+}
+
+func TestOverrideForTest(t *testing.T) {
+	client := newTestClient(t)
+
+	t.Run("override", func(t *testing.T) {
+		OverrideForTest(t, client, "debug", true)
+		OverrideForTest(t, client, "greeting_style", "casual")
+		OverrideForTest(t, client, "new_scheduler", true)
+
+		if on, err := client.Bool("debug", ""); err != nil || !on {
+			t.Errorf("debug = %v, %v; want true", on, err)
+		}
+		if s, err := client.String("greeting_style"); err != nil || s != "casual" {
+			t.Errorf("greeting_style = %q, %v; want casual", s, err)
+		}
+		if on, err := client.Bool("new_scheduler", "bob"); err != nil || !on {
+			t.Errorf("new_scheduler for bob = %v, %v; want true", on, err)
+		}
+		if ev, _ := client.Evaluate("debug", ""); ev.Reason != "overridden" {
+			t.Errorf("reason = %q, want overridden", ev.Reason)
+		}
+	})
+
+	// The cleanup registered by OverrideForTest has run by now.
+	if on, err := client.Bool("debug", ""); err != nil || on {
+		t.Errorf("debug after subtest = %v, %v; want false", on, err)
+	}
+	if s, err := client.String("greeting_style"); err != nil || s != "formal" {
+		t.Errorf("greeting_style after subtest = %q, %v; want formal", s, err)
+	}
+	if on, err := client.Bool("new_scheduler", "bob"); err != nil || on {
+		t.Errorf("new_scheduler for bob after subtest = %v, %v; want false", on, err)
+	}
+}
+
+func TestOverrideForTestNested(t *testing.T) {
+	client := newTestClient(t)
+	OverrideForTest(t, client, "debug", true)
+	t.Run("inner", func(t *testing.T) {
+		OverrideForTest(t, client, "debug", false)
+		if on, _ := client.Bool("debug", ""); on {
+			t.Error("inner override not applied")
+		}
+	})
+	if on, _ := client.Bool("debug", ""); !on {
+		t.Error("outer override lost after the inner one was restored")
+	}
+}
+
+func TestOverrideForTestRejectsBadValue(t *testing.T) {
+	client := newTestClient(t)
+	ft := &fakeTB{}
+	OverrideForTest(ft, client, "debug", 1)
+	if !ft.failed {
+		t.Error("an int override did not fail the test")
+	}
+	if _, err := client.Override("debug", 1.5); !errors.Is(err, ErrFlagType) {
+		t.Errorf("Override(1.5) error = %v, want ErrFlagType", err)
+	}
+}
+
+func TestOverrideSurvivesReload(t *testing.T) {
+	client := newTestClient(t)
+	OverrideForTest(t, client, "debug", true)
+	client.Replace(Set{})
+	if on, err := client.Bool("debug", ""); err != nil || !on {
+		t.Errorf("debug after reload = %v, %v; want true", on, err)
+	}
+	if _, err := client.Bool("new_scheduler", "alice"); !errors.Is(err, ErrUnknownFlag) {
+		t.Errorf("new_scheduler after reload: error = %v, want ErrUnknownFlag", err)
+	}
+}
+
+// recorder collects what poll and Watch report.
+type recorder struct {
+	reloads []Set
+	errs    []error
+}
+
+func (r *recorder) onReload(s Set)    { r.reloads = append(r.reloads, s) }
+func (r *recorder) onError(err error) { r.errs = append(r.errs, err) }
+
+func writeFlags(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestPoll drives Watch's polling one tick at a time. Each write changes the
+// file's size, so it is noticed even when the modification time does not move.
+func TestPoll(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	writeFlags(t, path, testFlags)
+	client := newTestClient(t)
+	w := newFileWatch(path)
+	var rec recorder
+
+	client.poll(w, rec.onReload, rec.onError)
+	if len(rec.reloads) != 0 || len(rec.errs) != 0 {
+		t.Fatalf("unchanged file: %d reloads, %d errors; want none", len(rec.reloads), len(rec.errs))
+	}
+
+	writeFlags(t, path, `not json`)
+	client.poll(w, rec.onReload, rec.onError)
+	if len(rec.errs) != 1 || len(rec.reloads) != 0 {
+		t.Fatalf("bad file: %d reloads, errors %v; want one error", len(rec.reloads), rec.errs)
+	}
+	if _, ok := client.Set()["greeting_style"]; !ok {
+		t.Error("a bad file replaced the previous flags")
+	}
+
+	writeFlags(t, path, `{"debug": {"type": "bool", "value": true}}`)
+	client.poll(w, rec.onReload, rec.onError)
+	if len(rec.reloads) != 1 || len(rec.errs) != 1 {
+		t.Fatalf("good file: %d reloads, %d errors; want one reload", len(rec.reloads), len(rec.errs))
+	}
+	if got := rec.reloads[0].Names(); len(got) != 1 || got[0] != "debug" {
+		t.Errorf("reloaded flags = %v, want [debug]", got)
+	}
+	if on, err := client.Bool("debug", ""); err != nil || !on {
+		t.Errorf("debug after reload = %v, %v; want true", on, err)
+	}
+
+	client.poll(w, rec.onReload, rec.onError)
+	if len(rec.reloads) != 1 {
+		t.Error("an unchanged file was reloaded again")
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	client.poll(w, rec.onReload, rec.onError)
+	if len(rec.errs) != 2 || !errors.Is(rec.errs[1], os.ErrNotExist) {
+		t.Errorf("missing file: errors %v, want a not-exist error", rec.errs)
+	}
+}
+
+func TestPollNilCallbacks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	client := newTestClient(t)
+	w := newFileWatch(path)
+
+	client.poll(w, nil, nil) // Missing file.
+	writeFlags(t, path, `not json`)
+	client.poll(w, nil, nil)
+	writeFlags(t, path, `{"debug": {"type": "bool", "value": true}}`)
+	client.poll(w, nil, nil)
+	if on, err := client.Bool("debug", ""); err != nil || !on {
+		t.Errorf("debug after reload = %v, %v; want true", on, err)
+	}
+}
+
+// TestWatch checks that Watch polls on its own and returns when ctx is done.
+// A missing file makes every tick report an error, so the test only waits
+// for callbacks, never for the clock.
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.json")
+	client := newTestClient(t)
+	errs := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		client.Watch(ctx, path, time.Millisecond, nil, func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		})
+	}()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("onError(%v), want a not-exist error", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Watch never polled")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Watch did not return after cancel")
+	}
+}
+
+// fakeTB records a failure instead of stopping the test.
+type fakeTB struct {
+	failed   bool
+	cleanups []func()
+}
+
+func (f *fakeTB) Helper()               {}
+func (f *fakeTB) Fatalf(string, ...any) { f.failed = true }
+func (f *fakeTB) Cleanup(fn func())     { f.cleanups = append(f.cleanups, fn) }
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrUnknownFlag is returned when a flag is not defined.
+	ErrUnknownFlag = errors.New("unknown flag")
+	// ErrFlagType is returned when a flag is read as the wrong type.
+	ErrFlagType = errors.New("wrong flag type")
+)
+
+// FlagType is the kind of value a flag produces.
+type FlagType string
+
+const (
+	BoolFlag       FlagType = "bool"       // On or off for everyone.
+	StringFlag     FlagType = "string"     // One string value for everyone.
+	PercentageFlag FlagType = "percentage" // On for a stable share of user keys.
+)
+
+// Flag is one definition from the flags file.
+type Flag struct {
+	Name        string   `json:"-"`
+	Type        FlagType `json:"type"`
+	Description string   `json:"description,omitempty"`
+
+	Value json.RawMessage `json:"value,omitempty"` // For bool and string flags.
+
+	// For percentage flags.
+	Percent float64  `json:"percent,omitempty"` // 0–100, two decimals of precision.
+	Include []string `json:"include,omitempty"` // Keys that are always on.
+	Exclude []string `json:"exclude,omitempty"` // Keys that are always off.
+	Salt    string   `json:"salt,omitempty"`    // Reshuffles buckets; defaults to the flag name.
+
+	boolValue   bool
+	stringValue string
+}
+
+// Set is an immutable collection of flags, as parsed from one version of the file.
+type Set map[string]*Flag
+
+// ParseSet decodes a flags file: a JSON object mapping names to definitions.
+// Every definition is checked, and all problems are reported together.
+func ParseSet(data []byte) (Set, error) {
+	var raw map[string]*Flag
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("flags file: %w", err)
+	}
+	var problems []string
+	for name, f := range raw {
+		if f == nil {
+			problems = append(problems, fmt.Sprintf("%s: null definition", name))
+			continue
+		}
+		f.Name = name
+		if err := f.validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("flags file: %s", strings.Join(problems, "; "))
+	}
+	return Set(raw), nil
+}
+
+func (f *Flag) validate() error {
+	switch f.Type {
+	case BoolFlag:
+		if err := json.Unmarshal(f.Value, &f.boolValue); err != nil {
+			return fmt.Errorf("bool flag needs \"value\": true or false")
+		}
+	case StringFlag:
+		if err := json.Unmarshal(f.Value, &f.stringValue); err != nil {
+			return fmt.Errorf("string flag needs a string \"value\"")
+		}
+	case PercentageFlag:
+		if f.Percent < 0 || f.Percent > 100 {
+			return fmt.Errorf("percent must be between 0 and 100, got %v", f.Percent)
+		}
+		if f.Salt == "" {
+			f.Salt = f.Name
+		}
+	default:
+		return fmt.Errorf("unknown type %q (want bool, string or percentage)", f.Type)
+	}
+	return nil
+}
+
+// Names returns the flag names in sorted order.
+func (s Set) Names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Evaluation is a flag's value for one user key and why it has that value.
+type Evaluation struct {
+	Flag   string
+	Value  any // bool or string.
+	Reason string
+}
+
+// Evaluate computes the value of flag name for key. Bool and string flags
+// ignore the key.
+func (s Set) Evaluate(name, key string) (Evaluation, error) {
+	f, ok := s[name]
+	if !ok {
+		return Evaluation{}, fmt.Errorf("%w %q", ErrUnknownFlag, name)
+	}
+	ev := Evaluation{Flag: name}
+	switch f.Type {
+	case BoolFlag:
+		ev.Value, ev.Reason = f.boolValue, "static value"
+	case StringFlag:
+		ev.Value, ev.Reason = f.stringValue, "static value"
+	case PercentageFlag:
+		switch {
+		case contains(f.Exclude, key):
+			ev.Value, ev.Reason = false, "key is excluded"
+		case contains(f.Include, key):
+			ev.Value, ev.Reason = true, "key is included"
+		default:
+			b := bucket(f.Salt, key)
+			on := float64(b) < f.Percent*100
+			cmp := ">="
+			if on {
+				cmp = "<"
+			}
+			ev.Value, ev.Reason = on, fmt.Sprintf("bucket %d %s %v for a %v%% rollout", b, cmp, f.Percent*100, f.Percent)
+		}
+	}
+	return ev, nil
+}
+
+// bucket maps a key to 0–9999 with FNV-1a. The same key always lands in the
+// same bucket, so raising the percentage only ever adds users, and the salt
+// keeps different flags from enabling the same users first.
+func bucket(salt, key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(salt))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return h.Sum32() % 10000
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// rollout returns a set holding one percentage flag named "rollout".
+func rollout(t *testing.T, percent float64, extra string) Set {
+	t.Helper()
+	set, err := ParseSet([]byte(fmt.Sprintf(`{"rollout": {"type": "percentage", "percent": %v%s}}`, percent, extra)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return set
+}
+
+// share returns the fraction of keys user0…user(n-1) for which flag is on.
+func share(t *testing.T, set Set, n int) float64 {
+	t.Helper()
+	on := 0
+	for i := 0; i < n; i++ {
+		ev, err := set.Evaluate("rollout", fmt.Sprintf("user%d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ev.Value.(bool) {
+			on++
+		}
+	}
+	return float64(on) / float64(n)
+}
+
+func TestBucketStable(t *testing.T) {
+	for _, key := range []string{"", "alice", "bob", "user42", "ünïcødé"} {
+		b := bucket("rollout", key)
+		if b >= 10000 {
+			t.Errorf("bucket(rollout, %q) = %d, want < 10000", key, b)
+		}
+		for i := 0; i < 3; i++ {
+			if again := bucket("rollout", key); again != b {
+				t.Fatalf("bucket(rollout, %q) = %d, then %d", key, b, again)
+			}
+		}
+	}
+	// Buckets must not change between releases: a new hash would move users
+	// in and out of every running rollout.
+	if got := bucket("new_scheduler", "bob"); got != 4308 {
+		t.Errorf("bucket(new_scheduler, bob) = %d, want 4308", got)
+	}
+	// The salt and the key are separated, so ("ab", "c") and ("a", "bc") differ.
+	if bucket("ab", "c") == bucket("a", "bc") {
+		t.Error("salt and key run together")
+	}
+}
+
+func TestBucketSaltReshuffles(t *testing.T) {
+	same := 0
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("user%d", i)
+		if bucket("one", key) == bucket("two", key) {
+			same++
+		}
+	}
+	if same > 10 {
+		t.Errorf("%d of 1000 keys share a bucket under different salts", same)
+	}
+}
+
+func TestPercentageBounds(t *testing.T) {
+	if got := share(t, rollout(t, 0, ""), 10000); got != 0 {
+		t.Errorf("0%% rollout is on for %.2f%% of keys", got*100)
+	}
+	if got := share(t, rollout(t, 100, ""), 10000); got != 1 {
+		t.Errorf("100%% rollout is on for %.2f%% of keys", got*100)
+	}
+}
+
+func TestPercentageShare(t *testing.T) {
+	for _, percent := range []float64{0.5, 10, 25, 50, 90} {
+		got := share(t, rollout(t, percent, ""), 20000) * 100
+		if got < percent*0.9-0.2 || got > percent*1.1+0.2 {
+			t.Errorf("%v%% rollout is on for %.2f%% of keys", percent, got)
+		}
+	}
+}
+
+// TestPercentageOnlyAdds checks that raising the percentage keeps every key
+// that was already on.
+func TestPercentageOnlyAdds(t *testing.T) {
+	low, high := rollout(t, 20, ""), rollout(t, 30, "")
+	for i := 0; i < 5000; i++ {
+		key := fmt.Sprintf("user%d", i)
+		l, _ := low.Evaluate("rollout", key)
+		h, _ := high.Evaluate("rollout", key)
+		if l.Value.(bool) && !h.Value.(bool) {
+			t.Fatalf("%s is on at 20%% but off at 30%%", key)
+		}
+	}
+}
+
+func TestPercentageIncludeExclude(t *testing.T) {
+	set := rollout(t, 50, `, "include": ["alice", "bob"], "exclude": ["bob", "carol"]`)
+	tests := []struct {
+		key    string
+		on     bool
+		reason string
+	}{
+		{"alice", true, "key is included"},
+		{"bob", false, "key is excluded"}, // Exclude wins.
+		{"carol", false, "key is excluded"},
+	}
+	for _, tt := range tests {
+		ev, err := set.Evaluate("rollout", tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ev.Value != tt.on || ev.Reason != tt.reason {
+			t.Errorf("%s: got %v (%s), want %v (%s)", tt.key, ev.Value, ev.Reason, tt.on, tt.reason)
+		}
+	}
+}
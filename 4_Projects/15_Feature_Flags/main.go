@@ -0,0 +1,153 @@
+// Package main implements feature flags, growing the "debug": false field of
+// the raw config in the Variables and Constants lesson into runtime toggles.
+//
+// Flags are defined in a JSON file (see flags.json) and come in three types:
+//
+//	bool        {"type": "bool", "value": false}
+//	string      {"type": "string", "value": "formal"}
+//	percentage  {"type": "percentage", "percent": 25, "include": ["alice"]}
+//
+// A percentage flag is on for a stable share of user keys: each key hashes to
+// one of 10,000 buckets, so a user keeps their answer between runs and
+// raising the percentage only adds users. The Client reloads the file when it
+// changes, and OverrideForTest pins a flag for the length of one test.
+//
+//	go run main.go flags.go client.go                         # every flag for a few sample users
+//	go run main.go flags.go client.go -user carol             # one user
+//	go run main.go flags.go client.go -rollout new_scheduler  # check the real share over 10,000 keys
+//	go run main.go flags.go client.go -watch                  # edit flags.json while this runs
+//	go test *.go
+//
+// When the debug flag is on, each value is printed with the reason it was chosen.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// sampleUsers are evaluated when no -user is given.
+var sampleUsers = []string{"alice", "bob", "carol", "dave", "erin"}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("featureflags", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	file := flags.String("file", "flags.json", "flags file")
+	user := flags.String("user", "", "user key to evaluate (default: a few sample users)")
+	rollout := flags.String("rollout", "", "measure the share of 10,000 synthetic users that get this flag")
+	watch := flags.Bool("watch", false, "keep running and reload the file when it changes")
+	interval := flags.Duration("interval", time.Second, "polling interval for -watch")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if *interval <= 0 {
+		fmt.Fprintln(stderr, "error: -interval must be positive")
+		return 2
+	}
+
+	set, err := LoadFile(*file)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	client := NewClient(set)
+
+	if *rollout != "" {
+		if err := measureRollout(stdout, client, *rollout); err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			if errors.Is(err, ErrUnknownFlag) || errors.Is(err, ErrFlagType) {
+				return 2
+			}
+			return 1
+		}
+		return 0
+	}
+
+	users := sampleUsers
+	if *user != "" {
+		users = []string{*user}
+	}
+	printFlags(stdout, client, users)
+	if !*watch {
+		return 0
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	fmt.Fprintf(stdout, "\nwatching %s every %s; press Ctrl+C to stop\n", *file, *interval)
+	client.Watch(ctx, *file, *interval,
+		func(Set) {
+			fmt.Fprintf(stdout, "\n%s reloaded at %s\n", *file, time.Now().Format(time.TimeOnly))
+			printFlags(stdout, client, users)
+		},
+		func(err error) {
+			fmt.Fprintln(stderr, "warning:", err)
+		})
+	return 0
+}
+
+// printFlags prints one row per flag and one column per user. The debug flag
+// itself decides whether the reasons are shown.
+func printFlags(w io.Writer, client *Client, users []string) {
+	verbose, _ := client.Bool("debug", "")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprint(tw, "FLAG")
+	for _, u := range users {
+		fmt.Fprintf(tw, "\t%s", u)
+	}
+	fmt.Fprintln(tw)
+	for _, name := range client.Set().Names() {
+		fmt.Fprint(tw, name)
+		for _, u := range users {
+			ev, err := client.Evaluate(name, u)
+			if err != nil {
+				fmt.Fprintf(tw, "\terror: %v", err)
+				continue
+			}
+			if verbose {
+				fmt.Fprintf(tw, "\t%v (%s)", ev.Value, ev.Reason)
+			} else {
+				fmt.Fprintf(tw, "\t%v", ev.Value)
+			}
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+}
+
+// measureRollout evaluates a flag for many synthetic keys to show that the
+// hash spreads users evenly.
+func measureRollout(w io.Writer, client *Client, name string) error {
+	const keys = 10000
+	on := 0
+	for i := 0; i < keys; i++ {
+		enabled, err := client.Bool(name, "user-"+strconv.Itoa(i))
+		if err != nil {
+			return err
+		}
+		if enabled {
+			on++
+		}
+	}
+	configured := "n/a"
+	if f := client.Set()[name]; f.Type == PercentageFlag {
+		configured = fmt.Sprintf("%v%%", f.Percent)
+	}
+	fmt.Fprintf(w, "%s: on for %d of %d keys (%.2f%%), configured %s\n", name, on, keys, float64(on)*100/keys, configured)
+	return nil
+}
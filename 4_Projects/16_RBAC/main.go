@@ -0,0 +1,130 @@
+// Package main implements role-based access control (RBAC), replacing the
+// hard-coded check from the Control Statements lesson:
+//
+//	if userLoggedIn && userRole == "admin" { ... }
+//
+// A policy file (see policy.json) defines roles with permissions, roles that
+// inherit other roles, bindings that give a subject a role on a subtree of
+// resources, and deny rules that override everything. Authorize answers
+// "may this subject do this action to this resource?" and names the rule
+// that decided it, so every answer can be explained.
+//
+//	go run main.go rbac.go pattern.go                                          # a table of sample requests
+//	go run main.go rbac.go pattern.go bob write lessons/foundations/4_functions
+//	go run main.go rbac.go pattern.go alice delete lessons/foundations/8_errors  # admin, but a deny rule wins
+//	go run main.go rbac.go pattern.go -roles                                   # effective permissions per role
+//	go run main.go rbac.go pattern.go -policy my-policy.json carol read lessons/projects/1_i18n
+//	go test *.go
+//
+// Exit codes: 0 when the decision is printed, 1 on runtime errors, 2 on invalid usage.
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// defaultPolicy is compiled in so the demo runs from anywhere.
+//
+//go:embed policy.json
+var defaultPolicy []byte
+
+// sampleRequests exercise inheritance, scoped bindings, the wildcard subject
+// and both deny rules.
+var sampleRequests = [][3]string{
+	{"alice", "delete", "lessons/projects/4_task_manager"},
+	{"alice", "delete", "lessons/foundations/8_errors"},
+	{"bob", "write", "lessons/foundations/4_functions"},
+	{"bob", "read", "lessons/foundations/4_functions"},
+	{"bob", "write", "lessons/foundations/8_errors"},
+	{"bob", "publish", "lessons/foundations/4_functions"},
+	{"bob", "read", "lessons/projects/1_i18n"},
+	{"carol", "publish", "lessons/projects/1_i18n"},
+	{"carol", "write", "lessons/foundations/4_functions"},
+	{"dave", "read", "lessons/foundations/1_hello_world"},
+	{"dave", "read", "lessons/foundations/2_variables_constants"},
+	{"", "read", "lessons/foundations/1_hello_world"},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("rbac", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	policyFile := flags.String("policy", "", "policy file (default: the built-in policy.json)")
+	roles := flags.Bool("roles", false, "list every role with its effective permissions")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	data := defaultPolicy
+	if *policyFile != "" {
+		var err error
+		if data, err = os.ReadFile(*policyFile); err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+	}
+	policy, err := ParsePolicy(data)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+
+	switch {
+	case *roles:
+		printRoles(stdout, policy)
+	case flags.NArg() == 3:
+		fmt.Fprintln(stdout, policy.Authorize(flags.Arg(0), flags.Arg(1), flags.Arg(2)))
+	case flags.NArg() == 0:
+		printSamples(stdout, policy)
+	default:
+		fmt.Fprintln(stderr, "error: want SUBJECT ACTION RESOURCE, or no arguments for the sample table")
+		return 2
+	}
+	return 0
+}
+
+func printSamples(w io.Writer, policy *Policy) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SUBJECT\tACTION\tRESOURCE\tDECISION\tBECAUSE")
+	for _, req := range sampleRequests {
+		d := policy.Authorize(req[0], req[1], req[2])
+		verdict := "deny"
+		if d.Allowed {
+			verdict = "allow"
+		}
+		subject := d.Subject
+		if subject == "" {
+			subject = "(anonymous)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", subject, d.Action, d.Resource, verdict, d.Rule)
+	}
+	tw.Flush()
+}
+
+func printRoles(w io.Writer, policy *Policy) {
+	for _, name := range sortedKeys(policy.Roles) {
+		perms := policy.Effective(name)
+		names := make([]string, len(perms))
+		for i, p := range perms {
+			names[i] = p.String()
+		}
+		inherits := ""
+		if r := policy.Roles[name]; len(r.Inherits) > 0 {
+			inherits = " (inherits " + strings.Join(r.Inherits, ", ") + ")"
+		}
+		fmt.Fprintf(w, "%s%s: %s\n", name, inherits, strings.Join(names, ", "))
+	}
+}
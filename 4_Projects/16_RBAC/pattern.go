@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// MatchResource reports whether a slash-separated resource matches pattern.
+// Each pattern segment is matched with path.Match, so "*" matches exactly one
+// segment and "lesson_?" one character. A "**" segment matches zero or more
+// whole segments: "lessons/**" matches "lessons", "lessons/a" and
+// "lessons/a/b".
+func MatchResource(pattern, resource string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(resource, "/"))
+}
+
+func matchSegments(pattern, resource []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Try every possible length for the "**" run, shortest first.
+			for skip := 0; skip <= len(resource); skip++ {
+				if matchSegments(pattern[1:], resource[skip:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(resource) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], resource[0]); !ok {
+			return false
+		}
+		pattern, resource = pattern[1:], resource[1:]
+	}
+	return len(resource) == 0
+}
+
+// CleanResource reports whether resource has no empty, "." or ".."
+// segments, the only form MatchResource compares correctly.
+func CleanResource(resource string) bool {
+	for _, seg := range strings.Split(resource, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// checkPattern rejects patterns path.Match cannot parse and "**" mixed into a segment.
+func checkPattern(pattern string) error {
+	for _, seg := range strings.Split(pattern, "/") {
+		if seg == "**" {
+			continue
+		}
+		if strings.Contains(seg, "**") {
+			return fmt.Errorf("pattern %q: ** must be a whole segment", pattern)
+		}
+		if _, err := path.Match(seg, ""); err != nil {
+			return fmt.Errorf("pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
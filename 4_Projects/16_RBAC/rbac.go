@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AnySubject in a binding or deny rule matches every authenticated subject.
+const AnySubject = "*"
+
+// ErrPolicy is wrapped by every error from ParsePolicy.
+var ErrPolicy = errors.New("invalid policy")
+
+// Permission allows one action, optionally only on resources matching a pattern.
+// In the policy file it is written "action" or "action:pattern", e.g.
+// "read" or "publish:lessons/projects/**". The action "*" allows everything.
+type Permission struct {
+	Action   string
+	Resource string // Pattern; "**" (everything) when omitted.
+}
+
+func (p Permission) String() string {
+	if p.Resource == "**" {
+		return p.Action
+	}
+	return p.Action + ":" + p.Resource
+}
+
+// UnmarshalText parses the "action[:pattern]" form.
+func (p *Permission) UnmarshalText(text []byte) error {
+	action, resource, ok := strings.Cut(string(text), ":")
+	if !ok {
+		resource = "**"
+	}
+	if action == "" || resource == "" {
+		return fmt.Errorf("permission %q: want action or action:pattern", text)
+	}
+	*p = Permission{Action: action, Resource: resource}
+	return nil
+}
+
+// Role is a named set of permissions plus the roles it inherits from.
+type Role struct {
+	Name        string       `json:"-"`
+	Inherits    []string     `json:"inherits,omitempty"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// Binding gives a subject a role, limited to resources matching Resource.
+type Binding struct {
+	Subject  string `json:"subject"`
+	Role     string `json:"role"`
+	Resource string `json:"resource,omitempty"` // Pattern; "**" when omitted.
+}
+
+// DenyRule forbids an action regardless of any role. Action may be "*".
+type DenyRule struct {
+	Subject  string `json:"subject"`
+	Action   string `json:"action"`
+	Resource string `json:"resource,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Policy is a parsed, validated policy file.
+type Policy struct {
+	Roles    map[string]*Role `json:"roles"`
+	Bindings []Binding        `json:"bindings"`
+	Deny     []DenyRule       `json:"deny"`
+}
+
+// ParsePolicy decodes a policy file and checks that every referenced role
+// exists, that inheritance has no cycles and that every pattern is valid.
+// All problems are reported in one error.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var p Policy
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPolicy, err)
+	}
+
+	var problems []string
+	addf := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	for name, r := range p.Roles {
+		if r == nil {
+			addf("role %q: null definition", name)
+			continue
+		}
+		r.Name = name
+		for _, parent := range r.Inherits {
+			if _, ok := p.Roles[parent]; !ok {
+				addf("role %q inherits unknown role %q", name, parent)
+			}
+		}
+		for _, perm := range r.Permissions {
+			if err := checkPattern(perm.Resource); err != nil {
+				addf("role %q permission %q: %v", name, perm, err)
+			}
+		}
+	}
+	for i := range p.Bindings {
+		b := &p.Bindings[i]
+		if b.Resource == "" {
+			b.Resource = "**"
+		}
+		if b.Subject == "" {
+			addf("binding %d: missing subject", i+1)
+		}
+		if _, ok := p.Roles[b.Role]; !ok {
+			addf("binding %d: unknown role %q", i+1, b.Role)
+		}
+		if err := checkPattern(b.Resource); err != nil {
+			addf("binding %d: %v", i+1, err)
+		}
+	}
+	for i := range p.Deny {
+		d := &p.Deny[i]
+		if d.Resource == "" {
+			d.Resource = "**"
+		}
+		if d.Subject == "" || d.Action == "" {
+			addf("deny rule %d: subject and action are required", i+1)
+		}
+		if err := checkPattern(d.Resource); err != nil {
+			addf("deny rule %d: %v", i+1, err)
+		}
+	}
+	if len(problems) == 0 {
+		if cycle := p.findCycle(); cycle != nil {
+			addf("inheritance cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("%w:\n  %s", ErrPolicy, strings.Join(problems, "\n  "))
+	}
+	return &p, nil
+}
+
+// findCycle returns a role inheritance cycle, or nil, with a depth-first search.
+func (p *Policy) findCycle() []string {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := map[string]int{}
+	var stack []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case inProgress:
+			for i, n := range stack {
+				if n == name {
+					return append(append([]string(nil), stack[i:]...), name)
+				}
+			}
+		case done:
+			return nil
+		}
+		state[name] = inProgress
+		stack = append(stack, name)
+		for _, parent := range p.Roles[name].Inherits {
+			if cycle := visit(parent); cycle != nil {
+				return cycle
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = done
+		return nil
+	}
+	for _, name := range sortedKeys(p.Roles) {
+		if cycle := visit(name); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// Decision is the outcome of Authorize together with the rule that decided it.
+type Decision struct {
+	Allowed  bool
+	Subject  string
+	Action   string
+	Resource string
+	// Rule names the deciding rule, e.g. `binding 2 (bob: editor on
+	// lessons/foundations/**) -> editor inherits viewer -> viewer grants "read"`.
+	Rule string
+}
+
+func (d Decision) String() string {
+	verdict := "DENY"
+	if d.Allowed {
+		verdict = "ALLOW"
+	}
+	return fmt.Sprintf("%s %s %s %s: %s", verdict, d.Subject, d.Action, d.Resource, d.Rule)
+}
+
+// Authorize decides whether subject may perform action on resource.
+//
+// Deny rules are checked first and always win. Otherwise the first binding,
+// in file order, whose subject and resource scope match, and whose role grants
+// the action (directly or by inheritance), allows the request. With no
+// match the request is denied by default. An empty subject means the caller
+// is not logged in and is always denied, and so is a resource that is not in
+// clean form: "lessons/x/../foundations" would otherwise slip past a deny
+// rule on "lessons/foundations/**".
+func (p *Policy) Authorize(subject, action, resource string) Decision {
+	d := Decision{Subject: subject, Action: action, Resource: resource}
+	if subject == "" {
+		d.Rule = "not authenticated"
+		return d
+	}
+	if !CleanResource(resource) {
+		d.Rule = "resource has empty, \".\" or \"..\" segments"
+		return d
+	}
+	for i, rule := range p.Deny {
+		if matchSubject(rule.Subject, subject) && matchAction(rule.Action, action) && MatchResource(rule.Resource, resource) {
+			d.Rule = fmt.Sprintf("deny rule %d (%s %s on %s)", i+1, rule.Subject, rule.Action, rule.Resource)
+			if rule.Reason != "" {
+				d.Rule += ": " + rule.Reason
+			}
+			return d
+		}
+	}
+	for i, b := range p.Bindings {
+		if !matchSubject(b.Subject, subject) || !MatchResource(b.Resource, resource) {
+			continue
+		}
+		if chain := p.grant(b.Role, action, resource, nil); chain != nil {
+			d.Allowed = true
+			d.Rule = fmt.Sprintf("binding %d (%s: %s on %s) -> %s", i+1, b.Subject, b.Role, b.Resource, strings.Join(chain, " -> "))
+			return d
+		}
+	}
+	d.Rule = "no binding grants this (default deny)"
+	return d
+}
+
+// grant returns the inheritance path by which role allows action on
+// resource, or nil. seen guards against revisiting a role through two parents.
+func (p *Policy) grant(role, action, resource string, seen map[string]bool) []string {
+	if seen == nil {
+		seen = map[string]bool{}
+	}
+	if seen[role] {
+		return nil
+	}
+	seen[role] = true
+	r := p.Roles[role]
+	for _, perm := range r.Permissions {
+		if matchAction(perm.Action, action) && MatchResource(perm.Resource, resource) {
+			return []string{fmt.Sprintf("%s grants %q", role, perm)}
+		}
+	}
+	for _, parent := range r.Inherits {
+		if chain := p.grant(parent, action, resource, seen); chain != nil {
+			return append([]string{role + " inherits " + parent}, chain...)
+		}
+	}
+	return nil
+}
+
+// Effective lists every permission a role has, including inherited ones.
+func (p *Policy) Effective(role string) []Permission {
+	var perms []Permission
+	seen := map[string]bool{}
+	var walk func(name string)
+	walk = func(name string) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		perms = append(perms, p.Roles[name].Permissions...)
+		for _, parent := range p.Roles[name].Inherits {
+			walk(parent)
+		}
+	}
+	walk(role)
+	return perms
+}
+
+func matchSubject(pattern, subject string) bool {
+	return pattern == AnySubject || pattern == subject
+}
+
+func matchAction(pattern, action string) bool {
+	return pattern == "*" || pattern == action
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func loadDefaultPolicy(t *testing.T) *Policy {
+	t.Helper()
+	policy, err := ParsePolicy(defaultPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return policy
+}
+
+func TestAuthorize(t *testing.T) {
+	policy := loadDefaultPolicy(t)
+	tests := []struct {
+		subject, action, resource string
+		allowed                   bool
+		rule                      string // A substring of Decision.Rule.
+	}{
+		{"alice", "delete", "lessons/projects/4_task_manager", true, "admin grants"},
+		{"alice", "delete", "lessons/foundations/2_variables", false, "foundation lessons are never deleted"},
+		{"bob", "write", "lessons/foundations/4_functions", true, "editor grants"},
+		{"bob", "write", "lessons/foundations/8_errors", false, "deny rule 2"},
+		{"bob", "read", "lessons/projects/1_i18n", true, "viewer grants"},
+		{"carol", "publish", "lessons/projects/1_i18n", true, "editor grants"},
+		{"dave", "read", "lessons/foundations/1_hello_world", true, "binding 5"},
+		{"dave", "read", "lessons/foundations/4_functions", false, "default deny"},
+		{"", "read", "lessons/foundations/1_hello_world", false, "not authenticated"},
+	}
+	for _, tt := range tests {
+		d := policy.Authorize(tt.subject, tt.action, tt.resource)
+		if d.Allowed != tt.allowed || !strings.Contains(d.Rule, tt.rule) {
+			t.Errorf("Authorize(%q, %q, %q) = %s; want allowed=%v by a rule containing %q",
+				tt.subject, tt.action, tt.resource, d, tt.allowed, tt.rule)
+		}
+	}
+}
+
+// TestAuthorizeUncleanResource checks that ".." and friends cannot route a
+// request around a deny rule into a broader binding.
+func TestAuthorizeUncleanResource(t *testing.T) {
+	policy := loadDefaultPolicy(t)
+	for _, resource := range []string{
+		"lessons/x/../foundations/2_variables",
+		"lessons/./foundations/2_variables",
+		"lessons//foundations/2_variables",
+		"/lessons/foundations/2_variables",
+		"lessons/foundations/2_variables/",
+		"..",
+		"",
+	} {
+		d := policy.Authorize("alice", "delete", resource)
+		if d.Allowed {
+			t.Errorf("Authorize(alice, delete, %q) = %s, want DENY", resource, d)
+		}
+	}
+}
+
+func TestMatchResource(t *testing.T) {
+	tests := []struct {
+		pattern, resource string
+		want              bool
+	}{
+		{"lessons/**", "lessons", true},
+		{"lessons/**", "lessons/a/b", true},
+		{"lessons/*", "lessons/a", true},
+		{"lessons/*", "lessons/a/b", false},
+		{"lessons/lesson_?", "lessons/lesson_1", true},
+		{"**/8_errors", "lessons/foundations/8_errors", true},
+		{"lessons/foundations/**", "lessons/projects/1_i18n", false},
+	}
+	for _, tt := range tests {
+		if got := MatchResource(tt.pattern, tt.resource); got != tt.want {
+			t.Errorf("MatchResource(%q, %q) = %v, want %v", tt.pattern, tt.resource, got, tt.want)
+		}
+	}
+}
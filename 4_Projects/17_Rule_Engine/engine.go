@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrRules is wrapped by every error from ParseRules.
+var ErrRules = errors.New("invalid rules")
+
+// ErrNotFinite is returned by ParseNumber for NaN and infinities.
+var ErrNotFinite = errors.New("not a finite number")
+
+// Mode decides how many rules may fire for one set of facts.
+type Mode int
+
+const (
+	// FirstMatch stops at the first rule whose condition holds, like a
+	// tagless switch. Order matters.
+	FirstMatch Mode = iota
+	// AllMatch fires every rule whose condition holds, in file order.
+	AllMatch
+)
+
+func (m Mode) String() string {
+	if m == AllMatch {
+		return "all"
+	}
+	return "first"
+}
+
+// ParseMode accepts "first" or "all".
+func ParseMode(s string) (Mode, error) {
+	switch s {
+	case "first":
+		return FirstMatch, nil
+	case "all":
+		return AllMatch, nil
+	}
+	return 0, fmt.Errorf("unknown mode %q (want first or all)", s)
+}
+
+// Rule is one entry from the rules file: when the condition holds, the
+// rule fires and produces Then.
+type Rule struct {
+	Name string `json:"name"`
+	When string `json:"when"`
+	Then string `json:"then"`
+
+	cond Expr
+}
+
+// RuleError reports a rule whose condition could not be evaluated.
+type RuleError struct {
+	Rule string
+	Err  error
+}
+
+func (e *RuleError) Error() string { return fmt.Sprintf("rule %q: %v", e.Rule, e.Err) }
+
+func (e *RuleError) Unwrap() error { return e.Err }
+
+// RuleSet is an ordered list of rules with parsed conditions.
+type RuleSet struct {
+	Rules []Rule `json:"rules"`
+}
+
+// ParseRules decodes a rules file and parses every condition. Missing or
+// duplicate names and all syntax errors are reported in one error.
+func ParseRules(data []byte) (*RuleSet, error) {
+	var rs RuleSet
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rs); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRules, err)
+	}
+
+	var problems []string
+	seen := map[string]bool{}
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		label := fmt.Sprintf("rule %d", i+1)
+		switch {
+		case r.Name == "":
+			problems = append(problems, label+": missing name")
+		case seen[r.Name]:
+			problems = append(problems, fmt.Sprintf("%s: duplicate name %q", label, r.Name))
+		default:
+			label = fmt.Sprintf("rule %q", r.Name)
+		}
+		seen[r.Name] = true
+		if strings.TrimSpace(r.When) == "" {
+			problems = append(problems, label+": missing condition")
+			continue
+		}
+		cond, err := Parse(r.When)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", label, err))
+			continue
+		}
+		r.cond = cond
+	}
+	if len(rs.Rules) == 0 {
+		problems = append(problems, "no rules")
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w:\n  %s", ErrRules, strings.Join(problems, "\n  "))
+	}
+	return &rs, nil
+}
+
+// Firing records one rule that fired.
+type Firing struct {
+	Index int // 1-based position in the rules file.
+	Rule  Rule
+}
+
+func (f Firing) String() string {
+	return fmt.Sprintf("%s (rule %d %q: %s)", f.Rule.Then, f.Index, f.Rule.Name, f.Rule.When)
+}
+
+// Result is the outcome of one evaluation.
+type Result struct {
+	Mode    Mode
+	Fired   []Firing // Empty when no rule matched.
+	Checked int      // How many conditions were evaluated.
+}
+
+// Evaluate checks the rules against facts in order. A condition that fails
+// to evaluate (an unknown fact, a type mismatch or a non-bool result) stops
+// the evaluation with a *RuleError: a rule engine that skipped a broken rule
+// would quietly fall through to the next one.
+func (rs *RuleSet) Evaluate(facts Facts, mode Mode) (Result, error) {
+	res := Result{Mode: mode}
+	for i, r := range rs.Rules {
+		res.Checked++
+		v, err := r.cond.Eval(facts)
+		if err != nil {
+			return res, &RuleError{Rule: r.Name, Err: err}
+		}
+		holds, ok := v.(bool)
+		if !ok {
+			return res, &RuleError{Rule: r.Name, Err: fmt.Errorf("%w: condition is %s, not a bool", ErrType, describe(v))}
+		}
+		if !holds {
+			continue
+		}
+		res.Fired = append(res.Fired, Firing{Index: i + 1, Rule: r})
+		if mode == FirstMatch {
+			break
+		}
+	}
+	return res, nil
+}
+
+// ParseFact parses a "name=value" pair. The value becomes a number if it
+// parses as one, a bool for "true" or "false", and a string otherwise.
+// NaN and infinities are rejected: no rule can sensibly compare against them.
+func ParseFact(s string) (name string, value any, err error) {
+	name, raw, ok := strings.Cut(s, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", nil, fmt.Errorf("fact %q: want name=value", s)
+	}
+	if n, err := ParseNumber(raw); err == nil {
+		return name, n, nil
+	} else if errors.Is(err, ErrNotFinite) {
+		return "", nil, fmt.Errorf("fact %q: %w", s, err)
+	}
+	switch raw {
+	case "true":
+		return name, true, nil
+	case "false":
+		return name, false, nil
+	}
+	return name, raw, nil
+}
+
+// ParseNumber parses a fact's numeric value, rejecting NaN and infinities.
+func ParseNumber(s string) (float64, error) {
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, ErrNotFinite
+	}
+	return n, nil
+}
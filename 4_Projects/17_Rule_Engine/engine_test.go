@@ -0,0 +1,198 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// lessonRules parses the built-in rules.json.
+func lessonRules(t *testing.T) *RuleSet {
+	t.Helper()
+	data, err := os.ReadFile("rules.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs, err := ParseRules(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rs
+}
+
+// names lists the names of the rules that fired.
+func names(res Result) string {
+	var fired []string
+	for _, f := range res.Fired {
+		fired = append(fired, f.Rule.Name)
+	}
+	return strings.Join(fired, ",")
+}
+
+func TestEvaluateModes(t *testing.T) {
+	rs := lessonRules(t)
+	tests := []struct {
+		age     float64
+		mode    Mode
+		fired   string
+		checked int
+	}{
+		// FirstMatch stops at the first rule that holds, like the lesson's switch.
+		{10, FirstMatch, "underage", 1},
+		{15, FirstMatch, "underage", 1},
+		{25, FirstMatch, "working_age", 2},
+		{70, FirstMatch, "senior", 3},
+
+		// AllMatch evaluates every rule and fires each one that holds, in order.
+		{10, AllMatch, "underage", 5},
+		{15, AllMatch, "underage,teenager", 5},
+		{19, AllMatch, "working_age,teenager", 5},
+		{70, AllMatch, "senior,pension", 5},
+	}
+	for _, tt := range tests {
+		res, err := rs.Evaluate(Facts{"age": tt.age}, tt.mode)
+		if err != nil {
+			t.Errorf("age %v, %v: %v", tt.age, tt.mode, err)
+			continue
+		}
+		if got := names(res); got != tt.fired || res.Checked != tt.checked {
+			t.Errorf("age %v, %v: fired %q after %d checks, want %q after %d", tt.age, tt.mode, got, res.Checked, tt.fired, tt.checked)
+		}
+	}
+}
+
+func TestEvaluateNoMatch(t *testing.T) {
+	rs, err := ParseRules([]byte(`{"rules": [{"name": "adult", "when": "age >= 18", "then": "Adult"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := rs.Evaluate(Facts{"age": 5.0}, FirstMatch)
+	if err != nil || len(res.Fired) != 0 || res.Checked != 1 {
+		t.Errorf("got %+v, %v; want nothing fired after 1 check", res, err)
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	rs, err := ParseRules([]byte(`{"rules": [
+		{"name": "minor", "when": "age < 18", "then": "Minor"},
+		{"name": "vip", "when": "tier == 'gold'", "then": "VIP"},
+		{"name": "bare", "when": "age", "then": "?"}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		facts   Facts
+		rule    string
+		err     error
+		checked int
+	}{
+		{Facts{"age": 30.0}, "vip", ErrUnknownFact, 2},
+		{Facts{"age": 30.0, "tier": 3.0}, "vip", ErrType, 2},
+		{Facts{"age": 30.0, "tier": "silver"}, "bare", ErrType, 3},
+	}
+	for _, tt := range tests {
+		res, err := rs.Evaluate(tt.facts, AllMatch)
+		var re *RuleError
+		if !errors.As(err, &re) || re.Rule != tt.rule || !errors.Is(err, tt.err) {
+			t.Errorf("%v: err = %v, want a RuleError for %q wrapping %v", tt.facts, err, tt.rule, tt.err)
+		}
+		if res.Checked != tt.checked {
+			t.Errorf("%v: Checked = %d, want %d", tt.facts, res.Checked, tt.checked)
+		}
+	}
+
+	// FirstMatch stops before reaching the broken rules.
+	if res, err := rs.Evaluate(Facts{"age": 5.0}, FirstMatch); err != nil || names(res) != "minor" {
+		t.Errorf("FirstMatch: got %q, %v; want minor", names(res), err)
+	}
+}
+
+func TestParseRulesErrors(t *testing.T) {
+	tests := []struct {
+		name, data string
+		problems   []string
+	}{
+		{
+			name: "duplicate and missing names",
+			data: `{"rules": [
+				{"name": "a", "when": "x > 1", "then": "A"},
+				{"name": "a", "when": "x > 2", "then": "A again"},
+				{"when": "x > 3", "then": "nameless"}
+			]}`,
+			problems: []string{`rule 2: duplicate name "a"`, "rule 3: missing name"},
+		},
+		{
+			name:     "missing condition",
+			data:     `{"rules": [{"name": "a", "when": "  ", "then": "A"}]}`,
+			problems: []string{`rule "a": missing condition`},
+		},
+		{
+			name:     "syntax error names the rule",
+			data:     `{"rules": [{"name": "a", "when": "18 <= age < 60", "then": "A"}]}`,
+			problems: []string{`rule "a": syntax error at column 11`},
+		},
+		{
+			name:     "every problem at once",
+			data:     `{"rules": [{"name": "", "when": "x >", "then": "A"}, {"name": "b", "when": "", "then": "B"}]}`,
+			problems: []string{"rule 1: missing name", "rule 1: syntax error", `rule "b": missing condition`},
+		},
+		{
+			name:     "no rules",
+			data:     `{"rules": []}`,
+			problems: []string{"no rules"},
+		},
+		{
+			name:     "unknown field",
+			data:     `{"rules": [{"name": "a", "if": "x > 1", "then": "A"}]}`,
+			problems: []string{`unknown field "if"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRules([]byte(tt.data))
+			if !errors.Is(err, ErrRules) {
+				t.Fatalf("err = %v, want ErrRules", err)
+			}
+			for _, p := range tt.problems {
+				if !strings.Contains(err.Error(), p) {
+					t.Errorf("err = %v\nwant it to mention %s", err, p)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFact(t *testing.T) {
+	tests := []struct {
+		in    string
+		name  string
+		value any
+	}{
+		{"age=25", "age", 25.0},
+		{" age =2.5e1", "age", 25.0},
+		{"member=true", "member", true},
+		{"member=false", "member", false},
+		{"name=Ann", "name", "Ann"},
+		{"note=a=b", "note", "a=b"},
+		{"empty=", "empty", ""},
+	}
+	for _, tt := range tests {
+		name, value, err := ParseFact(tt.in)
+		if err != nil || name != tt.name || value != tt.value {
+			t.Errorf("ParseFact(%q) = %q, %#v, %v; want %q, %#v", tt.in, name, value, err, tt.name, tt.value)
+		}
+	}
+
+	for _, in := range []string{"x=NaN", "x=nan", "x=Inf", "x=-inf", "x=+Infinity"} {
+		if _, _, err := ParseFact(in); !errors.Is(err, ErrNotFinite) {
+			t.Errorf("ParseFact(%q): err = %v, want ErrNotFinite", in, err)
+		}
+	}
+	for _, in := range []string{"age", "=25", " =25"} {
+		if _, _, err := ParseFact(in); err == nil {
+			t.Errorf("ParseFact(%q) succeeded, want an error", in)
+		}
+	}
+}
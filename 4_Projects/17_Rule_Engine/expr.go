@@ -0,0 +1,464 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrUnknownFact is returned when an expression names a fact that is not set.
+	ErrUnknownFact = errors.New("unknown fact")
+	// ErrType is returned when an operator is applied to the wrong kind of value.
+	ErrType = errors.New("type mismatch")
+)
+
+// Facts are the named values a condition is evaluated against. Values are
+// float64, string or bool.
+type Facts map[string]any
+
+// SyntaxError reports where an expression could not be parsed.
+type SyntaxError struct {
+	Expr string
+	Pos  int // Byte offset into Expr.
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at column %d in %q: %s", e.Pos+1, e.Expr, e.Msg)
+}
+
+// Expr is a parsed condition.
+type Expr interface {
+	Eval(facts Facts) (any, error)
+	String() string
+}
+
+type literal struct{ value any }
+
+type ident struct{ name string }
+
+type unary struct {
+	op string
+	x  Expr
+}
+
+type binary struct {
+	op   string
+	x, y Expr
+}
+
+func (l literal) Eval(Facts) (any, error) { return l.value, nil }
+
+func (l literal) String() string {
+	if s, ok := l.value.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(l.value)
+}
+
+func (id ident) Eval(facts Facts) (any, error) {
+	v, ok := facts[id.name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownFact, id.name)
+	}
+	return v, nil
+}
+
+func (id ident) String() string { return id.name }
+
+func (u unary) Eval(facts Facts) (any, error) {
+	v, err := u.x.Eval(facts)
+	if err != nil {
+		return nil, err
+	}
+	switch u.op {
+	case "!":
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: ! needs a bool, got %s", ErrType, describe(v))
+		}
+		return !b, nil
+	default: // "-"
+		n, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%w: unary - needs a number, got %s", ErrType, describe(v))
+		}
+		return -n, nil
+	}
+}
+
+func (u unary) String() string { return u.op + u.x.String() }
+
+func (b binary) Eval(facts Facts) (any, error) {
+	x, err := b.x.Eval(facts)
+	if err != nil {
+		return nil, err
+	}
+	if b.op == "&&" || b.op == "||" {
+		left, ok := x.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s needs bools, got %s", ErrType, b.op, describe(x))
+		}
+		// Short-circuit, so "has_id && id > 0" is safe when has_id is false.
+		if (b.op == "&&") != left {
+			return left, nil
+		}
+		y, err := b.y.Eval(facts)
+		if err != nil {
+			return nil, err
+		}
+		right, ok := y.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s needs bools, got %s", ErrType, b.op, describe(y))
+		}
+		return right, nil
+	}
+
+	y, err := b.y.Eval(facts)
+	if err != nil {
+		return nil, err
+	}
+	if b.op == "==" || b.op == "!=" {
+		if kind(x) != kind(y) {
+			return nil, fmt.Errorf("%w: cannot compare %s %s %s", ErrType, describe(x), b.op, describe(y))
+		}
+		return (x == y) == (b.op == "=="), nil
+	}
+
+	var cmp int
+	switch xv := x.(type) {
+	case float64:
+		yv, ok := y.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot compare %s %s %s", ErrType, describe(x), b.op, describe(y))
+		}
+		if math.IsNaN(xv) || math.IsNaN(yv) {
+			return false, nil // NaN is unordered, so every ordering is false, as in Go.
+		}
+		switch {
+		case xv < yv:
+			cmp = -1
+		case xv > yv:
+			cmp = 1
+		}
+	case string:
+		yv, ok := y.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot compare %s %s %s", ErrType, describe(x), b.op, describe(y))
+		}
+		cmp = strings.Compare(xv, yv)
+	default:
+		return nil, fmt.Errorf("%w: %s cannot order %s", ErrType, b.op, describe(x))
+	}
+	switch b.op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default: // ">="
+		return cmp >= 0, nil
+	}
+}
+
+func (b binary) String() string {
+	return "(" + b.x.String() + " " + b.op + " " + b.y.String() + ")"
+}
+
+func kind(v any) string {
+	switch v.(type) {
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func describe(v any) string {
+	return fmt.Sprintf("%s %s", kind(v), literal{v})
+}
+
+// Parse parses a condition. The grammar, loosest binding first:
+//
+//	or         = and { "||" and }
+//	and        = not { "&&" not }
+//	not        = "!" not | comparison
+//	comparison = operand [ ("<" | "<=" | ">" | ">=" | "==" | "!=") operand ]
+//	operand    = number | string | "true" | "false" | fact | "-" operand | "(" or ")"
+//
+// Numbers are decimal, with an optional fraction and exponent (1.5e3).
+// Strings are double- or single-quoted. Fact names are ASCII letters,
+// digits, "_" and ".", starting with a letter or "_". Comparisons do not
+// chain, so "18 <= age < 60" is a syntax error rather than a surprise.
+func Parse(expr string) (Expr, error) {
+	toks, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{expr: expr, toks: toks}
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+	return e, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string // Operator or identifier text, or the unquoted string.
+	num  float64
+	pos  int
+}
+
+func (t token) String() string {
+	//exhaustive:ignore Numbers, identifiers and operators all print as their text.
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// twoCharOps are checked before the single-character operators.
+var twoCharOps = []string{"&&", "||", "<=", ">=", "==", "!="}
+
+func lex(expr string) ([]token, error) {
+	var toks []token
+	syntaxErr := func(pos int, format string, args ...any) error {
+		return &SyntaxError{Expr: expr, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+	}
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case isDigit(c) || c == '.' && i+1 < len(expr) && isDigit(expr[i+1]):
+			start := i
+			for i < len(expr) && (isDigit(expr[i]) || expr[i] == '.') {
+				i++
+			}
+			if i < len(expr) && (expr[i] == 'e' || expr[i] == 'E') {
+				j := i + 1
+				if j < len(expr) && (expr[j] == '+' || expr[j] == '-') {
+					j++
+				}
+				if j < len(expr) && isDigit(expr[j]) {
+					i = j
+					for i < len(expr) && isDigit(expr[i]) {
+						i++
+					}
+				}
+			}
+			// Letters or "_" straight after a number ("1_000", "2x", "1e",
+			// "0x10") make it a bad number, not the start of a fact name.
+			// ParseFloat alone would accept Go's digit separators and hex.
+			end := i
+			for i < len(expr) && (isIdentStart(expr[i]) || isDigit(expr[i])) {
+				i++
+			}
+			n, err := strconv.ParseFloat(expr[start:i], 64)
+			if err != nil || i != end {
+				return nil, syntaxErr(start, "bad number %q", expr[start:i])
+			}
+			toks = append(toks, token{kind: tokNumber, text: expr[start:i], num: n, pos: start})
+		case c == '"' || c == '\'':
+			start := i
+			end := strings.IndexByte(expr[i+1:], c)
+			if end < 0 {
+				return nil, syntaxErr(start, "unterminated string")
+			}
+			i += end + 2
+			toks = append(toks, token{kind: tokString, text: expr[start+1 : i-1], pos: start})
+		case isIdentStart(c):
+			start := i
+			for i < len(expr) && (isIdentStart(expr[i]) || expr[i] == '.' || isDigit(expr[i])) {
+				i++
+			}
+			toks = append(toks, token{kind: tokIdent, text: expr[start:i], pos: start})
+		default:
+			op := ""
+			for _, two := range twoCharOps {
+				if strings.HasPrefix(expr[i:], two) {
+					op = two
+					break
+				}
+			}
+			if op == "" && strings.IndexByte("<>!-()", c) >= 0 {
+				op = string(c)
+			}
+			if op == "" {
+				if c == '=' || c == '&' || c == '|' {
+					return nil, syntaxErr(i, "unexpected %q (did you mean %q?)", c, string([]byte{c, c}))
+				}
+				return nil, syntaxErr(i, "unexpected %q", c)
+			}
+			toks = append(toks, token{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(expr)}), nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+type parser struct {
+	expr string
+	toks []token
+	i    int
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) isOp(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokOp {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return &SyntaxError{Expr: p.expr, Pos: t.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) or() (Expr, error) {
+	x, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("||") {
+		p.next()
+		y, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		x = binary{op: "||", x: x, y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) and() (Expr, error) {
+	x, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("&&") {
+		p.next()
+		y, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		x = binary{op: "&&", x: x, y: y}
+	}
+	return x, nil
+}
+
+func (p *parser) not() (Expr, error) {
+	if p.isOp("!") {
+		p.next()
+		x, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return unary{op: "!", x: x}, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (Expr, error) {
+	x, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOp("<", "<=", ">", ">=", "==", "!=") {
+		return x, nil
+	}
+	op := p.next().text
+	y, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOp("<", "<=", ">", ">=", "==", "!=") {
+		return nil, p.errorf(p.peek(), "comparisons cannot be chained; join them with &&")
+	}
+	return binary{op: op, x: x, y: y}, nil
+}
+
+func (p *parser) operand() (Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return literal{t.num}, nil
+	case tokString:
+		return literal{t.text}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return literal{true}, nil
+		case "false":
+			return literal{false}, nil
+		}
+		return ident{t.text}, nil
+	case tokOp:
+		switch t.text {
+		case "-":
+			x, err := p.operand()
+			if err != nil {
+				return nil, err
+			}
+			return unary{op: "-", x: x}, nil
+		case "(":
+			x, err := p.or()
+			if err != nil {
+				return nil, err
+			}
+			if !p.isOp(")") {
+				return nil, p.errorf(p.peek(), "missing ) for ( at column %d", t.pos+1)
+			}
+			p.next()
+			return x, nil
+		}
+	case tokEOF:
+		// Reported below like any other token that cannot start a value.
+	}
+	return nil, p.errorf(t, "expected a value, got %s", t)
+}
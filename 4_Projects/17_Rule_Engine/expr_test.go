@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParsePrecedence(t *testing.T) {
+	tests := []struct {
+		expr, want string
+	}{
+		{"a || b && c", "(a || (b && c))"},
+		{"a && b || c", "((a && b) || c)"},
+		{"a || b || c", "((a || b) || c)"},
+		{"(a || b) && c", "((a || b) && c)"},
+		{"!a && b", "(!a && b)"},
+		{"!(a && b)", "!(a && b)"},
+		{"age >= 18 && age < 60", "((age >= 18) && (age < 60))"},
+		{"!x == y", "!(x == y)"},
+		{"-x < -1.5", "(-x < -1.5)"},
+		{"n > 1e3 || n < 2.5E-2", "((n > 1000) || (n < 0.025))"},
+		{`name == 'Ann' || name == "Bo"`, `((name == "Ann") || (name == "Bo"))`},
+		{"user.age_1 > .5", "(user.age_1 > 0.5)"},
+	}
+	for _, tt := range tests {
+		e, err := Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := e.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		{"18 <= age < 60", 10, "comparisons cannot be chained; join them with &&"},
+		{"a == b != c", 7, "comparisons cannot be chained; join them with &&"},
+		{"age = 18", 4, `unexpected '=' (did you mean "=="?)`},
+		{"a & b", 2, `unexpected '&' (did you mean "&&"?)`},
+		{"(a || b", 7, "missing ) for ( at column 1"},
+		{"a b", 2, `unexpected "b"`},
+		{"age >", 5, "expected a value, got end of expression"},
+		{"", 0, "expected a value, got end of expression"},
+		{`name == "Ann`, 8, "unterminated string"},
+		{"x > 1_000", 4, `bad number "1_000"`},
+		{"x > 2x", 4, `bad number "2x"`},
+		{"x > 1e", 4, `bad number "1e"`},
+		{"x > 1e+", 4, `bad number "1e"`},
+		{"x > 0x1p4", 4, `bad number "0x1p4"`},
+		{"x > 1.2.3", 4, `bad number "1.2.3"`},
+		{"x > 1e400", 4, `bad number "1e400"`},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.expr)
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			t.Errorf("Parse(%q): err = %v, want a *SyntaxError", tt.expr, err)
+			continue
+		}
+		if se.Pos != tt.pos || se.Msg != tt.msg {
+			t.Errorf("Parse(%q): error at %d %q, want at %d %q", tt.expr, se.Pos, se.Msg, tt.pos, tt.msg)
+		}
+	}
+}
+
+// eval parses and evaluates expr against facts.
+func eval(t *testing.T, expr string, facts Facts) (any, error) {
+	t.Helper()
+	e, err := Parse(expr)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", expr, err)
+	}
+	return e.Eval(facts)
+}
+
+func TestEval(t *testing.T) {
+	facts := Facts{"age": 25.0, "name": "Ann", "member": true, "nan": math.NaN()}
+	tests := []struct {
+		expr string
+		want any
+		err  error // nil when the evaluation must succeed.
+	}{
+		{"age >= 18 && age < 60", true, nil},
+		{"age == 25", true, nil},
+		{"-age == -25", true, nil},
+		{"name < 'Bob'", true, nil},
+		{"member && !false", true, nil},
+
+		// Short-circuiting never evaluates the right side, so an unknown
+		// fact there is not an error.
+		{"false && missing > 1", false, nil},
+		{"true || missing > 1", true, nil},
+		{"member || missing", true, nil},
+		{"true && missing > 1", nil, ErrUnknownFact},
+		{"false || missing", nil, ErrUnknownFact},
+		{"missing || true", nil, ErrUnknownFact},
+
+		// == and != need both sides to be the same kind.
+		{"age == '25'", nil, ErrType},
+		{"name != 1", nil, ErrType},
+		{"member == 1", nil, ErrType},
+		{"age < name", nil, ErrType},
+		{"member < true", nil, ErrType},
+		{"age && member", nil, ErrType},
+		{"!age", nil, ErrType},
+		{"-name", nil, ErrType},
+
+		// NaN is unordered: every ordering and == are false, != is true.
+		{"nan < 1", false, nil},
+		{"nan <= 1", false, nil},
+		{"nan > 1", false, nil},
+		{"nan >= 1", false, nil},
+		{"1 < nan", false, nil},
+		{"nan >= nan", false, nil},
+		{"nan == nan", false, nil},
+		{"nan != nan", true, nil},
+	}
+	for _, tt := range tests {
+		got, err := eval(t, tt.expr, facts)
+		switch {
+		case tt.err != nil:
+			if !errors.Is(err, tt.err) {
+				t.Errorf("%s: err = %v, want %v", tt.expr, err, tt.err)
+			}
+		case err != nil:
+			t.Errorf("%s: %v", tt.expr, err)
+		case got != tt.want:
+			t.Errorf("%s = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestUnknownFactNamesTheFact(t *testing.T) {
+	_, err := eval(t, "salary > 0", Facts{})
+	if err == nil || !strings.Contains(err.Error(), `"salary"`) {
+		t.Errorf("err = %v, want it to name salary", err)
+	}
+}
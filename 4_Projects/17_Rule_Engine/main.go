@@ -0,0 +1,152 @@
+// Package main implements a small rule engine, moving the tagless switch from
+// the Control Statements lesson out of Go code and into data:
+//
+//	switch {
+//	case ageCategory < 18:                       -> {"when": "age < 18", ...}
+//	case ageCategory >= 18 && ageCategory < 60:  -> {"when": "age >= 18 && age < 60", ...}
+//	default:                                     -> {"when": "age >= 60", ...}
+//	}
+//
+// A rules file (see rules.json) lists rules in order, each with a name, a
+// condition and a result. Conditions compare facts with numbers, strings and
+// bools using < <= > >= == != && || ! and parentheses. In first-match mode the
+// first rule that holds wins, exactly like the switch; in all-match mode every
+// rule that holds fires. Either way the output names the rule that fired.
+//
+//	go run main.go engine.go expr.go            # sample ages, first-match
+//	go run main.go engine.go expr.go -mode all  # sample ages, every matching rule
+//	go run main.go engine.go expr.go -age 15 -mode all
+//	go run main.go engine.go expr.go -rules my-rules.json -fact age=30 -fact country=DE
+//	go test *.go                                # expression parsing and rule evaluation
+//
+// Exit codes: 0 when the result is printed (even if no rule matched), 1 on
+// runtime errors, 2 on invalid usage.
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// defaultRules is compiled in so the demo runs from anywhere.
+//
+//go:embed rules.json
+var defaultRules []byte
+
+// sampleAges cover every branch of the original switch and its boundaries.
+var sampleAges = []float64{10, 15, 18, 25, 59, 60, 70}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	facts := Facts{}
+	flags := flag.NewFlagSet("rules", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	rulesFile := flags.String("rules", "", "rules file (default: the built-in rules.json)")
+	modeName := flags.String("mode", "first", "first: stop at the first matching rule; all: fire every matching rule")
+	flags.Func("age", "shorthand for -fact age=N", func(s string) error {
+		n, err := ParseNumber(s)
+		if errors.Is(err, ErrNotFinite) {
+			return err
+		}
+		if err != nil {
+			return fmt.Errorf("not a number: %q", s)
+		}
+		facts["age"] = n
+		return nil
+	})
+	flags.Func("fact", "a fact as name=value; repeatable", func(s string) error {
+		name, value, err := ParseFact(s)
+		if err != nil {
+			return err
+		}
+		facts[name] = value
+		return nil
+	})
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "error: unexpected argument %q; pass facts with -age or -fact\n", flags.Arg(0))
+		return 2
+	}
+	mode, err := ParseMode(*modeName)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+
+	data := defaultRules
+	if *rulesFile != "" {
+		if data, err = os.ReadFile(*rulesFile); err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+	}
+	rules, err := ParseRules(data)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+
+	if len(facts) == 0 {
+		err = printSamples(stdout, rules, mode)
+	} else {
+		err = printResult(stdout, rules, facts, mode)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
+
+// printResult evaluates one set of facts and explains every rule that fired.
+func printResult(w io.Writer, rules *RuleSet, facts Facts, mode Mode) error {
+	res, err := rules.Evaluate(facts, mode)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s-match, %d of %d rules checked\n", mode, res.Checked, len(rules.Rules))
+	if len(res.Fired) == 0 {
+		fmt.Fprintln(w, "no rule matched")
+		return nil
+	}
+	for _, f := range res.Fired {
+		fmt.Fprintln(w, f)
+	}
+	return nil
+}
+
+func printSamples(w io.Writer, rules *RuleSet, mode Mode) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "AGE\tRESULT (%s-match)\tRULE\n", mode)
+	for _, age := range sampleAges {
+		res, err := rules.Evaluate(Facts{"age": age}, mode)
+		if err != nil {
+			return err
+		}
+		results := []string{"-"}
+		names := []string{"no rule matched"}
+		if len(res.Fired) > 0 {
+			results, names = nil, nil
+			for _, f := range res.Fired {
+				results = append(results, f.Rule.Then)
+				names = append(names, f.Rule.Name)
+			}
+		}
+		fmt.Fprintf(tw, "%v\t%s\t%s\n", age, strings.Join(results, ", "), strings.Join(names, ", "))
+	}
+	return tw.Flush()
+}
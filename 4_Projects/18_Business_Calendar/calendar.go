@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DateLayout is the format of every date read or printed by this package.
+const DateLayout = "2006-01-02"
+
+var (
+	// ErrUnknownRegion is returned by LookupRegion.
+	ErrUnknownRegion = errors.New("unknown region")
+	// ErrHolidayFile is wrapped by every error from ParseHolidays.
+	ErrHolidayFile = errors.New("invalid holiday file")
+)
+
+// Region is a place with its own working week.
+type Region struct {
+	Code    string
+	Name    string
+	Weekend []time.Weekday
+}
+
+// regions are the built-in regions. Regions with a file in holidays/ also get
+// that year's public holidays; the others only know their weekend.
+var regions = []Region{
+	{"US", "United States", []time.Weekday{time.Saturday, time.Sunday}},
+	{"GB", "England and Wales", []time.Weekday{time.Saturday, time.Sunday}},
+	{"DE", "Germany", []time.Weekday{time.Saturday, time.Sunday}},
+	{"IL", "Israel", []time.Weekday{time.Friday, time.Saturday}},
+	{"SA", "Saudi Arabia", []time.Weekday{time.Friday, time.Saturday}},
+}
+
+// LookupRegion finds a built-in region by its code, ignoring case.
+func LookupRegion(code string) (Region, error) {
+	for _, r := range regions {
+		if strings.EqualFold(r.Code, code) {
+			return r, nil
+		}
+	}
+	codes := make([]string, len(regions))
+	for i, r := range regions {
+		codes[i] = r.Code
+	}
+	return Region{}, fmt.Errorf("%w %q (known: %s)", ErrUnknownRegion, code, strings.Join(codes, ", "))
+}
+
+// ParseWeekend parses a comma-separated list of day names such as "fri,sat".
+// Full names and three-letter abbreviations are accepted; "none" is an empty
+// weekend.
+func ParseWeekend(s string) ([]time.Weekday, error) {
+	if strings.EqualFold(s, "none") {
+		return nil, nil
+	}
+	var days []time.Weekday
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		found := false
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			full := strings.ToLower(d.String())
+			if name == full || name == full[:3] {
+				days = append(days, d)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown weekday %q", name)
+		}
+	}
+	return days, nil
+}
+
+// Holiday is one non-working day.
+type Holiday struct {
+	Date time.Time // Midnight UTC.
+	Name string
+}
+
+// ParseHolidays reads a holiday file: one "YYYY-MM-DD name" per line, with
+// blank lines and lines starting with # ignored. Every bad line is reported
+// in one error; file names the source in messages.
+func ParseHolidays(r io.Reader, file string) ([]Holiday, error) {
+	var holidays []Holiday
+	var problems []string
+	seen := map[time.Time]int{}
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		date, name, _ := strings.Cut(text, " ")
+		name = strings.TrimSpace(name)
+		d, err := time.Parse(DateLayout, date)
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("%s:%d: bad date %q (want %s)", file, line, date, DateLayout))
+		case name == "":
+			problems = append(problems, fmt.Sprintf("%s:%d: missing holiday name", file, line))
+		case seen[d] != 0:
+			problems = append(problems, fmt.Sprintf("%s:%d: %s is already listed on line %d", file, line, date, seen[d]))
+		default:
+			seen[d] = line
+			holidays = append(holidays, Holiday{Date: d, Name: name})
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w:\n  %s", ErrHolidayFile, strings.Join(problems, "\n  "))
+	}
+	return holidays, nil
+}
+
+// Calendar answers working-day questions for one region. Only the date part
+// of a time.Time is used; the clock and location are ignored.
+type Calendar struct {
+	weekend  [7]bool
+	holidays map[time.Time]string
+	years    map[int]bool // Years with at least one holiday; see Covers.
+}
+
+// NewCalendar returns a calendar with the given weekend and holidays. When
+// two lists name the same date, the first name wins.
+func NewCalendar(weekend []time.Weekday, holidays ...[]Holiday) *Calendar {
+	c := &Calendar{holidays: map[time.Time]string{}, years: map[int]bool{}}
+	for _, d := range weekend {
+		c.weekend[d] = true
+	}
+	for _, list := range holidays {
+		for _, h := range list {
+			if _, ok := c.holidays[dateOf(h.Date)]; !ok {
+				c.holidays[dateOf(h.Date)] = h.Name
+			}
+			c.years[h.Date.Year()] = true
+		}
+	}
+	return c
+}
+
+// dateOf drops the clock and location, keeping the calendar date as seen in
+// t's own location.
+func dateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Covers reports whether the calendar knows the holidays of year, taken to
+// mean that some holiday list has a date in it: every region has at least
+// one public holiday a year, so a year with none was never loaded. Answers
+// for dates outside the covered years only account for the weekend.
+func (c *Calendar) Covers(year int) bool {
+	return c.years[year]
+}
+
+// UncoveredYears returns the years from start to end inclusive that Covers
+// rejects, in order.
+func (c *Calendar) UncoveredYears(start, end time.Time) []int {
+	if end.Before(start) {
+		start, end = end, start
+	}
+	var years []int
+	for y := start.Year(); y <= end.Year(); y++ {
+		if !c.Covers(y) {
+			years = append(years, y)
+		}
+	}
+	return years
+}
+
+// IsWeekend reports whether d falls on one of the calendar's weekend days.
+func (c *Calendar) IsWeekend(d time.Time) bool {
+	return c.weekend[d.Weekday()]
+}
+
+// IsWorkingDay reports whether d is neither a weekend day nor a holiday.
+func (c *Calendar) IsWorkingDay(d time.Time) bool {
+	_, holiday := c.holidays[dateOf(d)]
+	return !holiday && !c.weekend[d.Weekday()]
+}
+
+// Explain says why d is or is not a working day, e.g. "holiday: Labor Day".
+func (c *Calendar) Explain(d time.Time) string {
+	if name, ok := c.holidays[dateOf(d)]; ok {
+		return "holiday: " + name
+	}
+	if c.IsWeekend(d) {
+		return "weekend"
+	}
+	return "working day"
+}
+
+// AddBusinessDays moves n working days from d: forward when n is positive,
+// backward when negative. d itself is never counted, so adding 1 to a Friday
+// in a Saturday–Sunday week gives the next Monday, and adding 0 returns d
+// unchanged even when it is not a working day.
+func (c *Calendar) AddBusinessDays(d time.Time, n int) (time.Time, error) {
+	if n != 0 && !c.hasWorkingDay() {
+		return time.Time{}, errors.New("every day of the week is a weekend day")
+	}
+	d = dateOf(d)
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		d = d.AddDate(0, 0, step)
+		if c.IsWorkingDay(d) {
+			n--
+		}
+	}
+	return d, nil
+}
+
+func (c *Calendar) hasWorkingDay() bool {
+	for _, weekend := range c.weekend {
+		if !weekend {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkingDaysBetween counts the working days from start up to but not
+// including end, the way "days until the deadline" is usually counted.
+// The result is negative when end is before start.
+func (c *Calendar) WorkingDaysBetween(start, end time.Time) int {
+	start, end = dateOf(start), dateOf(end)
+	sign := 1
+	if end.Before(start) {
+		start, end, sign = end, start, -1
+	}
+	// Count whole weeks arithmetically and walk only the remainder, then
+	// correct for holidays in the range that fall on weekdays.
+	days := int(end.Sub(start).Hours()/24 + 0.5)
+	workdaysPerWeek := 0
+	for _, weekend := range c.weekend {
+		if !weekend {
+			workdaysPerWeek++
+		}
+	}
+	count := days / 7 * workdaysPerWeek
+	for d := start.AddDate(0, 0, days/7*7); d.Before(end); d = d.AddDate(0, 0, 1) {
+		if !c.weekend[d.Weekday()] {
+			count++
+		}
+	}
+	for date := range c.holidays {
+		if !date.Before(start) && date.Before(end) && !c.weekend[date.Weekday()] {
+			count--
+		}
+	}
+	return sign * count
+}
+
+// Holidays returns the holidays from start to end inclusive, in date order.
+func (c *Calendar) Holidays(start, end time.Time) []Holiday {
+	start, end = dateOf(start), dateOf(end)
+	var list []Holiday
+	for date, name := range c.holidays {
+		if !date.Before(start) && !date.After(end) {
+			list = append(list, Holiday{Date: date, Name: name})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Date.Before(list[j].Date) })
+	return list
+}
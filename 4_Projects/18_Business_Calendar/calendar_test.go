@@ -0,0 +1,191 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// date parses a YYYY-MM-DD date or fails the test.
+func date(t *testing.T, s string) time.Time {
+	t.Helper()
+	d, err := time.Parse(DateLayout, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// holidays parses "YYYY-MM-DD name" lines or fails the test.
+func holidays(t *testing.T, lines ...string) []Holiday {
+	t.Helper()
+	list, err := ParseHolidays(strings.NewReader(strings.Join(lines, "\n")), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return list
+}
+
+var (
+	satSun = []time.Weekday{time.Saturday, time.Sunday}
+	friSat = []time.Weekday{time.Friday, time.Saturday}
+)
+
+// walkWorkingDays is WorkingDaysBetween without the week-skipping shortcut.
+func walkWorkingDays(c *Calendar, start, end time.Time) int {
+	sign := 1
+	if end.Before(start) {
+		start, end, sign = end, start, -1
+	}
+	count := 0
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		if c.IsWorkingDay(d) {
+			count++
+		}
+	}
+	return sign * count
+}
+
+func TestWorkingDaysBetween(t *testing.T) {
+	// 2026-12-25 is a Friday, 2026-12-26 a Saturday and 2026-12-27 a Sunday.
+	christmas := holidays(t, "2026-12-25 Christmas Day", "2026-12-26 Boxing Day", "2026-12-27 Weekend Feast")
+	tests := []struct {
+		name       string
+		weekend    []time.Weekday
+		start, end string
+		want       int
+	}{
+		{"same day", satSun, "2026-12-21", "2026-12-21", 0},
+		{"one week", satSun, "2026-12-07", "2026-12-14", 5},
+		{"end is excluded", satSun, "2026-12-07", "2026-12-08", 1},
+		{"weekend only", satSun, "2026-12-12", "2026-12-14", 0},
+		{"three weeks and two days", satSun, "2026-11-30", "2026-12-23", 17},
+		{"negative range", satSun, "2026-12-14", "2026-12-07", -5},
+		{"negative across weekend", satSun, "2026-12-15", "2026-12-11", -2},
+
+		// Christmas falls on a working Friday; Boxing Day and the Sunday
+		// holiday are weekend days already and must not be subtracted again.
+		{"holidays on weekend days", satSun, "2026-12-21", "2026-12-28", 4},
+		{"holidays on weekend days, reversed", satSun, "2026-12-28", "2026-12-21", -4},
+
+		// With a Friday–Saturday weekend Christmas is already off, but the
+		// Sunday holiday now costs a working day.
+		{"Fri-Sat one week", friSat, "2026-12-06", "2026-12-13", 5},
+		{"Fri-Sat holidays", friSat, "2026-12-20", "2026-12-28", 5},
+		{"no weekend", nil, "2026-12-21", "2026-12-28", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCalendar(tt.weekend, christmas)
+			start, end := date(t, tt.start), date(t, tt.end)
+			if got := c.WorkingDaysBetween(start, end); got != tt.want {
+				t.Errorf("WorkingDaysBetween(%s, %s) = %d, want %d", tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWorkingDaysBetweenMatchesWalk checks the shortcut against a day-by-day
+// count for every start in a month and every length up to six weeks.
+func TestWorkingDaysBetweenMatchesWalk(t *testing.T) {
+	list := holidays(t, "2026-12-24 Eve", "2026-12-25 Christmas", "2026-12-26 Boxing Day", "2027-01-01 New Year", "2027-01-02 Saturday holiday")
+	for _, weekend := range [][]time.Weekday{satSun, friSat, {time.Sunday}, nil} {
+		c := NewCalendar(weekend, list)
+		first := date(t, "2026-12-01")
+		for i := 0; i < 31; i++ {
+			start := first.AddDate(0, 0, i)
+			for n := -42; n <= 42; n++ {
+				end := start.AddDate(0, 0, n)
+				if got, want := c.WorkingDaysBetween(start, end), walkWorkingDays(c, start, end); got != want {
+					t.Fatalf("weekend %v: WorkingDaysBetween(%s, %s) = %d, want %d",
+						weekend, start.Format(DateLayout), end.Format(DateLayout), got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	list := holidays(t, "2026-12-25 Christmas Day", "2026-12-28 Boxing Day (substitute day)")
+	tests := []struct {
+		name    string
+		weekend []time.Weekday
+		from    string
+		n       int
+		want    string
+	}{
+		{"Friday plus one", satSun, "2026-12-11", 1, "2026-12-14"},
+		{"Monday minus one", satSun, "2026-12-14", -1, "2026-12-11"},
+		{"over Christmas", satSun, "2026-12-24", 1, "2026-12-29"},
+		{"back over Christmas", satSun, "2026-12-29", -1, "2026-12-24"},
+		{"from a Saturday", satSun, "2026-12-12", 1, "2026-12-14"},
+		{"zero on a weekday", satSun, "2026-12-14", 0, "2026-12-14"},
+		{"zero on a holiday", satSun, "2026-12-25", 0, "2026-12-25"},
+		{"zero on a weekend day", satSun, "2026-12-26", 0, "2026-12-26"},
+		{"Thursday plus one, Fri-Sat", friSat, "2026-12-10", 1, "2026-12-13"},
+		{"Sunday minus one, Fri-Sat", friSat, "2026-12-13", -1, "2026-12-10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCalendar(tt.weekend, list)
+			got, err := c.AddBusinessDays(date(t, tt.from), tt.n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Format(DateLayout) != tt.want {
+				t.Errorf("AddBusinessDays(%s, %d) = %s, want %s", tt.from, tt.n, got.Format(DateLayout), tt.want)
+			}
+		})
+	}
+}
+
+func TestAddBusinessDaysAllWeekend(t *testing.T) {
+	all := []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+	c := NewCalendar(all)
+	d := date(t, "2026-12-14")
+	for _, n := range []int{1, -1} {
+		if _, err := c.AddBusinessDays(d, n); err == nil || !strings.Contains(err.Error(), "every day of the week is a weekend day") {
+			t.Errorf("AddBusinessDays(%d): err = %v, want every day of the week is a weekend day", n, err)
+		}
+	}
+	if got, err := c.AddBusinessDays(d, 0); err != nil || !got.Equal(d) {
+		t.Errorf("AddBusinessDays(0) = %v, %v; want %v unchanged", got, err, d)
+	}
+}
+
+func TestUncoveredYears(t *testing.T) {
+	c := NewCalendar(satSun,
+		holidays(t, "2026-01-01 New Year's Day"),
+		holidays(t, "2028-12-25 Christmas Day"))
+	tests := []struct {
+		start, end string
+		want       []int
+	}{
+		{"2026-03-01", "2026-03-31", nil},
+		{"2026-12-01", "2027-01-31", []int{2027}},
+		{"2025-06-01", "2029-01-01", []int{2025, 2027, 2029}},
+		{"2029-01-01", "2025-06-01", []int{2025, 2027, 2029}}, // Reversed.
+		{"2028-12-31", "2028-01-01", nil},
+	}
+	for _, tt := range tests {
+		if got := c.UncoveredYears(date(t, tt.start), date(t, tt.end)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("UncoveredYears(%s, %s) = %v, want %v", tt.start, tt.end, got, tt.want)
+		}
+	}
+	if got := NewCalendar(satSun).UncoveredYears(date(t, "2026-01-01"), date(t, "2026-12-31")); !reflect.DeepEqual(got, []int{2026}) {
+		t.Errorf("no holiday lists: UncoveredYears = %v, want [2026]", got)
+	}
+}
+
+func TestParseHolidaysErrors(t *testing.T) {
+	_, err := ParseHolidays(strings.NewReader("# header\n2026-13-01 Bad month\n2026-01-01\n2026-01-02 A\n2026-01-02 B\n"), "x.txt")
+	if err == nil {
+		t.Fatal("no error")
+	}
+	for _, want := range []string{`x.txt:2: bad date "2026-13-01"`, "x.txt:3: missing holiday name", "x.txt:5: 2026-01-02 is already listed on line 4"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v\nwant it to mention %s", err, want)
+		}
+	}
+}
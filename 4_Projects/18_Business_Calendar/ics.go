@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// WriteICS writes holidays as an iCalendar (RFC 5545) file of all-day events
+// that calendar apps can import. calName becomes the calendar's display name
+// and part of each event's UID, so re-importing updates events instead of
+// duplicating them. stamp is the DTSTAMP of every event.
+func WriteICS(w io.Writer, calName string, holidays []Holiday, stamp time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(format string, args ...any) {
+		writeFolded(bw, fmt.Sprintf(format, args...))
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//go-lessons//business-calendar//EN")
+	line("CALSCALE:GREGORIAN")
+	line("X-WR-CALNAME:%s", escapeText(calName))
+	for _, h := range holidays {
+		date := h.Date.Format("20060102")
+		line("BEGIN:VEVENT")
+		line("UID:%s-%s@business-calendar", date, uidPart(calName))
+		line("DTSTAMP:%s", stamp.UTC().Format("20060102T150405Z"))
+		line("DTSTART;VALUE=DATE:%s", date)
+		line("DTEND;VALUE=DATE:%s", h.Date.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:%s", escapeText(h.Name))
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return bw.Flush()
+}
+
+// escapeText escapes a TEXT value (RFC 5545 section 3.3.11).
+var escapeText = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace
+
+// uidPart keeps the letters and digits of a calendar name for use in a UID.
+func uidPart(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return -1
+	}, s)
+}
+
+// writeFolded writes one content line ending in CRLF, folding it so no
+// physical line is longer than 75 octets. Continuation lines start with a
+// space, and a fold never splits a UTF-8 sequence.
+func writeFolded(w *bufio.Writer, s string) {
+	const limit = 75
+	for first := true; ; first = false {
+		room := limit
+		if !first {
+			room-- // The leading space counts.
+			w.WriteByte(' ')
+		}
+		if len(s) <= room {
+			w.WriteString(s)
+			w.WriteString("\r\n")
+			return
+		}
+		cut := room
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n")
+		s = s[cut:]
+	}
+}
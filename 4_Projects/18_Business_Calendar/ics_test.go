@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// fold runs writeFolded on s and returns the physical lines without CRLFs.
+func fold(t *testing.T, s string) []string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	writeFolded(w, s)
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasSuffix(out, "\r\n") {
+		t.Fatalf("%q does not end in CRLF", out)
+	}
+	return strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+}
+
+func TestWriteFolded(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		lines int
+	}{
+		{"short", "SUMMARY:Labor Day", 1},
+		{"exactly 75 octets", strings.Repeat("a", 75), 1},
+		{"76 octets", strings.Repeat("a", 76), 2},
+		{"ASCII over three lines", strings.Repeat("x", 75+74+10), 3},
+		{"two-byte runes", "SUMMARY:" + strings.Repeat("é", 60), 2},
+		{"three-byte runes", "SUMMARY:" + strings.Repeat("祝日", 30), 3},
+		{"four-byte runes", "SUMMARY:" + strings.Repeat("🎉", 40), 3},
+		{"mixed, misaligned", "SUMMARY:a" + strings.Repeat("ü€🎉", 20), 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := fold(t, tt.s)
+			if len(lines) != tt.lines {
+				t.Errorf("%d lines, want %d: %q", len(lines), tt.lines, lines)
+			}
+			var unfolded strings.Builder
+			for i, line := range lines {
+				if len(line) > 75 {
+					t.Errorf("line %d is %d octets: %q", i, len(line), line)
+				}
+				if i > 0 {
+					if !strings.HasPrefix(line, " ") {
+						t.Errorf("continuation line %d does not start with a space: %q", i, line)
+					}
+					line = line[1:]
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a UTF-8 sequence: %q", i, line)
+				}
+				unfolded.WriteString(line)
+			}
+			if unfolded.String() != tt.s {
+				t.Errorf("unfolds to %q, want %q", unfolded.String(), tt.s)
+			}
+		})
+	}
+}
+
+func TestWriteICS(t *testing.T) {
+	list := []Holiday{{Date: time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC), Name: "Tag der Deutschen Einheit; national, new"}}
+	var buf bytes.Buffer
+	if err := WriteICS(&buf, "DE Holidays", list, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//go-lessons//business-calendar//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:DE Holidays",
+		"BEGIN:VEVENT",
+		"UID:20261003-deholidays@business-calendar",
+		"DTSTAMP:20260102T030405Z",
+		"DTSTART;VALUE=DATE:20261003",
+		"DTEND;VALUE=DATE:20261004",
+		`SUMMARY:Tag der Deutschen Einheit\; national\, new`,
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n") + "\r\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,388 @@
+// Package main implements a business calendar, replacing the `switch day` and
+// isWeekend flag from the Control Statements lesson, which assume every
+// country rests on Saturday and Sunday and never has a holiday.
+//
+// Each region has its own weekend (Friday–Saturday in Israel and Saudi
+// Arabia) and, where a file in holidays/ exists, its 2026 public holidays.
+// More holidays are loaded from files with one "YYYY-MM-DD name" per line.
+// A year counts as covered once some file lists a holiday in it; answers
+// about other years only know the weekend, and say so on stderr.
+//
+//	go run main.go calendar.go ics.go                              # Christmas week across regions
+//	go run main.go calendar.go ics.go -region GB check 2026-12-28
+//	go run main.go calendar.go ics.go -region US add 2026-11-25 3  # skips Thanksgiving and the weekend
+//	go run main.go calendar.go ics.go -region DE between 2026-12-01 2027-01-01
+//	go run main.go calendar.go ics.go -region IL -holidays il-2026.txt holidays
+//	go run main.go calendar.go ics.go -region US ics -out us-holidays.ics
+//	go run main.go calendar.go ics.go -weekend fri,sat,sun check 2026-06-05
+//	go run main.go calendar.go ics.go regions
+//	go test *.go                                                   # working days, business-day arithmetic, warnings and iCalendar output
+//
+// Exit codes: 0 on success, 1 on runtime errors, 2 on invalid usage.
+package main
+
+import (
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// builtinHolidays holds holidays/<REGION>.txt for the regions that have one.
+//
+//go:embed holidays/*.txt
+var builtinHolidays embed.FS
+
+// demoStart and demoDays are the week shown when no command is given:
+// Christmas, a Boxing Day substitute and the Friday–Saturday weekend all
+// disagree across regions.
+var (
+	demoStart = time.Date(2026, 12, 21, 0, 0, 0, 0, time.UTC)
+	demoDays  = 8
+)
+
+// usageError marks errors caused by invalid command-line input (exit code 2).
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usageErrorf(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	var holidayFiles []string
+	flags := flag.NewFlagSet("bizcal", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	regionCode := flags.String("region", "US", "region code; see the regions command")
+	weekend := flags.String("weekend", "", `override the region's weekend, e.g. "fri,sat" or "none"`)
+	flags.Func("holidays", "add holidays from this file; repeatable", func(path string) error {
+		holidayFiles = append(holidayFiles, path)
+		return nil
+	})
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: bizcal [flags] [check|add|between|holidays|ics|regions] [args]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	err := dispatch(flags.Args(), *regionCode, *weekend, holidayFiles, stdout, stderr)
+	var usageErr *usageError
+	switch {
+	case errors.As(err, &usageErr), errors.Is(err, ErrUnknownRegion):
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	case err != nil:
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
+
+func dispatch(args []string, regionCode, weekend string, holidayFiles []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		return printDemo(stdout)
+	}
+	cmd, args := args[0], args[1:]
+	if cmd == "regions" {
+		printRegions(stdout)
+		return nil
+	}
+
+	region, err := LookupRegion(regionCode)
+	if err != nil {
+		return err
+	}
+	if weekend != "" {
+		if region.Weekend, err = ParseWeekend(weekend); err != nil {
+			return usageErrorf("-weekend: %v", err)
+		}
+	}
+	cal, err := loadCalendar(region, holidayFiles)
+	if err != nil {
+		return err
+	}
+
+	switch cmd {
+	case "check":
+		return cmdCheck(cal, args, stdout, stderr)
+	case "add":
+		return cmdAdd(cal, args, stdout, stderr)
+	case "between":
+		return cmdBetween(cal, args, stdout, stderr)
+	case "holidays":
+		return cmdHolidays(cal, args, stdout, stderr)
+	case "ics":
+		return cmdICS(cal, region, args, stdout, stderr)
+	default:
+		return usageErrorf("unknown command %q", cmd)
+	}
+}
+
+// loadCalendar combines the region's built-in holidays with any extra files.
+func loadCalendar(region Region, files []string) (*Calendar, error) {
+	var lists [][]Holiday
+	name := "holidays/" + region.Code + ".txt"
+	if f, err := builtinHolidays.Open(name); err == nil {
+		list, err := ParseHolidays(f, name)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		list, err := ParseHolidays(f, path)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+	return NewCalendar(region.Weekend, lists...), nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	d, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return time.Time{}, usageErrorf("bad date %q (want %s)", s, DateLayout)
+	}
+	return d, nil
+}
+
+func formatDay(d time.Time) string {
+	return d.Format(DateLayout) + " " + d.Weekday().String()[:3]
+}
+
+// warnUncovered prints one warning naming the years from start to end
+// inclusive whose holidays the calendar does not know.
+func warnUncovered(w io.Writer, cal *Calendar, start, end time.Time) {
+	if years := cal.UncoveredYears(start, end); len(years) > 0 {
+		fmt.Fprintf(w, "warning: no holidays known for %s; only the weekend is counted (add them with -holidays)\n", yearRanges(years))
+	}
+}
+
+// yearRanges formats sorted years as runs: [1700 … 2025 2027] → "1700–2025, 2027".
+func yearRanges(years []int) string {
+	var runs []string
+	for i := 0; i < len(years); {
+		j := i
+		for j+1 < len(years) && years[j+1] == years[j]+1 {
+			j++
+		}
+		if j == i {
+			runs = append(runs, strconv.Itoa(years[i]))
+		} else {
+			runs = append(runs, fmt.Sprintf("%d–%d", years[i], years[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(runs, ", ")
+}
+
+func cmdCheck(cal *Calendar, args []string, w, stderr io.Writer) error {
+	if len(args) == 0 {
+		return usageErrorf("check needs at least one date")
+	}
+	warned := map[int]bool{}
+	for _, arg := range args {
+		d, err := parseDate(arg)
+		if err != nil {
+			return err
+		}
+		if !warned[d.Year()] {
+			warned[d.Year()] = true
+			warnUncovered(stderr, cal, d, d)
+		}
+		fmt.Fprintf(w, "%s: %s\n", formatDay(d), cal.Explain(d))
+	}
+	return nil
+}
+
+func cmdAdd(cal *Calendar, args []string, w, stderr io.Writer) error {
+	if len(args) != 2 {
+		return usageErrorf("add needs a date and a number of business days")
+	}
+	d, err := parseDate(args[0])
+	if err != nil {
+		return err
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil {
+		return usageErrorf("bad number of days %q", args[1])
+	}
+	result, err := cal.AddBusinessDays(d, n)
+	if err != nil {
+		return err
+	}
+	warnUncovered(stderr, cal, d, result)
+	fmt.Fprintf(w, "%s %+d business days = %s\n", formatDay(d), n, formatDay(result))
+	for _, h := range cal.Holidays(minDate(d, result), maxDate(d, result)) {
+		fmt.Fprintf(w, "  skipped %s %s\n", formatDay(h.Date), h.Name)
+	}
+	return nil
+}
+
+func cmdBetween(cal *Calendar, args []string, w, stderr io.Writer) error {
+	if len(args) != 2 {
+		return usageErrorf("between needs a start and an end date")
+	}
+	start, err := parseDate(args[0])
+	if err != nil {
+		return err
+	}
+	end, err := parseDate(args[1])
+	if err != nil {
+		return err
+	}
+	if !start.Equal(end) {
+		// The later date is not counted, so 2026-12-01 to 2027-01-01 is
+		// entirely within 2026.
+		warnUncovered(stderr, cal, minDate(start, end), maxDate(start, end).AddDate(0, 0, -1))
+	}
+	n := cal.WorkingDaysBetween(start, end)
+	fmt.Fprintf(w, "%d working days from %s up to %s\n", n, formatDay(start), formatDay(end))
+	return nil
+}
+
+func cmdHolidays(cal *Calendar, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("holidays", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	year := flags.Int("year", demoStart.Year(), "year to list")
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf("%v", err)
+	}
+	list := yearHolidays(cal, *year)
+	if len(list) == 0 {
+		fmt.Fprintf(stdout, "no holidays known for %d; add some with -holidays\n", *year)
+		return nil
+	}
+	tw := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	for _, h := range list {
+		fmt.Fprintf(tw, "%s\t%s", formatDay(h.Date), h.Name)
+		if cal.IsWeekend(h.Date) {
+			fmt.Fprint(tw, "\t(on the weekend; no working day lost)")
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+func cmdICS(cal *Calendar, region Region, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("ics", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	year := flags.Int("year", demoStart.Year(), "year to export")
+	out := flags.String("out", "", "write to this file instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf("%v", err)
+	}
+	name := fmt.Sprintf("%s holidays %d", region.Name, *year)
+	list := yearHolidays(cal, *year)
+	if !cal.Covers(*year) {
+		fmt.Fprintf(stderr, "warning: no holidays known for %d; the calendar will be empty (add them with -holidays)\n", *year)
+	}
+	if *out == "" {
+		return WriteICS(stdout, name, list, time.Now())
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := WriteICS(f, name, list, time.Now()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "wrote %d holidays to %s\n", len(list), *out)
+	return nil
+}
+
+func yearHolidays(cal *Calendar, year int) []Holiday {
+	return cal.Holidays(time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC))
+}
+
+func printRegions(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CODE\tNAME\tWEEKEND\tBUILT-IN HOLIDAYS")
+	for _, r := range regions {
+		days := make([]string, len(r.Weekend))
+		for i, d := range r.Weekend {
+			days[i] = d.String()[:3]
+		}
+		builtin := "no"
+		if _, err := fs.Stat(builtinHolidays, "holidays/"+r.Code+".txt"); err == nil {
+			builtin = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Code, r.Name, strings.Join(days, ", "), builtin)
+	}
+	tw.Flush()
+}
+
+// printDemo shows one week in every region side by side.
+func printDemo(w io.Writer) error {
+	cals := make([]*Calendar, len(regions))
+	for i, r := range regions {
+		cal, err := loadCalendar(r, nil)
+		if err != nil {
+			return err
+		}
+		cals[i] = cal
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprint(tw, "DATE")
+	for _, r := range regions {
+		fmt.Fprintf(tw, "\t%s", r.Code)
+	}
+	fmt.Fprintln(tw)
+	for i := 0; i < demoDays; i++ {
+		d := demoStart.AddDate(0, 0, i)
+		fmt.Fprint(tw, formatDay(d))
+		for _, cal := range cals {
+			fmt.Fprintf(tw, "\t%s", cal.Explain(d))
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+func minDate(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxDate(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestYearRanges(t *testing.T) {
+	tests := []struct {
+		years []int
+		want  string
+	}{
+		{[]int{2025}, "2025"},
+		{[]int{2024, 2025}, "2024–2025"},
+		{[]int{2024, 2025, 2027}, "2024–2025, 2027"},
+		{[]int{2020, 2022, 2024}, "2020, 2022, 2024"},
+		{[]int{2024, 2025, 2027, 2028, 2029}, "2024–2025, 2027–2029"},
+	}
+	for _, tt := range tests {
+		if got := yearRanges(tt.years); got != tt.want {
+			t.Errorf("yearRanges(%v) = %q, want %q", tt.years, got, tt.want)
+		}
+	}
+}
+
+// TestWarnUncoveredOnce checks that a long range gets one warning, not one
+// line per year.
+func TestWarnUncoveredOnce(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{
+			[]string{"between", "1700-01-01", "2026-01-01"},
+			"warning: no holidays known for 1700–2025; only the weekend is counted (add them with -holidays)\n",
+		},
+		{
+			[]string{"between", "2025-06-01", "2028-01-01"},
+			"warning: no holidays known for 2025, 2027; only the weekend is counted (add them with -holidays)\n",
+		},
+		{[]string{"between", "2026-01-01", "2027-01-01"}, ""},
+		{
+			[]string{"add", "2026-12-30", "200"},
+			"warning: no holidays known for 2027; only the weekend is counted (add them with -holidays)\n",
+		},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run(tt.args, &stdout, &stderr); code != 0 {
+			t.Errorf("%q: exit code %d, stderr %s", tt.args, code, &stderr)
+		}
+		if got := stderr.String(); got != tt.want {
+			t.Errorf("%q: stderr = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDOT writes the state graph in Graphviz DOT format. Render it with
+//
+//	dot -Tsvg machine.dot -o machine.svg
+//
+// The initial state is marked by an arrow from a dot, guarded transitions are
+// labeled "event [guard]" and drawn dashed, and the states in highlight (for
+// example a machine's current state) are filled.
+func (d *Definition[S, E]) WriteDOT(w io.Writer, name string, highlight ...S) error {
+	bw := bufio.NewWriter(w)
+	filled := map[S]bool{}
+	for _, s := range highlight {
+		filled[s] = true
+	}
+	id := func(s S) string { return dotQuote(fmt.Sprint(s)) }
+
+	fmt.Fprintf(bw, "digraph %s {\n", dotQuote(name))
+	fmt.Fprintln(bw, "\trankdir=LR;")
+	fmt.Fprintln(bw, "\tnode [shape=circle];")
+	fmt.Fprintln(bw, "\t__start [shape=point];")
+	for _, s := range d.states {
+		if filled[s] {
+			fmt.Fprintf(bw, "\t%s [style=filled, fillcolor=lightblue];\n", id(s))
+		} else {
+			fmt.Fprintf(bw, "\t%s;\n", id(s))
+		}
+	}
+	fmt.Fprintf(bw, "\t__start -> %s;\n", id(d.initial))
+	for _, t := range d.transitions {
+		label := fmt.Sprint(t.Event)
+		style := ""
+		if t.Guard != nil {
+			label += " [" + t.GuardName + "]"
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(bw, "\t%s -> %s [label=%s%s];\n", id(t.From), id(t.To), dotQuote(label), style)
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// dotQuote makes a DOT quoted string; unlike strconv.Quote it leaves
+// non-ASCII text alone, which DOT reads as UTF-8.
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
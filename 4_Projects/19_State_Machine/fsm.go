@@ -0,0 +1,303 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrDefinition is wrapped by every error from Definition.New.
+	ErrDefinition = errors.New("invalid state machine")
+	// ErrUnknownEvent means the event was never declared.
+	ErrUnknownEvent = errors.New("unknown event")
+	// ErrNoTransition means the event is declared but not allowed in the
+	// current state.
+	ErrNoTransition = errors.New("no transition")
+	// ErrGuardRejected means every transition for the event had a guard and
+	// all of them refused.
+	ErrGuardRejected = errors.New("rejected by guard")
+	// ErrReentrant means Fire was called from a hook or guard while another
+	// Fire, Can or Permitted was still running.
+	ErrReentrant = errors.New("Fire called from a hook or guard")
+)
+
+// TransitionError reports an event the machine refused. The machine stays in
+// State. Err is one of the sentinel errors above, possibly wrapping the
+// guard's own error, so errors.Is works on either.
+type TransitionError[S, E comparable] struct {
+	State S
+	Event E
+	Err   error
+}
+
+func (e *TransitionError[S, E]) Error() string {
+	return fmt.Sprintf("event %v in state %v: %v", e.Event, e.State, e.Err)
+}
+
+func (e *TransitionError[S, E]) Unwrap() error { return e.Err }
+
+// Guard decides whether a transition may happen. A nil error allows it; the
+// error otherwise explains the refusal.
+type Guard func() error
+
+// Transition is one edge of the state graph.
+type Transition[S, E comparable] struct {
+	From, To  S
+	Event     E
+	Guard     Guard  // Nil means always allowed.
+	GuardName string // Shown in errors and in the DOT graph.
+}
+
+// Change is one transition that happened.
+type Change[S, E comparable] struct {
+	From, To S
+	Event    E
+}
+
+func (c Change[S, E]) String() string {
+	return fmt.Sprintf("%v --%v--> %v", c.From, c.Event, c.To)
+}
+
+// Hook runs on entering or leaving a state.
+type Hook[S, E comparable] func(Change[S, E])
+
+// Definition declares states, events, transitions and hooks. Build it once,
+// then call New for each machine; the machines share the definition and must
+// not be created while it is still being changed.
+type Definition[S, E comparable] struct {
+	initial     S
+	states      []S
+	events      []E
+	transitions []Transition[S, E]
+	onEnter     map[S][]Hook[S, E]
+	onExit      map[S][]Hook[S, E]
+}
+
+// NewDefinition starts a definition whose machines begin in initial.
+func NewDefinition[S, E comparable](initial S) *Definition[S, E] {
+	return &Definition[S, E]{
+		initial: initial,
+		onEnter: map[S][]Hook[S, E]{},
+		onExit:  map[S][]Hook[S, E]{},
+	}
+}
+
+// States declares states, in the order they are drawn.
+func (d *Definition[S, E]) States(states ...S) *Definition[S, E] {
+	d.states = append(d.states, states...)
+	return d
+}
+
+// Events declares events.
+func (d *Definition[S, E]) Events(events ...E) *Definition[S, E] {
+	d.events = append(d.events, events...)
+	return d
+}
+
+// Permit allows event to move the machine from one state to another.
+func (d *Definition[S, E]) Permit(from S, event E, to S) *Definition[S, E] {
+	return d.PermitIf(from, event, to, "", nil)
+}
+
+// PermitIf is Permit with a guard. Several guarded transitions may share a
+// state and event; the first whose guard allows it, in declaration order,
+// is taken.
+func (d *Definition[S, E]) PermitIf(from S, event E, to S, guardName string, guard Guard) *Definition[S, E] {
+	d.transitions = append(d.transitions, Transition[S, E]{From: from, To: to, Event: event, Guard: guard, GuardName: guardName})
+	return d
+}
+
+// OnEnter adds a hook that runs after the machine enters state.
+func (d *Definition[S, E]) OnEnter(state S, hook Hook[S, E]) *Definition[S, E] {
+	d.onEnter[state] = append(d.onEnter[state], hook)
+	return d
+}
+
+// OnExit adds a hook that runs before the machine leaves state.
+func (d *Definition[S, E]) OnExit(state S, hook Hook[S, E]) *Definition[S, E] {
+	d.onExit[state] = append(d.onExit[state], hook)
+	return d
+}
+
+// Transitions returns the declared transitions in declaration order.
+func (d *Definition[S, E]) Transitions() []Transition[S, E] {
+	return append([]Transition[S, E](nil), d.transitions...)
+}
+
+// validate checks that everything refers to declared states and events, and
+// that no transition is hidden behind an earlier unguarded one. All problems
+// are reported together.
+func (d *Definition[S, E]) validate() error {
+	var problems []string
+	addf := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	states := map[S]bool{}
+	for _, s := range d.states {
+		if states[s] {
+			addf("state %v declared twice", s)
+		}
+		states[s] = true
+	}
+	events := map[E]bool{}
+	for _, e := range d.events {
+		if events[e] {
+			addf("event %v declared twice", e)
+		}
+		events[e] = true
+	}
+	if !states[d.initial] {
+		addf("initial state %v is not declared", d.initial)
+	}
+
+	type key struct {
+		from  S
+		event E
+	}
+	unguarded := map[key]int{}
+	for i, t := range d.transitions {
+		label := fmt.Sprintf("transition %d (%v)", i+1, Change[S, E]{From: t.From, To: t.To, Event: t.Event})
+		if !states[t.From] {
+			addf("%s: undeclared state %v", label, t.From)
+		}
+		if !states[t.To] {
+			addf("%s: undeclared state %v", label, t.To)
+		}
+		if !events[t.Event] {
+			addf("%s: undeclared event %v", label, t.Event)
+		}
+		k := key{t.From, t.Event}
+		if first, ok := unguarded[k]; ok {
+			addf("%s is unreachable: transition %d has no guard", label, first)
+		} else if t.Guard == nil {
+			unguarded[k] = i + 1
+		}
+	}
+	for _, hooks := range []map[S][]Hook[S, E]{d.onEnter, d.onExit} {
+		for s := range hooks {
+			if !states[s] {
+				addf("hook on undeclared state %v", s)
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w:\n  %s", ErrDefinition, strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// Machine is one running instance of a Definition. It is not safe for
+// concurrent use.
+type Machine[S, E comparable] struct {
+	def     *Definition[S, E]
+	state   S
+	history []Change[S, E]
+	firing  bool
+}
+
+// New validates the definition and returns a machine in the initial state.
+// The initial state's entry hooks do not run.
+func (d *Definition[S, E]) New() (*Machine[S, E], error) {
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+	return &Machine[S, E]{def: d, state: d.initial}, nil
+}
+
+// State returns the current state.
+func (m *Machine[S, E]) State() S { return m.state }
+
+// History returns every transition taken so far, oldest first.
+func (m *Machine[S, E]) History() []Change[S, E] {
+	return append([]Change[S, E](nil), m.history...)
+}
+
+// Fire applies event. On success the exit hooks of the old state run, the
+// state changes, the change is recorded in the history and the entry hooks
+// of the new state run. A self-transition runs both sets of hooks. On
+// failure the machine is unchanged and the error is a *TransitionError.
+func (m *Machine[S, E]) Fire(event E) (Change[S, E], error) {
+	fail := func(err error) (Change[S, E], error) {
+		return Change[S, E]{}, &TransitionError[S, E]{State: m.state, Event: event, Err: err}
+	}
+	if m.firing {
+		return fail(ErrReentrant)
+	}
+	// Guards run inside find, so they are covered by the reentrancy check too.
+	m.firing = true
+	defer func() { m.firing = false }()
+	t, err := m.find(event)
+	if err != nil {
+		return fail(err)
+	}
+
+	c := Change[S, E]{From: m.state, To: t.To, Event: event}
+	for _, hook := range m.def.onExit[c.From] {
+		hook(c)
+	}
+	m.state = c.To
+	m.history = append(m.history, c)
+	for _, hook := range m.def.onEnter[c.To] {
+		hook(c)
+	}
+	return c, nil
+}
+
+// Can reports whether event would be accepted now. Guards are evaluated,
+// and a guard that calls Fire gets ErrReentrant.
+func (m *Machine[S, E]) Can(event E) bool {
+	defer func(firing bool) { m.firing = firing }(m.firing)
+	m.firing = true
+	_, err := m.find(event)
+	return err == nil
+}
+
+// Permitted lists the declared events accepted in the current state.
+func (m *Machine[S, E]) Permitted() []E {
+	var events []E
+	for _, e := range m.def.events {
+		if m.Can(e) {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+// find returns the transition event takes from the current state.
+func (m *Machine[S, E]) find(event E) (Transition[S, E], error) {
+	declared := false
+	for _, e := range m.def.events {
+		if e == event {
+			declared = true
+			break
+		}
+	}
+	if !declared {
+		return Transition[S, E]{}, ErrUnknownEvent
+	}
+	var refusals []any
+	for _, t := range m.def.transitions {
+		if t.From != m.state || t.Event != event {
+			continue
+		}
+		if t.Guard == nil {
+			return t, nil
+		}
+		err := t.Guard()
+		if err == nil {
+			return t, nil
+		}
+		name := t.GuardName
+		if name == "" {
+			name = "unnamed guard"
+		}
+		refusals = append(refusals, fmt.Errorf("%s: %w", name, err))
+	}
+	if len(refusals) == 0 {
+		return Transition[S, E]{}, ErrNoTransition
+	}
+	// Wrap every refusal so errors.Is and errors.As reach the guards' own errors.
+	format := "%w: " + strings.TrimSuffix(strings.Repeat("%w; ", len(refusals)), "; ")
+	return Transition[S, E]{}, fmt.Errorf(format, append([]any{ErrGuardRejected}, refusals...)...)
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// door is a small definition used by most tests: a door that can be opened,
+// closed, and locked only while closed.
+func door() *Definition[string, string] {
+	return NewDefinition[string, string]("closed").
+		States("open", "closed", "locked").
+		Events("open", "close", "lock", "unlock").
+		Permit("closed", "open", "open").
+		Permit("open", "close", "closed").
+		Permit("closed", "lock", "locked").
+		Permit("locked", "unlock", "closed")
+}
+
+// newMachine builds d or fails the test.
+func newMachine(t *testing.T, d *Definition[string, string]) *Machine[string, string] {
+	t.Helper()
+	m, err := d.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestValidate(t *testing.T) {
+	allow := func() error { return nil }
+	tests := []struct {
+		name     string
+		def      *Definition[string, string]
+		problems []string
+	}{
+		{
+			name: "transition after an unguarded one",
+			def: door().
+				PermitIf("closed", "open", "locked", "never", allow),
+			problems: []string{"transition 5 (closed --open--> locked) is unreachable: transition 1 has no guard"},
+		},
+		{
+			name: "unguarded after guarded is fine",
+			def: NewDefinition[string, string]("a").States("a", "b", "c").Events("go").
+				PermitIf("a", "go", "b", "first", allow).
+				Permit("a", "go", "c"),
+		},
+		{
+			name: "undeclared states",
+			def: door().
+				Permit("ajar", "close", "closed").
+				Permit("open", "lock", "jammed"),
+			problems: []string{
+				"transition 5 (ajar --close--> closed): undeclared state ajar",
+				"transition 6 (open --lock--> jammed): undeclared state jammed",
+			},
+		},
+		{
+			name:     "undeclared event",
+			def:      door().Permit("open", "slam", "closed"),
+			problems: []string{"transition 5 (open --slam--> closed): undeclared event slam"},
+		},
+		{
+			name:     "initial state and hooks",
+			def:      NewDefinition[string, string]("start").States("a").OnEnter("b", func(Change[string, string]) {}),
+			problems: []string{"initial state start is not declared", "hook on undeclared state b"},
+		},
+		{
+			name:     "declared twice",
+			def:      door().States("open").Events("lock"),
+			problems: []string{"state open declared twice", "event lock declared twice"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.def.New()
+			if len(tt.problems) == 0 {
+				if err != nil {
+					t.Errorf("New: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrDefinition) {
+				t.Fatalf("New: err = %v, want ErrDefinition", err)
+			}
+			for _, p := range tt.problems {
+				if !strings.Contains(err.Error(), p) {
+					t.Errorf("err = %v\nwant it to mention %s", err, p)
+				}
+			}
+			// Every problem is listed on its own line.
+			if got := strings.Count(err.Error(), "\n  "); got != len(tt.problems) {
+				t.Errorf("%d problems reported, want %d: %v", got, len(tt.problems), err)
+			}
+		})
+	}
+}
+
+// errNoBadge is the guard's own error.
+var errNoBadge = errors.New("no badge")
+
+func TestGuards(t *testing.T) {
+	hasBadge := false
+	var tries []string
+	d := NewDefinition[string, string]("hall").
+		States("hall", "vault", "office").
+		Events("enter").
+		PermitIf("hall", "enter", "vault", "has badge", func() error {
+			tries = append(tries, "vault")
+			if !hasBadge {
+				return errNoBadge
+			}
+			return nil
+		}).
+		PermitIf("hall", "enter", "office", "office hours", func() error {
+			tries = append(tries, "office")
+			return errors.New("closed for lunch")
+		})
+	m := newMachine(t, d)
+
+	_, err := m.Fire("enter")
+	var te *TransitionError[string, string]
+	if !errors.As(err, &te) || te.State != "hall" || te.Event != "enter" {
+		t.Fatalf("err = %v, want a TransitionError in hall for enter", err)
+	}
+	if !errors.Is(err, ErrGuardRejected) {
+		t.Errorf("errors.Is(err, ErrGuardRejected) = false for %v", err)
+	}
+	if !errors.Is(err, errNoBadge) {
+		t.Errorf("errors.Is(err, errNoBadge) = false for %v", err)
+	}
+	for _, want := range []string{"has badge: no badge", "office hours: closed for lunch"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v, want it to mention %s", err, want)
+		}
+	}
+	if m.State() != "hall" || len(m.History()) != 0 {
+		t.Errorf("after a refusal: state %s, history %v; want hall and nothing", m.State(), m.History())
+	}
+	if !reflect.DeepEqual(tries, []string{"vault", "office"}) {
+		t.Errorf("guards tried %v, want vault then office", tries)
+	}
+
+	// The first guard that allows wins; later ones are not asked.
+	hasBadge, tries = true, nil
+	if c, err := m.Fire("enter"); err != nil || c.To != "vault" {
+		t.Errorf("with the badge: %v, %v; want a move to vault", c, err)
+	}
+	if !reflect.DeepEqual(tries, []string{"vault"}) {
+		t.Errorf("guards tried %v, want only vault", tries)
+	}
+}
+
+func TestFireErrors(t *testing.T) {
+	m := newMachine(t, door())
+	if _, err := m.Fire("kick"); !errors.Is(err, ErrUnknownEvent) {
+		t.Errorf("undeclared event: err = %v, want ErrUnknownEvent", err)
+	}
+	if _, err := m.Fire("close"); !errors.Is(err, ErrNoTransition) || errors.Is(err, ErrGuardRejected) {
+		t.Errorf("close while closed: err = %v, want only ErrNoTransition", err)
+	}
+	if m.Can("close") || !m.Can("open") {
+		t.Errorf("Can(close) = %v, Can(open) = %v; want false, true", m.Can("close"), m.Can("open"))
+	}
+	if got := m.Permitted(); !reflect.DeepEqual(got, []string{"open", "lock"}) {
+		t.Errorf("Permitted = %v, want [open lock]", got)
+	}
+}
+
+func TestReentrantFire(t *testing.T) {
+	var m *Machine[string, string]
+	var nested error
+	d := door().OnEnter("open", func(Change[string, string]) {
+		_, nested = m.Fire("close")
+	})
+	m = newMachine(t, d)
+
+	if _, err := m.Fire("open"); err != nil {
+		t.Fatalf("Fire(open): %v", err)
+	}
+	var te *TransitionError[string, string]
+	if !errors.Is(nested, ErrReentrant) || !errors.As(nested, &te) || te.State != "open" {
+		t.Errorf("Fire from a hook: err = %v, want ErrReentrant in state open", nested)
+	}
+	if m.State() != "open" {
+		t.Errorf("state = %s, want open", m.State())
+	}
+	// Once the outer Fire returns, Fire works again.
+	if _, err := m.Fire("close"); err != nil {
+		t.Errorf("Fire after the hook: %v", err)
+	}
+}
+
+func TestFireFromGuard(t *testing.T) {
+	var m *Machine[string, string]
+	var nested []error
+	d := NewDefinition[string, string]("A").
+		States("A", "B", "C").
+		Events("go", "jump").
+		PermitIf("A", "go", "B", "jumps first", func() error {
+			_, err := m.Fire("jump")
+			nested = append(nested, err)
+			return nil
+		}).
+		Permit("A", "jump", "C")
+	m = newMachine(t, d)
+
+	// Guards asked by Can and Permitted may not fire either.
+	if !m.Can("go") || !reflect.DeepEqual(m.Permitted(), []string{"go", "jump"}) || m.State() != "A" {
+		t.Fatalf("Can/Permitted moved the machine to %s", m.State())
+	}
+	c, err := m.Fire("go")
+	if err != nil || c != (Change[string, string]{From: "A", To: "B", Event: "go"}) {
+		t.Fatalf("Fire(go) = %v, %v; want A --go--> B", c, err)
+	}
+	if len(nested) != 3 {
+		t.Fatalf("guard ran %d times, want 3", len(nested))
+	}
+	for i, err := range nested {
+		if !errors.Is(err, ErrReentrant) {
+			t.Errorf("Fire from guard call %d: err = %v, want ErrReentrant", i, err)
+		}
+	}
+	if h := m.History(); len(h) != 1 || h[0] != c {
+		t.Errorf("History = %v, want only %v", h, c)
+	}
+
+	// Can from a hook still works, and Fire works again afterwards.
+	var can bool
+	d2 := door().OnEnter("open", func(Change[string, string]) { can = m.Can("close") })
+	m = newMachine(t, d2)
+	if _, err := m.Fire("open"); err != nil || !can {
+		t.Fatalf("Fire(open) = %v, Can(close) from the hook = %v", err, can)
+	}
+	if _, err := m.Fire("close"); err != nil {
+		t.Errorf("Fire after Can in a hook: %v", err)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	var calls []string
+	record := func(what string) Hook[string, string] {
+		return func(c Change[string, string]) { calls = append(calls, what+" "+c.String()) }
+	}
+	d := door().
+		Events("knock").
+		Permit("closed", "knock", "closed").
+		OnExit("closed", record("exit")).
+		OnEnter("closed", record("enter")).
+		OnEnter("open", record("enter"))
+	m := newMachine(t, d)
+
+	if _, err := m.Fire("knock"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Fire("open"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"exit closed --knock--> closed", // A self-transition leaves and re-enters.
+		"enter closed --knock--> closed",
+		"exit closed --open--> open",
+		"enter closed --open--> open",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("hooks ran\n%s\nwant\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestHistoryIsACopy(t *testing.T) {
+	m := newMachine(t, door())
+	for _, e := range []string{"open", "close", "lock"} {
+		if _, err := m.Fire(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := m.History()
+	want := []Change[string, string]{
+		{From: "closed", To: "open", Event: "open"},
+		{From: "open", To: "closed", Event: "close"},
+		{From: "closed", To: "locked", Event: "lock"},
+	}
+	if !reflect.DeepEqual(h, want) {
+		t.Fatalf("History = %v, want %v", h, want)
+	}
+
+	h[0].To = "tampered"
+	_ = append(h[:1], Change[string, string]{From: "x", To: "y", Event: "z"})
+	if got := m.History(); !reflect.DeepEqual(got, want) {
+		t.Errorf("changing the returned slice changed History: %v", got)
+	}
+
+	// A copy is a snapshot: later transitions do not show up in it.
+	before := m.History()
+	if _, err := m.Fire("unlock"); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(before, want) {
+		t.Errorf("an earlier History changed to %v", before)
+	}
+	if got := m.History(); len(got) != 4 || got[3].Event != "unlock" {
+		t.Errorf("History = %v, want four changes ending in unlock", got)
+	}
+}
@@ -0,0 +1,192 @@
+// Package main implements a finite state machine library and uses it for the
+// levels from the Control Statements lesson, where `fallthrough` quietly
+// carries level 1 into level 2:
+//
+//	switch level {
+//	case 1:
+//		fmt.Println("Level 1")
+//		fallthrough
+//	case 2:
+//		...
+//
+// Here moving between levels is an explicit, declared transition. The game
+// has states Level 1–3, Won and Game over, and events complete, pickup, fail
+// and restart. Completing level 2 is guarded: the player needs the key that
+// the pickup event gives. Entry and exit hooks print what happens, every
+// change is kept in the history, and an event the current state does not
+// accept is rejected with a *TransitionError.
+//
+//	go run main.go dot.go fsm.go                           # a scripted game, including rejected events
+//	go run main.go dot.go fsm.go complete pickup complete  # fire your own events
+//	go run main.go dot.go fsm.go -history complete fail restart
+//	go run main.go dot.go fsm.go -dot complete > game.dot  # graph with the current state filled
+//	go test *.go                                           # definition checks, guards, hooks and history
+//
+// Exit codes: 0 when the events were processed (rejections are part of the
+// output), 1 on runtime errors, 2 on invalid usage.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Level is a state of the game.
+type Level int
+
+const (
+	Level1 Level = iota + 1
+	Level2
+	Level3
+	Won
+	GameOver
+)
+
+func (l Level) String() string {
+	switch l {
+	case Level1, Level2, Level3:
+		return fmt.Sprintf("Level %d", int(l))
+	case Won:
+		return "Won"
+	case GameOver:
+		return "Game over"
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+// Event is something the player does.
+type Event string
+
+const (
+	Complete Event = "complete"
+	Pickup   Event = "pickup"
+	Fail     Event = "fail"
+	Restart  Event = "restart"
+)
+
+// defaultScript plays through the game and shows each kind of rejection.
+var defaultScript = []Event{Complete, Complete, Pickup, Complete, Pickup, Complete, Fail, Restart, "jump", Complete, Fail, Restart}
+
+// errNoKey is the guard's reason for refusing level 2.
+var errNoKey = errors.New("the door is locked; pick up the key first")
+
+// newGame declares the game. Hooks write to w.
+func newGame(w io.Writer) (*Machine[Level, Event], *Definition[Level, Event], error) {
+	hasKey := false
+	def := NewDefinition[Level, Event](Level1).
+		States(Level1, Level2, Level3, Won, GameOver).
+		Events(Complete, Pickup, Fail, Restart).
+		Permit(Level1, Complete, Level2).
+		Permit(Level2, Pickup, Level2).
+		PermitIf(Level2, Complete, Level3, "has key", func() error {
+			if !hasKey {
+				return errNoKey
+			}
+			return nil
+		}).
+		Permit(Level3, Complete, Won).
+		Permit(Level1, Fail, GameOver).
+		Permit(Level2, Fail, GameOver).
+		Permit(Level3, Fail, GameOver).
+		Permit(GameOver, Restart, Level1).
+		Permit(Won, Restart, Level1)
+
+	for _, l := range []Level{Level1, Level2, Level3} {
+		def.OnEnter(l, func(c Change[Level, Event]) {
+			if c.From != c.To {
+				fmt.Fprintf(w, "    enter %v\n", c.To)
+			}
+		})
+		def.OnExit(l, func(c Change[Level, Event]) {
+			if c.From != c.To {
+				fmt.Fprintf(w, "    exit %v\n", c.From)
+			}
+		})
+	}
+	def.OnEnter(Level2, func(c Change[Level, Event]) {
+		if c.Event == Pickup && !hasKey {
+			hasKey = true
+			fmt.Fprintln(w, "    picked up the key")
+		}
+	})
+	def.OnEnter(Level1, func(c Change[Level, Event]) {
+		if c.Event == Restart {
+			hasKey = false
+			fmt.Fprintln(w, "    inventory cleared")
+		}
+	})
+	def.OnEnter(Won, func(Change[Level, Event]) {
+		fmt.Fprintln(w, "    you win!")
+	})
+
+	m, err := def.New()
+	return m, def, err
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("fsm", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dot := flags.Bool("dot", false, "after the events, print the state graph in DOT format instead of the trace")
+	history := flags.Bool("history", false, "print the transition history at the end")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	script := defaultScript
+	if flags.NArg() > 0 {
+		script = make([]Event, flags.NArg())
+		for i, arg := range flags.Args() {
+			script[i] = Event(arg)
+		}
+	}
+
+	trace := stdout
+	if *dot {
+		trace = io.Discard
+	}
+	game, def, err := newGame(trace)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+
+	fmt.Fprintf(trace, "start in %v\n", game.State())
+	for _, ev := range script {
+		change, err := game.Fire(ev)
+		var refused *TransitionError[Level, Event]
+		switch {
+		case errors.As(err, &refused):
+			fmt.Fprintf(trace, "%-8s rejected: %v\n", ev, refused.Err)
+			fmt.Fprintf(trace, "%-8s still in %v; accepted here: %v\n", "", game.State(), game.Permitted())
+		case err != nil:
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		default:
+			fmt.Fprintf(trace, "%-8s %v\n", ev, change)
+		}
+	}
+
+	if *dot {
+		if err := def.WriteDOT(stdout, "game", game.State()); err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+		return 0
+	}
+	if *history {
+		fmt.Fprintln(stdout, "\nhistory:")
+		for i, c := range game.History() {
+			fmt.Fprintf(stdout, "%3d. %v\n", i+1, c)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrMissingMessage is returned when a key is not found anywhere in a locale's fallback chain.
+var ErrMissingMessage = errors.New("missing message")
+
+// Message is a single catalog entry. In JSON it is either a plain string
+// ("Hello, {name}!") or an object of plural forms ({"one": "...", "other": "..."}).
+type Message struct {
+	Text   string
+	Plural map[PluralCategory]string
+}
+
+// UnmarshalJSON accepts both the plain string and the plural object forms.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		m.Text = text
+		return nil
+	}
+
+	var forms map[PluralCategory]string
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return errors.New("message must be a string or an object of plural forms")
+	}
+	if _, ok := forms[Other]; !ok {
+		return errors.New(`plural message must define the "other" form`)
+	}
+	m.Plural = forms
+	return nil
+}
+
+// Catalog holds every message for one locale, keyed by message ID.
+type Catalog map[string]Message
+
+// Bundle groups the catalogs of all known locales.
+type Bundle struct {
+	DefaultLocale string
+	catalogs      map[string]Catalog
+}
+
+// NewBundle creates an empty bundle whose fallback chains end at defaultLocale.
+func NewBundle(defaultLocale string) *Bundle {
+	return &Bundle{
+		DefaultLocale: normalizeLocale(defaultLocale),
+		catalogs:      make(map[string]Catalog),
+	}
+}
+
+// AddCatalog registers (or merges into) the catalog for a locale.
+func (b *Bundle) AddCatalog(locale string, catalog Catalog) {
+	locale = normalizeLocale(locale)
+	existing, ok := b.catalogs[locale]
+	if !ok {
+		existing = make(Catalog, len(catalog))
+		b.catalogs[locale] = existing
+	}
+	for key, msg := range catalog {
+		existing[key] = msg
+	}
+}
+
+// LoadFS loads every "<locale>.json" file in dir, e.g. "locales/pt-BR.json".
+func (b *Bundle) LoadFS(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no catalogs found in %s", dir)
+	}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		var catalog Catalog
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return fmt.Errorf("parsing %s: %w", file, err)
+		}
+		b.AddCatalog(strings.TrimSuffix(path.Base(file), ".json"), catalog)
+	}
+	return nil
+}
+
+// Locales returns the loaded locales in sorted order.
+func (b *Bundle) Locales() []string {
+	locales := make([]string, 0, len(b.catalogs))
+	for locale := range b.catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Localizer looks messages up for one requested locale, walking its fallback chain.
+type Localizer struct {
+	bundle *Bundle
+	chain  []string
+}
+
+// NewLocalizer returns a localizer for the first of the preferred locales.
+// Each locale contributes itself and its parents ("pt-BR" → "pt"), and the
+// chain always ends with the bundle's default locale.
+func (b *Bundle) NewLocalizer(preferred ...string) *Localizer {
+	var chain []string
+	seen := make(map[string]bool)
+	add := func(locale string) {
+		if locale != "" && !seen[locale] {
+			seen[locale] = true
+			chain = append(chain, locale)
+		}
+	}
+	for _, locale := range preferred {
+		locale = normalizeLocale(locale)
+		for locale != "" {
+			add(locale)
+			locale = parentLocale(locale)
+		}
+	}
+	add(b.DefaultLocale)
+
+	return &Localizer{bundle: b, chain: chain}
+}
+
+// Chain returns the locales searched by this localizer, in order.
+func (l *Localizer) Chain() []string {
+	return append([]string(nil), l.chain...)
+}
+
+// Locale returns the locale actually used for the given key, or "" if no catalog has it.
+func (l *Localizer) Locale(key string) string {
+	_, locale, ok := l.lookup(key)
+	if !ok {
+		return ""
+	}
+	return locale
+}
+
+// Message renders key with the given arguments, replacing "{name}" placeholders.
+func (l *Localizer) Message(key string, args map[string]any) (string, error) {
+	msg, _, ok := l.lookup(key)
+	if !ok {
+		return "", fmt.Errorf("%w: %q (searched %s)", ErrMissingMessage, key, strings.Join(l.chain, ", "))
+	}
+	text := msg.Text
+	if msg.Plural != nil {
+		text = msg.Plural[Other]
+	}
+	return interpolate(text, args), nil
+}
+
+// PluralMessage renders key using the plural form that matches count.
+// The count is also available to the message as the "{count}" placeholder.
+func (l *Localizer) PluralMessage(key string, count int64, args map[string]any) (string, error) {
+	msg, locale, ok := l.lookup(key)
+	if !ok {
+		return "", fmt.Errorf("%w: %q (searched %s)", ErrMissingMessage, key, strings.Join(l.chain, ", "))
+	}
+
+	merged := map[string]any{"count": count}
+	for k, v := range args {
+		merged[k] = v
+	}
+	if msg.Plural == nil {
+		return interpolate(msg.Text, merged), nil
+	}
+
+	// Use the rule of the locale that actually provided the message so a
+	// fallback to English never asks for a Russian "few" form.
+	category := pluralRuleFor(locale)(count)
+	text, ok := msg.Plural[category]
+	if !ok {
+		text = msg.Plural[Other]
+	}
+	return interpolate(text, merged), nil
+}
+
+// T is a convenience wrapper around Message that falls back to the key itself,
+// so a missing translation is visible in the output instead of silently blank.
+func (l *Localizer) T(key string, args map[string]any) string {
+	text, err := l.Message(key, args)
+	if err != nil {
+		return key
+	}
+	return text
+}
+
+// N is the plural counterpart of T.
+func (l *Localizer) N(key string, count int64, args map[string]any) string {
+	text, err := l.PluralMessage(key, count, args)
+	if err != nil {
+		return key
+	}
+	return text
+}
+
+// lookup finds key in the first locale of the chain that defines it.
+func (l *Localizer) lookup(key string) (Message, string, bool) {
+	for _, locale := range l.chain {
+		if msg, ok := l.bundle.catalogs[locale][key]; ok {
+			return msg, locale, true
+		}
+	}
+	return Message{}, "", false
+}
+
+// interpolate replaces "{name}" placeholders with the matching argument.
+// Unknown placeholders are left untouched.
+func interpolate(text string, args map[string]any) string {
+	if len(args) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+	pairs := make([]string, 0, len(args)*2)
+	for name, value := range args {
+		pairs = append(pairs, "{"+name+"}", formatArg(value))
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+func formatArg(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// normalizeLocale turns POSIX and BCP 47 spellings ("fr_FR.UTF-8", "FR-fr")
+// into the form used by catalog file names ("fr-FR").
+func normalizeLocale(locale string) string {
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+	locale = strings.ReplaceAll(strings.TrimSpace(locale), "_", "-")
+	if locale == "" || locale == "C" || locale == "POSIX" {
+		return ""
+	}
+	parts := strings.Split(locale, "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i]) // Region, e.g. "BR".
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// parentLocale drops the last subtag: "zh-Hant-TW" → "zh-Hant" → "zh" → "".
+func parentLocale(locale string) string {
+	if i := strings.LastIndex(locale, "-"); i >= 0 {
+		return locale[:i]
+	}
+	return ""
+}
+
+// baseLanguage returns the language subtag of a locale: "pt-BR" → "pt".
+func baseLanguage(locale string) string {
+	locale = normalizeLocale(locale)
+	if i := strings.Index(locale, "-"); i >= 0 {
+		return locale[:i]
+	}
+	return locale
+}
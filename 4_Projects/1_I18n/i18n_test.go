@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testBundle has English, German and Russian catalogs, and one message only
+// English has.
+func testBundle() *Bundle {
+	b := NewBundle("en")
+	b.AddCatalog("en", Catalog{
+		"greeting":    {Text: "Hello, {name}!"},
+		"only.en":     {Text: "English only"},
+		"files":       {Plural: map[PluralCategory]string{One: "{count} file", Other: "{count} files"}},
+		"files.en":    {Plural: map[PluralCategory]string{One: "{count} file", Other: "{count} files"}},
+		"placeholder": {Text: "{name} has {unknown}"},
+		"plain":       {Text: "{count} of them"},
+	})
+	b.AddCatalog("de", Catalog{
+		"greeting": {Text: "Hallo, {name}!"},
+	})
+	b.AddCatalog("ru", Catalog{
+		"files": {Plural: map[PluralCategory]string{
+			One: "{count} файл", Few: "{count} файла", Many: "{count} файлов", Other: "{count} файла",
+		}},
+	})
+	return b
+}
+
+func TestChain(t *testing.T) {
+	b := testBundle()
+	tests := []struct {
+		preferred []string
+		want      []string
+	}{
+		{[]string{"de-AT"}, []string{"de-AT", "de", "en"}},
+		{[]string{"de_AT.UTF-8"}, []string{"de-AT", "de", "en"}},
+		{[]string{"zh-Hant-TW"}, []string{"zh-Hant-TW", "zh-Hant", "zh", "en"}},
+		{[]string{"pt-BR", "de"}, []string{"pt-BR", "pt", "de", "en"}},
+		{[]string{"en-GB"}, []string{"en-GB", "en"}},
+		{[]string{"C"}, []string{"en"}},
+		{nil, []string{"en"}},
+	}
+	for _, tt := range tests {
+		l := b.NewLocalizer(tt.preferred...)
+		if got := l.Chain(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("NewLocalizer(%q).Chain() = %q, want %q", tt.preferred, got, tt.want)
+		}
+	}
+
+	// Chain returns a copy.
+	l := b.NewLocalizer("de-AT")
+	l.Chain()[0] = "fr"
+	if got := l.Chain()[0]; got != "de-AT" {
+		t.Errorf("changing the returned chain changed it to start with %s", got)
+	}
+}
+
+func TestFallback(t *testing.T) {
+	l := testBundle().NewLocalizer("de-AT")
+	tests := []struct {
+		key, want, locale string
+	}{
+		{"greeting", "Hallo, Gopher!", "de"},
+		{"only.en", "English only", "en"},
+		{"placeholder", "Gopher has {unknown}", "en"}, // Unknown placeholders are kept.
+	}
+	for _, tt := range tests {
+		got, err := l.Message(tt.key, map[string]any{"name": "Gopher"})
+		if err != nil || got != tt.want {
+			t.Errorf("Message(%q) = %q, %v; want %q", tt.key, got, err, tt.want)
+		}
+		if got := l.Locale(tt.key); got != tt.locale {
+			t.Errorf("Locale(%q) = %q, want %q", tt.key, got, tt.locale)
+		}
+	}
+}
+
+func TestMissingMessage(t *testing.T) {
+	l := testBundle().NewLocalizer("de-AT")
+	if _, err := l.Message("nope", nil); !errors.Is(err, ErrMissingMessage) || !strings.Contains(err.Error(), "de-AT, de, en") {
+		t.Errorf("Message: err = %v, want ErrMissingMessage naming the chain", err)
+	}
+	if _, err := l.PluralMessage("nope", 2, nil); !errors.Is(err, ErrMissingMessage) {
+		t.Errorf("PluralMessage: err = %v, want ErrMissingMessage", err)
+	}
+	if got := l.T("nope", nil); got != "nope" {
+		t.Errorf("T = %q, want the key", got)
+	}
+	if got := l.N("nope", 2, nil); got != "nope" {
+		t.Errorf("N = %q, want the key", got)
+	}
+	if got := l.Locale("nope"); got != "" {
+		t.Errorf("Locale = %q, want empty", got)
+	}
+}
+
+func TestPluralMessage(t *testing.T) {
+	b := testBundle()
+	tests := []struct {
+		locale, key string
+		count       int64
+		want        string
+	}{
+		{"ru", "files", 1, "1 файл"},
+		{"ru", "files", 3, "3 файла"},
+		{"ru", "files", 11, "11 файлов"},
+		{"ru", "files", 21, "21 файл"},
+		// A message found in English uses the English rule, not Russian "few".
+		{"ru", "files.en", 3, "3 files"},
+		{"ru", "files.en", 21, "21 files"},
+		{"de", "files", 1, "1 file"},
+		// A plain message still gets {count}.
+		{"en", "plain", 5, "5 of them"},
+	}
+	for _, tt := range tests {
+		if got := b.NewLocalizer(tt.locale).N(tt.key, tt.count, nil); got != tt.want {
+			t.Errorf("%s: N(%q, %d) = %q, want %q", tt.locale, tt.key, tt.count, got, tt.want)
+		}
+	}
+}
+
+// TestShippedCatalogs checks that every shipped plural message has the
+// categories its language uses, so none silently falls back to "other".
+func TestShippedCatalogs(t *testing.T) {
+	b, err := loadBundle("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, locale := range b.Locales() {
+		rule := pluralRuleFor(locale)
+		for key, msg := range b.catalogs[locale] {
+			if msg.Plural == nil {
+				continue
+			}
+			for n := int64(0); n <= 200; n++ {
+				if c := rule(n); msg.Plural[c] == "" {
+					t.Errorf("%s %s: no %q form (needed for %d)", locale, key, c, n)
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// The lessons are separate main packages and cannot import the bundle, so
+// they are localized from the outside: RunLesson runs one and rewrites the
+// lines that match a source-locale message, leaving the rest untouched.
+
+// LessonKey derives a lesson's catalog key from its directory:
+// "1_Foundations/4_Functions" → "functions", "1_Hello_World" → "hello_world".
+func LessonKey(dir string) string {
+	name := filepath.Base(filepath.Clean(dir))
+	if i := strings.IndexByte(name, '_'); i > 0 && strings.Trim(name[:i], "0123456789") == "" {
+		name = name[i+1:]
+	}
+	return strings.ToLower(name)
+}
+
+// LessonTranslator rewrites lesson output written in the source locale.
+type LessonTranslator struct {
+	lesson string
+	to     *Localizer
+	banner *regexp.Regexp // Matches the source "lesson.banner".
+	greet  *regexp.Regexp // Matches the source "greeting".
+	fields []string       // Placeholder names in banner, in group order.
+}
+
+// NewLessonTranslator translates the output of lesson from the locale of
+// from into the locale of to.
+func NewLessonTranslator(lesson string, from, to *Localizer) (*LessonTranslator, error) {
+	t := &LessonTranslator{lesson: lesson, to: to}
+	var err error
+	if t.banner, t.fields, err = templateRegexp(from, "lesson.banner"); err != nil {
+		return nil, err
+	}
+	if t.greet, _, err = templateRegexp(from, "greeting"); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Line translates one line of output. Banners keep their source title when
+// no catalog has one for the section, so a new section is never lost.
+func (t *LessonTranslator) Line(line string) string {
+	if m := t.banner.FindStringSubmatch(line); m != nil {
+		args := map[string]any{}
+		for i, name := range t.fields {
+			args[name] = m[i+1]
+		}
+		if key := fmt.Sprintf("lesson.%s.%s", t.lesson, args["number"]); t.to.Locale(key) != "" {
+			args["title"] = t.to.T(key, nil)
+		}
+		return t.to.T("lesson.banner", args)
+	}
+	if m := t.greet.FindStringSubmatch(line); m != nil {
+		return t.to.T("greeting", map[string]any{"name": m[1]})
+	}
+	return line
+}
+
+// Translate copies r to w line by line, translating each line.
+func (t *LessonTranslator) Translate(w io.Writer, r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		if _, err := fmt.Fprintln(w, t.Line(sc.Text())); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+var placeholder = regexp.MustCompile(`\{(\w+)\}`)
+
+// templateRegexp turns the message for key, e.g. "SECTION {number}: {title}",
+// into a regexp matching its rendered form, and returns the placeholder
+// names in the order of the groups. Punctuation after the last placeholder
+// is optional, so "Hello, {name}!" matches the Functions lesson's "Hello,
+// Alice" as well as Hello World's "Hello, World!".
+func templateRegexp(l *Localizer, key string) (*regexp.Regexp, []string, error) {
+	text, err := l.Message(key, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var names []string
+	var expr strings.Builder
+	expr.WriteString("^")
+	last := 0
+	for _, m := range placeholder.FindAllStringSubmatchIndex(text, -1) {
+		expr.WriteString(regexp.QuoteMeta(text[last:m[0]]))
+		expr.WriteString("(.+?)")
+		names = append(names, text[m[2]:m[3]])
+		last = m[1]
+	}
+	tail := text[last:]
+	if len(names) > 0 && tail != "" && strings.Trim(tail, "!.?¡¿ ") == "" {
+		expr.WriteString("(?:" + regexp.QuoteMeta(tail) + ")?")
+	} else {
+		expr.WriteString(regexp.QuoteMeta(tail))
+	}
+	expr.WriteString("$")
+	re, err := regexp.Compile(expr.String())
+	return re, names, err
+}
+
+// RunLesson runs the lesson in dir with "go run", passing it args and stdin,
+// and writes its output translated by t. The lesson's own errors go to stderr.
+func RunLesson(dir string, args []string, t *LessonTranslator, stdin io.Reader, stdout, stderr io.Writer) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+	goArgs := []string{"run"}
+	for _, f := range files {
+		if !strings.HasSuffix(f, "_test.go") {
+			goArgs = append(goArgs, filepath.Base(f))
+		}
+	}
+	if len(goArgs) == 1 {
+		return fmt.Errorf("no Go files in %s", dir)
+	}
+	var out bytes.Buffer
+	cmd := exec.Command("go", append(goArgs, args...)...)
+	cmd.Dir = dir
+	cmd.Stdin = stdin
+	cmd.Stdout = &out
+	cmd.Stderr = stderr
+	runErr := cmd.Run()
+	// Whatever the lesson printed before failing is still worth showing.
+	if err := t.Translate(stdout, &out); err != nil {
+		return err
+	}
+	if runErr != nil {
+		return fmt.Errorf("running %s: %w", dir, runErr)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func newTestTranslator(t *testing.T, lesson, locale string) *LessonTranslator {
+	t.Helper()
+	bundle, err := loadBundle("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := NewLessonTranslator(lesson, bundle.NewLocalizer(bundle.DefaultLocale), bundle.NewLocalizer(locale))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+func TestLessonKey(t *testing.T) {
+	tests := map[string]string{
+		"../../1_Foundations/4_Functions":  "functions",
+		"1_Hello_World":                    "hello_world",
+		"../../1_Foundations/4_Functions/": "functions",
+		"Scratch":                          "scratch",
+		"v2_Notes":                         "v2_notes",
+	}
+	for dir, want := range tests {
+		if got := LessonKey(dir); got != want {
+			t.Errorf("LessonKey(%q) = %q, want %q", dir, got, want)
+		}
+	}
+}
+
+func TestLessonTranslatorLine(t *testing.T) {
+	tests := []struct {
+		locale, line, want string
+	}{
+		{"fr", "SECTION 1: Basic Function", "SECTION 1 : Fonction de base"},
+		{"de", "Hello, Alice!", "Hallo, Alice!"},
+		{"fr", "Hello, Alice!", "Bonjour, Alice !"},
+		// The Functions lesson's greet has no "!".
+		{"fr", "Hello, Alice", "Bonjour, Alice !"},
+		{"es", "Hello, Alice", "¡Hola, Alice!"},
+		// A section with no title in any catalog keeps the English one.
+		{"fr", "SECTION 99: Something New", "SECTION 99 : Something New"},
+		{"fr", "Sum: 15, Difference: 5", "Sum: 15, Difference: 5"},
+		{"en", "Hello, Alice!", "Hello, Alice!"},
+	}
+	for _, tt := range tests {
+		tr := newTestTranslator(t, "functions", tt.locale)
+		if got := tr.Line(tt.line); got != tt.want {
+			t.Errorf("%s: Line(%q) = %q, want %q", tt.locale, tt.line, got, tt.want)
+		}
+	}
+}
+
+// TestRunFunctionsLesson runs the real Functions lesson, so a change to its
+// greeting or banners that the catalogs no longer match shows up here.
+func TestRunFunctionsLesson(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the lesson with go run")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not in PATH")
+	}
+	var stdout, stderr bytes.Buffer
+	tr := newTestTranslator(t, "functions", "fr")
+	if err := RunLesson("../../1_Foundations/4_Functions", nil, tr, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Fatalf("RunLesson: %v\n%s", err, &stderr)
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"SECTION 1 : Fonction de base\n",
+		"Bonjour, Alice !\n",
+		"SECTION 8 : Fonctions d'ordre supérieur\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	for _, english := range []string{"Hello, Alice", "SECTION 1: Basic Function"} {
+		if strings.Contains(out, english) {
+			t.Errorf("output still contains %q:\n%s", english, out)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Package main demonstrates internationalization (i18n) in Go: message catalogs
+// loaded from JSON files, locale fallback chains and CLDR-style plural rules.
+//
+// It renders the greeting from the Hello World and Functions lessons, and the
+// section banners of the Functions lesson, in the learner's locale:
+//
+//	go run main.go i18n.go lesson.go plural.go                          # locale taken from LC_ALL / LC_MESSAGES / LANG
+//	go run main.go i18n.go lesson.go plural.go --locale fr --name Alice
+//	go run main.go i18n.go lesson.go plural.go --locale pt-BR           # pt-BR greeting, everything else falls back to pt
+//	go run main.go i18n.go lesson.go plural.go --locale ru --lessons 3  # "Вы прошли 3 урока."
+//	go run main.go i18n.go lesson.go plural.go --catalogs ./my-locales  # load catalogs from a directory instead
+//	go test *.go                                                        # also runs the Functions lesson through the translator
+//
+// With --lesson it runs a Foundations lesson instead and prints its output
+// in the learner's locale: the "SECTION n: Title" banners and the "Hello,
+// name" greeting of the Hello World and Functions lessons are translated,
+// everything else is passed through. Arguments after "--" go to the lesson:
+//
+//	go run main.go i18n.go lesson.go plural.go --locale fr --lesson ../../1_Foundations/4_Functions
+//	go run main.go i18n.go lesson.go plural.go --locale de --lesson ../../1_Foundations/1_Hello_World -- --name Gopher
+//
+// Catalog files are named after their locale (locales/fr.json, locales/pt-BR.json).
+// Each entry is either a string or an object of plural forms keyed by CLDR
+// category: zero, one, two, few, many and other.
+package main
+
+import (
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultCatalogs are compiled into the binary so the demo runs from anywhere.
+//
+//go:embed locales/*.json
+var defaultCatalogs embed.FS
+
+// functionsLessonSections is the number of sections in 1_Foundations/4_Functions.
+const functionsLessonSections = 8
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run follows the same exit code contract as the Hello World CLI:
+// 0 on success, 1 on runtime errors and 2 on invalid usage.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("i18n", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	locale := flags.String("locale", localeFromEnv(), "locale to render, e.g. en, fr, pt-BR")
+	name := flags.String("name", "World", "name to greet")
+	lessons := flags.Int64("lessons", 1, "number of completed lessons (demonstrates plurals)")
+	catalogDir := flags.String("catalogs", "", "directory of <locale>.json catalogs (default: built-in catalogs)")
+	lesson := flags.String("lesson", "", "run the lesson in this directory and translate its output")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if *lesson == "" && flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "error: unexpected arguments: %s\n", strings.Join(flags.Args(), " "))
+		return 2
+	}
+
+	bundle, err := loadBundle(*catalogDir)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+
+	l := bundle.NewLocalizer(*locale)
+	if *lesson != "" {
+		t, err := NewLessonTranslator(LessonKey(*lesson), bundle.NewLocalizer(bundle.DefaultLocale), l)
+		if err == nil {
+			err = RunLesson(*lesson, flags.Args(), t, stdin, stdout, stderr)
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+		return 0
+	}
+	if err := render(stdout, l, *name, *lessons); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
+
+// loadBundle reads catalogs from dir, or from the embedded locales when dir is empty.
+func loadBundle(dir string) (*Bundle, error) {
+	bundle := NewBundle("en")
+	var fsys fs.FS = defaultCatalogs
+	pattern := "locales"
+	if dir != "" {
+		// os.DirFS defers errors, so a mistyped directory would otherwise
+		// surface as "no catalogs found in .".
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("catalogs %s: not a directory", dir)
+		}
+		fsys, pattern = os.DirFS(dir), "."
+	}
+	if err := bundle.LoadFS(fsys, pattern); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// render prints the greeting, a pluralized progress line and the lesson banners.
+func render(w io.Writer, l *Localizer, name string, lessons int64) error {
+	lines := []string{
+		l.T("locale.chain", map[string]any{"chain": strings.Join(l.Chain(), " → ")}),
+		l.T("greeting", map[string]any{"name": name}),
+		l.N("lessons.completed", lessons, nil),
+		"",
+	}
+	for i := 1; i <= functionsLessonSections; i++ {
+		lines = append(lines, LessonBanner(l, "functions", i))
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LessonBanner renders a "SECTION n: Title" banner for a lesson section,
+// e.g. LessonBanner(l, "functions", 1) → "SECTION 1: Basic Function".
+func LessonBanner(l *Localizer, lesson string, section int) string {
+	title := l.T("lesson."+lesson+"."+strconv.Itoa(section), nil)
+	return l.T("lesson.banner", map[string]any{"number": section, "title": title})
+}
+
+// localeFromEnv returns the learner's locale using the usual POSIX precedence.
+func localeFromEnv() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := normalizeLocale(os.Getenv(key)); value != "" {
+			return value
+		}
+	}
+	return "en"
+}
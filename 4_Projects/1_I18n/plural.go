@@ -0,0 +1,121 @@
+package main
+
+// PluralCategory is one of the CLDR plural categories a language can use.
+// Every language has at least "other"; most only use a subset of the rest.
+type PluralCategory string
+
+const (
+	Zero  PluralCategory = "zero"
+	One   PluralCategory = "one"
+	Two   PluralCategory = "two"
+	Few   PluralCategory = "few"
+	Many  PluralCategory = "many"
+	Other PluralCategory = "other"
+)
+
+// PluralRule maps a count to its plural category for one language.
+type PluralRule func(n int64) PluralCategory
+
+// pluralRules holds the cardinal rules for the languages we ship, keyed by
+// base language. They follow the CLDR rules for integer values (v = 0).
+var pluralRules = map[string]PluralRule{
+	"en": pluralOneOther,
+	"de": pluralOneOther,
+	"es": pluralOneOther,
+	"it": pluralOneOther,
+	"nl": pluralOneOther,
+	"fr": pluralFrench,
+	"pt": pluralFrench,
+	"ru": pluralEastSlavic,
+	"uk": pluralEastSlavic,
+	"pl": pluralPolish,
+	"ar": pluralArabic,
+	"ja": pluralOtherOnly,
+	"zh": pluralOtherOnly,
+}
+
+// pluralRuleFor returns the rule for a locale, using its base language.
+// Unknown languages fall back to the English rule.
+func pluralRuleFor(locale string) PluralRule {
+	if rule, ok := pluralRules[baseLanguage(locale)]; ok {
+		return rule
+	}
+	return pluralOneOther
+}
+
+// pluralOneOther: one → n = 1 (English, German, Spanish, ...).
+func pluralOneOther(n int64) PluralCategory {
+	if n == 1 {
+		return One
+	}
+	return Other
+}
+
+// pluralFrench: one → i = 0,1 (French, Portuguese).
+func pluralFrench(n int64) PluralCategory {
+	if n == 0 || n == 1 {
+		return One
+	}
+	return Other
+}
+
+// pluralEastSlavic: one → n % 10 = 1 and n % 100 != 11;
+// few → n % 10 = 2..4 and n % 100 != 12..14; many → everything else.
+func pluralEastSlavic(n int64) PluralCategory {
+	n = abs(n)
+	mod10, mod100 := n%10, n%100
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return One
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return Few
+	default:
+		return Many
+	}
+}
+
+// pluralPolish: one → n = 1; few → n % 10 = 2..4 and n % 100 != 12..14; many → everything else.
+func pluralPolish(n int64) PluralCategory {
+	n = abs(n)
+	mod10, mod100 := n%10, n%100
+	switch {
+	case n == 1:
+		return One
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return Few
+	default:
+		return Many
+	}
+}
+
+// pluralArabic: zero → 0; one → 1; two → 2; few → n % 100 = 3..10; many → n % 100 = 11..99.
+func pluralArabic(n int64) PluralCategory {
+	n = abs(n)
+	mod100 := n % 100
+	switch {
+	case n == 0:
+		return Zero
+	case n == 1:
+		return One
+	case n == 2:
+		return Two
+	case mod100 >= 3 && mod100 <= 10:
+		return Few
+	case mod100 >= 11:
+		return Many
+	default:
+		return Other
+	}
+}
+
+// pluralOtherOnly: languages without grammatical number (Japanese, Chinese).
+func pluralOtherOnly(n int64) PluralCategory {
+	return Other
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
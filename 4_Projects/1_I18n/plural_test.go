@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+func TestPluralRules(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   map[int64]PluralCategory
+	}{
+		{"en", map[int64]PluralCategory{0: Other, 1: One, 2: Other, 11: Other, 21: Other}},
+		{"fr", map[int64]PluralCategory{0: One, 1: One, 2: Other, 100: Other}},
+		{"pt-BR", map[int64]PluralCategory{0: One, 1: One, 2: Other}},
+		{"ru", map[int64]PluralCategory{
+			0: Many, 1: One, 2: Few, 4: Few, 5: Many, 11: Many, 12: Many, 14: Many,
+			21: One, 22: Few, 25: Many, 101: One, 111: Many, 112: Many, 122: Few, -3: Few,
+		}},
+		{"pl", map[int64]PluralCategory{
+			0: Many, 1: One, 2: Few, 4: Few, 5: Many, 12: Many, 14: Many,
+			21: Many, 22: Few, 101: Many, 112: Many, 1002: Few,
+		}},
+		{"ar", map[int64]PluralCategory{
+			0: Zero, 1: One, 2: Two, 3: Few, 10: Few, 11: Many, 99: Many,
+			100: Other, 101: Other, 102: Other, 103: Few, 111: Many, 200: Other,
+		}},
+		{"ja", map[int64]PluralCategory{0: Other, 1: Other, 2: Other}},
+		{"xx", map[int64]PluralCategory{1: One, 2: Other}}, // Unknown: English rule.
+	}
+	for _, tt := range tests {
+		rule := pluralRuleFor(tt.locale)
+		for n, want := range tt.want {
+			if got := rule(n); got != want {
+				t.Errorf("%s: rule(%d) = %s, want %s", tt.locale, n, got, want)
+			}
+		}
+	}
+}
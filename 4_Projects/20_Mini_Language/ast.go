@@ -0,0 +1,132 @@
+package main
+
+// Expressions.
+type (
+	Expr interface{ exprPos() Pos }
+
+	IntLit struct {
+		At    Pos
+		Value int64
+	}
+	StringLit struct {
+		At    Pos
+		Value string
+	}
+	BoolLit struct {
+		At    Pos
+		Value bool
+	}
+	Ident struct {
+		At   Pos
+		Name string
+	}
+	Unary struct {
+		At Pos
+		Op string
+		X  Expr
+	}
+	Binary struct {
+		At   Pos // Position of the operator.
+		Op   string
+		X, Y Expr
+	}
+	// Call is only used for the built-in functions print and len.
+	Call struct {
+		At   Pos
+		Func string
+		Args []Expr
+	}
+)
+
+func (e *IntLit) exprPos() Pos    { return e.At }
+func (e *StringLit) exprPos() Pos { return e.At }
+func (e *BoolLit) exprPos() Pos   { return e.At }
+func (e *Ident) exprPos() Pos     { return e.At }
+func (e *Unary) exprPos() Pos     { return e.At }
+func (e *Binary) exprPos() Pos    { return e.At }
+func (e *Call) exprPos() Pos      { return e.At }
+
+// Statements.
+type (
+	Stmt interface{ stmtPos() Pos }
+
+	// ExprStmt is a call, or in the REPL any expression, whose value is shown.
+	ExprStmt struct {
+		X Expr
+	}
+	// Define is "name := value".
+	Define struct {
+		At    Pos
+		Name  string
+		Value Expr
+	}
+	// Assign is "name = value" or a compound form such as "name += value";
+	// Op is "=" or the arithmetic operator.
+	Assign struct {
+		At    Pos
+		Name  string
+		Op    string
+		Value Expr
+	}
+	// IncDec is "name++" or "name--".
+	IncDec struct {
+		At   Pos
+		Name string
+		Op   string
+	}
+	Block struct {
+		At    Pos
+		Stmts []Stmt
+	}
+	If struct {
+		At   Pos
+		Init Stmt // May be nil.
+		Cond Expr
+		Then *Block
+		Else Stmt // Nil, *Block or *If.
+	}
+	// For covers the three-clause, condition-only and infinite forms; any
+	// of Init, Cond and Post may be nil.
+	For struct {
+		At   Pos
+		Init Stmt
+		Cond Expr
+		Post Stmt
+		Body *Block
+	}
+	// ForRange is "for i := range n" over an int, or
+	// "for i, ch := range s" over a string. Key and Value may be empty.
+	ForRange struct {
+		At         Pos
+		Key, Value string
+		X          Expr
+		Body       *Block
+	}
+	Switch struct {
+		At    Pos
+		Init  Stmt
+		Tag   Expr // Nil for a tagless switch.
+		Cases []*Case
+	}
+	Case struct {
+		At    Pos
+		Exprs []Expr // Empty for default.
+		Body  []Stmt
+	}
+	// Branch is break, continue or fallthrough.
+	Branch struct {
+		At      Pos
+		Keyword string
+	}
+)
+
+func (s *ExprStmt) stmtPos() Pos { return s.X.exprPos() }
+func (s *Define) stmtPos() Pos   { return s.At }
+func (s *Assign) stmtPos() Pos   { return s.At }
+func (s *IncDec) stmtPos() Pos   { return s.At }
+func (s *Block) stmtPos() Pos    { return s.At }
+func (s *If) stmtPos() Pos       { return s.At }
+func (s *For) stmtPos() Pos      { return s.At }
+func (s *ForRange) stmtPos() Pos { return s.At }
+func (s *Switch) stmtPos() Pos   { return s.At }
+func (s *Branch) stmtPos() Pos   { return s.At }
@@ -0,0 +1,580 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxSteps stops runaway loops such as a forgotten break in "for {}".
+const DefaultMaxSteps = 1_000_000
+
+// MaxStringLen caps the length of strings built with +, so a loop that doubles
+// a string fails long before the step limit would stop it.
+const MaxStringLen = 1 << 20
+
+// RuntimeError reports a failure while a program runs.
+type RuntimeError struct {
+	Pos Pos
+	Msg string
+}
+
+func (e *RuntimeError) Error() string { return fmt.Sprintf("%v: runtime error: %s", e.Pos, e.Msg) }
+
+func runtimeErrorf(pos Pos, format string, args ...any) error {
+	return &RuntimeError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Values are int64, string or bool. A variable keeps the type of the value
+// it was declared with, as in Go.
+func typeName(v any) string {
+	switch v.(type) {
+	case int64:
+		return "int"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// formatValue prints a value the way fmt.Println would.
+func formatValue(v any) string { return fmt.Sprint(v) }
+
+// quoteValue prints a value as it would appear in source, for the REPL.
+func quoteValue(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(v)
+}
+
+type scope struct {
+	vars   map[string]any
+	parent *scope
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{vars: map[string]any{}, parent: parent}
+}
+
+func (s *scope) lookup(name string) (*scope, bool) {
+	for ; s != nil; s = s.parent {
+		if _, ok := s.vars[name]; ok {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// control says how a statement finished.
+type control int
+
+const (
+	normal control = iota
+	breakLoop
+	continueLoop
+	fallThrough
+)
+
+// Interpreter runs programs. Its global scope survives between calls to Run,
+// which is what lets the REPL build on earlier input.
+type Interpreter struct {
+	Out      io.Writer
+	MaxSteps int // Statements and loop iterations per Run before giving up; 0 means DefaultMaxSteps.
+
+	globals *scope
+	steps   int
+	repl    bool
+}
+
+// NewInterpreter returns an interpreter whose print writes to out.
+func NewInterpreter(out io.Writer) *Interpreter {
+	return &Interpreter{Out: out, globals: newScope(nil)}
+}
+
+// Run executes stmts in the global scope. In the REPL, show receives the
+// value of each top-level expression statement; it may be nil.
+func (in *Interpreter) Run(stmts []Stmt, show func(any)) error {
+	in.steps = 0
+	in.repl = show != nil
+	for _, s := range stmts {
+		if es, ok := s.(*ExprStmt); ok && show != nil {
+			if call, ok := es.X.(*Call); !ok || call.Func != "print" {
+				v, err := in.eval(es.X, in.globals)
+				if err != nil {
+					return err
+				}
+				show(v)
+				continue
+			}
+		}
+		if _, err := in.exec(s, in.globals); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Globals returns the global variables and their values, sorted by name.
+func (in *Interpreter) Globals() []string {
+	names := make([]string, 0, len(in.globals.vars))
+	for name := range in.globals.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		v := in.globals.vars[name]
+		names[i] = fmt.Sprintf("%s %s = %s", name, typeName(v), quoteValue(v))
+	}
+	return names
+}
+
+// step counts one statement or loop iteration against MaxSteps.
+func (in *Interpreter) step(pos Pos) error {
+	in.steps++
+	limit := in.MaxSteps
+	if limit <= 0 {
+		limit = DefaultMaxSteps
+	}
+	if in.steps > limit {
+		return runtimeErrorf(pos, "gave up after %d steps; is there a loop without a way out?", limit)
+	}
+	return nil
+}
+
+func (in *Interpreter) exec(s Stmt, sc *scope) (control, error) {
+	if err := in.step(s.stmtPos()); err != nil {
+		return normal, err
+	}
+
+	switch s := s.(type) {
+	case *ExprStmt:
+		var err error
+		if call, ok := s.X.(*Call); ok && call.Func == "print" {
+			err = in.print(call, sc)
+		} else {
+			_, err = in.eval(s.X, sc)
+		}
+		return normal, err
+	case *Define:
+		v, err := in.eval(s.Value, sc)
+		if err != nil {
+			return normal, err
+		}
+		return normal, in.define(sc, s.At, s.Name, v)
+	case *Assign:
+		return normal, in.assign(s, sc)
+	case *IncDec:
+		op := "+"
+		if s.Op == "--" {
+			op = "-"
+		}
+		return normal, in.assign(&Assign{At: s.At, Name: s.Name, Op: op, Value: &IntLit{At: s.At, Value: 1}}, sc)
+	case *Block:
+		return in.execList(s.Stmts, newScope(sc))
+	case *If:
+		return in.execIf(s, sc)
+	case *For:
+		return in.execFor(s, sc)
+	case *ForRange:
+		return in.execRange(s, sc)
+	case *Switch:
+		return in.execSwitch(s, sc)
+	case *Branch:
+		switch s.Keyword {
+		case "break":
+			return breakLoop, nil
+		case "continue":
+			return continueLoop, nil
+		}
+		return fallThrough, nil
+	}
+	return normal, runtimeErrorf(s.stmtPos(), "unknown statement %T", s)
+}
+
+// execList runs statements until one breaks, continues or falls through.
+func (in *Interpreter) execList(stmts []Stmt, sc *scope) (control, error) {
+	for _, s := range stmts {
+		ctl, err := in.exec(s, sc)
+		if err != nil || ctl != normal {
+			return ctl, err
+		}
+	}
+	return normal, nil
+}
+
+func (in *Interpreter) define(sc *scope, pos Pos, name string, v any) error {
+	if name == "_" {
+		return nil
+	}
+	// Unlike Go, the REPL lets a top-level name be declared again, so an
+	// experiment can be retyped.
+	if _, ok := sc.vars[name]; ok && !(in.repl && sc == in.globals) {
+		return runtimeErrorf(pos, "no new variables on left side of := (%s is already declared in this scope)", name)
+	}
+	sc.vars[name] = v
+	return nil
+}
+
+func (in *Interpreter) assign(s *Assign, sc *scope) error {
+	owner, ok := sc.lookup(s.Name)
+	if !ok {
+		return runtimeErrorf(s.At, "undefined: %s", s.Name)
+	}
+	v, err := in.eval(s.Value, sc)
+	if err != nil {
+		return err
+	}
+	old := owner.vars[s.Name]
+	if s.Op != "=" {
+		if v, err = binaryOp(s.At, s.Op, old, v); err != nil {
+			return err
+		}
+	}
+	if typeName(v) != typeName(old) {
+		return runtimeErrorf(s.At, "cannot assign %s to %s (variable of type %s)", typeName(v), s.Name, typeName(old))
+	}
+	owner.vars[s.Name] = v
+	return nil
+}
+
+func (in *Interpreter) cond(x Expr, sc *scope, what string) (bool, error) {
+	v, err := in.eval(x, sc)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, runtimeErrorf(x.exprPos(), "non-boolean %s (%s) used as %s", quoteValue(v), typeName(v), what)
+	}
+	return b, nil
+}
+
+func (in *Interpreter) execIf(s *If, sc *scope) (control, error) {
+	sc = newScope(sc) // The init statement's variables live until the end of the else.
+	if s.Init != nil {
+		if _, err := in.exec(s.Init, sc); err != nil {
+			return normal, err
+		}
+	}
+	ok, err := in.cond(s.Cond, sc, "if condition")
+	if err != nil {
+		return normal, err
+	}
+	if ok {
+		return in.execList(s.Then.Stmts, newScope(sc))
+	}
+	if s.Else != nil {
+		return in.exec(s.Else, sc)
+	}
+	return normal, nil
+}
+
+func (in *Interpreter) execFor(s *For, sc *scope) (control, error) {
+	sc = newScope(sc)
+	if s.Init != nil {
+		if _, err := in.exec(s.Init, sc); err != nil {
+			return normal, err
+		}
+	}
+	for {
+		if err := in.step(s.At); err != nil {
+			return normal, err
+		}
+		if s.Cond != nil {
+			ok, err := in.cond(s.Cond, sc, "for condition")
+			if err != nil || !ok {
+				return normal, err
+			}
+		}
+		ctl, err := in.execList(s.Body.Stmts, newScope(sc))
+		if err != nil {
+			return normal, err
+		}
+		if ctl == breakLoop {
+			return normal, nil
+		}
+		if s.Post != nil {
+			if _, err := in.exec(s.Post, sc); err != nil {
+				return normal, err
+			}
+		}
+	}
+}
+
+func (in *Interpreter) execRange(s *ForRange, sc *scope) (control, error) {
+	x, err := in.eval(s.X, sc)
+	if err != nil {
+		return normal, err
+	}
+	// iteration runs the body once; it reports whether the loop should stop.
+	iteration := func(key, value any) (bool, error) {
+		if err := in.step(s.At); err != nil {
+			return true, err
+		}
+		// As in Go, the iteration variables get a scope of their own, so
+		// the body may redeclare them.
+		vars := newScope(sc)
+		if s.Key != "" {
+			vars.vars[s.Key] = key
+		}
+		if s.Value != "" {
+			vars.vars[s.Value] = value
+		}
+		delete(vars.vars, "_")
+		ctl, err := in.execList(s.Body.Stmts, newScope(vars))
+		return ctl == breakLoop, err
+	}
+
+	switch x := x.(type) {
+	case int64:
+		if s.Value != "" {
+			return normal, runtimeErrorf(s.At, "range over %d permits only one iteration variable", x)
+		}
+		for i := int64(0); i < x; i++ {
+			if stop, err := iteration(i, nil); stop || err != nil {
+				return normal, err
+			}
+		}
+	case string:
+		// Like Go, the key is the byte offset; the value is the character
+		// as a one-character string, since there is no rune type.
+		for i, r := range x {
+			if stop, err := iteration(int64(i), string(r)); stop || err != nil {
+				return normal, err
+			}
+		}
+	default:
+		return normal, runtimeErrorf(s.X.exprPos(), "cannot range over %s (%s)", quoteValue(x), typeName(x))
+	}
+	return normal, nil
+}
+
+// execSwitch follows Go: the tag is evaluated once, cases are tried top to
+// bottom and left to right, the first match runs with no implicit
+// fallthrough, default runs only when nothing matched wherever it is
+// written, and break leaves the switch rather than an enclosing loop.
+func (in *Interpreter) execSwitch(s *Switch, sc *scope) (control, error) {
+	sc = newScope(sc)
+	if s.Init != nil {
+		if _, err := in.exec(s.Init, sc); err != nil {
+			return normal, err
+		}
+	}
+	var tag any = true
+	if s.Tag != nil {
+		var err error
+		if tag, err = in.eval(s.Tag, sc); err != nil {
+			return normal, err
+		}
+	}
+
+	chosen := -1
+	for i, c := range s.Cases {
+		for _, x := range c.Exprs {
+			v, err := in.eval(x, sc)
+			if err != nil {
+				return normal, err
+			}
+			if typeName(v) != typeName(tag) {
+				if s.Tag == nil {
+					return normal, runtimeErrorf(x.exprPos(), "non-boolean case %s (%s) in switch with no condition", quoteValue(v), typeName(v))
+				}
+				return normal, runtimeErrorf(x.exprPos(), "mismatched types in case: %s (%s) vs switch value %s (%s)", quoteValue(v), typeName(v), quoteValue(tag), typeName(tag))
+			}
+			if v == tag {
+				chosen = i
+				break
+			}
+		}
+		if chosen >= 0 {
+			break
+		}
+	}
+	if chosen < 0 {
+		for i, c := range s.Cases {
+			if c.Exprs == nil {
+				chosen = i
+			}
+		}
+	}
+	if chosen < 0 {
+		return normal, nil
+	}
+
+	for i := chosen; i < len(s.Cases); i++ {
+		ctl, err := in.execList(s.Cases[i].Body, newScope(sc))
+		switch {
+		case err != nil:
+			return normal, err
+		case ctl == fallThrough:
+			continue
+		case ctl == breakLoop:
+			return normal, nil
+		}
+		return ctl, nil // normal, or continue for an enclosing loop.
+	}
+	return normal, nil
+}
+
+func (in *Interpreter) eval(x Expr, sc *scope) (any, error) {
+	switch x := x.(type) {
+	case *IntLit:
+		return x.Value, nil
+	case *StringLit:
+		return x.Value, nil
+	case *BoolLit:
+		return x.Value, nil
+	case *Ident:
+		owner, ok := sc.lookup(x.Name)
+		if !ok {
+			return nil, runtimeErrorf(x.At, "undefined: %s", x.Name)
+		}
+		return owner.vars[x.Name], nil
+	case *Unary:
+		v, err := in.eval(x.X, sc)
+		if err != nil {
+			return nil, err
+		}
+		switch v := v.(type) {
+		case int64:
+			switch x.Op {
+			case "-":
+				return -v, nil
+			case "+":
+				return v, nil
+			}
+		case bool:
+			if x.Op == "!" {
+				return !v, nil
+			}
+		}
+		return nil, runtimeErrorf(x.At, "invalid operation: operator %s not defined on %s (%s)", x.Op, quoteValue(v), typeName(v))
+	case *Binary:
+		if x.Op == "&&" || x.Op == "||" {
+			return in.logical(x, sc)
+		}
+		a, err := in.eval(x.X, sc)
+		if err != nil {
+			return nil, err
+		}
+		b, err := in.eval(x.Y, sc)
+		if err != nil {
+			return nil, err
+		}
+		return binaryOp(x.At, x.Op, a, b)
+	case *Call:
+		return in.call(x, sc)
+	}
+	return nil, runtimeErrorf(x.exprPos(), "unknown expression %T", x)
+}
+
+func (in *Interpreter) logical(x *Binary, sc *scope) (any, error) {
+	a, err := in.cond(x.X, sc, "operand of "+x.Op)
+	if err != nil {
+		return nil, err
+	}
+	if (x.Op == "&&") != a {
+		return a, nil // Short circuit.
+	}
+	return in.cond(x.Y, sc, "operand of "+x.Op)
+}
+
+func binaryOp(pos Pos, op string, a, b any) (any, error) {
+	if typeName(a) != typeName(b) {
+		return nil, runtimeErrorf(pos, "invalid operation: %s %s %s (mismatched types %s and %s)", quoteValue(a), op, quoteValue(b), typeName(a), typeName(b))
+	}
+	switch a := a.(type) {
+	case int64:
+		b := b.(int64)
+		switch op {
+		case "+":
+			return a + b, nil
+		case "-":
+			return a - b, nil
+		case "*":
+			return a * b, nil
+		case "/", "%":
+			if b == 0 {
+				return nil, runtimeErrorf(pos, "integer divide by zero")
+			}
+			if op == "/" {
+				return a / b, nil
+			}
+			return a % b, nil
+		}
+		return compare(op, a < b, a == b), nil
+	case string:
+		b := b.(string)
+		if op == "+" {
+			if len(a)+len(b) > MaxStringLen {
+				return nil, runtimeErrorf(pos, "string concatenation of %d bytes exceeds the limit of %d", len(a)+len(b), MaxStringLen)
+			}
+			return a + b, nil
+		}
+		switch op {
+		case "==", "!=", "<", "<=", ">", ">=":
+			return compare(op, a < b, a == b), nil
+		}
+	case bool:
+		b := b.(bool)
+		switch op {
+		case "==":
+			return a == b, nil
+		case "!=":
+			return a != b, nil
+		}
+	}
+	return nil, runtimeErrorf(pos, "invalid operation: operator %s not defined on %s (%s)", op, quoteValue(a), typeName(a))
+}
+
+// compare evaluates a comparison operator from "less" and "equal".
+func compare(op string, less, equal bool) bool {
+	switch op {
+	case "==":
+		return equal
+	case "!=":
+		return !equal
+	case "<":
+		return less
+	case "<=":
+		return less || equal
+	case ">":
+		return !less && !equal
+	}
+	return !less // ">="
+}
+
+func (in *Interpreter) call(c *Call, sc *scope) (any, error) {
+	if c.Func == "print" {
+		return nil, runtimeErrorf(c.At, "print(...) (no value) used as value")
+	}
+	// len is the only other built-in.
+	v, err := in.eval(c.Args[0], sc)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return nil, runtimeErrorf(c.At, "invalid argument: %s (%s) for len", quoteValue(v), typeName(v))
+	}
+	return int64(len(s)), nil
+}
+
+// print writes its arguments separated by spaces, like fmt.Println.
+func (in *Interpreter) print(c *Call, sc *scope) error {
+	parts := make([]string, len(c.Args))
+	for i, a := range c.Args {
+		v, err := in.eval(a, sc)
+		if err != nil {
+			return err
+		}
+		parts[i] = formatValue(v)
+	}
+	_, err := fmt.Fprintln(in.Out, strings.Join(parts, " "))
+	return err
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// runSource parses and runs src, returning what it printed.
+func runSource(t *testing.T, src string) (string, error) {
+	t.Helper()
+	stmts, err := Parse(src, false)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	var out strings.Builder
+	err = NewInterpreter(&out).Run(stmts, nil)
+	return out.String(), err
+}
+
+type runCase struct {
+	name, src string
+	out       string // Printed lines joined by "|".
+	err       string // Empty when the program must run to the end.
+}
+
+func runCases(t *testing.T, tests []runCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runSource(t, tt.src)
+			if got := strings.ReplaceAll(strings.TrimSuffix(out, "\n"), "\n", "|"); got != tt.out {
+				t.Errorf("printed %q, want %q", got, tt.out)
+			}
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("Run: %v", err)
+			case tt.err != "" && (err == nil || err.Error() != tt.err):
+				t.Errorf("Run: err = %v, want %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSwitchInLoop(t *testing.T) {
+	runCases(t, []runCase{
+		{
+			name: "break leaves the switch, not the loop",
+			src:  "for i := range 3 {\nswitch i {\ncase 1:\nbreak\n}\nprint(i)\n}",
+			out:  "0|1|2",
+		},
+		{
+			name: "continue skips the rest of the loop body",
+			src:  "for i := range 3 {\nswitch i {\ncase 1:\ncontinue\n}\nprint(i)\n}",
+			out:  "0|2",
+		},
+		{
+			name: "fallthrough runs the next case without testing it",
+			src:  "for i := range 3 {\nswitch i {\ncase 0:\nprint(\"zero\")\nfallthrough\ncase 1:\nprint(\"low\")\ndefault:\nprint(\"other\")\n}\n}",
+			out:  "zero|low|low|other",
+		},
+		{
+			name: "fallthrough then break stops at the break",
+			src:  "for i := range 2 {\nswitch i {\ncase 0:\nfallthrough\ncase 1:\nif i == 0 {\nbreak\n}\nprint(\"one\", i)\n}\nprint(\"after\", i)\n}",
+			out:  "after 0|one 1|after 1",
+		},
+		{
+			name: "fallthrough from a case into default above it",
+			src:  "switch 2 {\ncase 1:\nprint(1)\ndefault:\nprint(\"default\")\ncase 2:\nprint(2)\nfallthrough\ncase 3:\nprint(3)\n}",
+			out:  "2|3",
+		},
+		{
+			name: "default runs only when nothing matched",
+			src:  "switch 5 {\ndefault:\nprint(\"default\")\ncase 1, 5:\nprint(\"five\")\n}",
+			out:  "five",
+		},
+		{
+			name: "break in a loop inside a switch leaves only the loop",
+			src:  "switch {\ncase true:\nfor {\nbreak\n}\nprint(\"still in case\")\n}",
+			out:  "still in case",
+		},
+	})
+}
+
+func TestArithmetic(t *testing.T) {
+	runCases(t, []runCase{
+		{
+			name: "int64 wraps on overflow like Go at run time",
+			src:  "x := 9223372036854775807\nx++\nprint(x)\ny := -x\nprint(y)\nx--\nprint(x)",
+			out:  "-9223372036854775808|-9223372036854775808|9223372036854775807",
+		},
+		{
+			name: "multiplication wraps",
+			src:  "x := 4611686018427387904\nx *= 2\nprint(x)",
+			out:  "-9223372036854775808",
+		},
+		{
+			name: "min int divided by -1",
+			src:  "x := -9223372036854775807 - 1\nprint(x / -1, x % -1)",
+			out:  "-9223372036854775808 0",
+		},
+		{
+			name: "division truncates toward zero",
+			src:  "print(7 / 2, -7 / 2, 7 % -2, -7 % 2)",
+			out:  "3 -3 1 -1",
+		},
+		{
+			name: "divide by zero",
+			src:  "x := 0\nprint(\"before\")\nprint(1 / x)",
+			out:  "before",
+			err:  "3:9: runtime error: integer divide by zero",
+		},
+		{
+			name: "remainder by zero",
+			src:  "x := 0\nx %= 0",
+			err:  "2:1: runtime error: integer divide by zero",
+		},
+		{
+			name: "mismatched types",
+			src:  "print(1 + \"a\")",
+			err:  `1:9: runtime error: invalid operation: 1 + "a" (mismatched types int and string)`,
+		},
+		{
+			name: "short circuit skips the failing operand",
+			src:  "x := 0\nprint(x != 0 && 1/x > 0, x == 0 || 1/x > 0)",
+			out:  "false true",
+		},
+	})
+}
+
+func TestScoping(t *testing.T) {
+	runCases(t, []runCase{
+		{
+			name: "range variable redeclared in the body",
+			src:  "for i := range 3 { i := i * 2; print(i) }",
+			out:  "0|2|4",
+		},
+		{
+			name: "range value redeclared with another type",
+			src:  `for i, c := range "ab" { c := 1; print(i, c) }`,
+			out:  "0 1|1 1",
+		},
+		{
+			name: "three-clause variable redeclared in the body",
+			src:  "for i := 0; i < 2; i++ { i := 10; print(i) }",
+			out:  "10|10",
+		},
+		{
+			name: "range variables do not outlive the loop",
+			src:  "for i := range 1 {}\nprint(i)",
+			err:  "2:7: runtime error: undefined: i",
+		},
+		{
+			name: "if init variable visible in else",
+			src:  "if x := 3; x > 5 {\nprint(\"big\")\n} else {\nprint(x)\n}",
+			out:  "3",
+		},
+		{
+			name: "inner := shadows, = assigns outward",
+			src:  "x := 1\n{\nx := 2\nx = 3\nprint(x)\n}\nprint(x)\n{\nx = 4\n}\nprint(x)",
+			out:  "3|1|4",
+		},
+		{
+			name: "redeclaring in the same scope",
+			src:  "x := 1\nx := 2",
+			err:  "2:1: runtime error: no new variables on left side of := (x is already declared in this scope)",
+		},
+		{
+			name: "variables keep their type",
+			src:  "x := 1\nx = \"one\"",
+			err:  "2:1: runtime error: cannot assign string to x (variable of type int)",
+		},
+		{
+			name: "blank range variable is not declared",
+			src:  `for _, c := range "hi" { print(c) }` + "\nprint(_)",
+			out:  "h|i",
+			err:  "2:7: runtime error: undefined: _",
+		},
+		{
+			name: "switch case bodies have their own scope",
+			src:  "switch {\ncase true:\nx := 1\nprint(x)\nfallthrough\ndefault:\nx := 2\nprint(x)\n}",
+			out:  "1|2",
+		},
+	})
+}
+
+func TestMaxSteps(t *testing.T) {
+	stmts, err := Parse("for {}", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := NewInterpreter(&strings.Builder{})
+	in.MaxSteps = 100
+	err = in.Run(stmts, nil)
+	var re *RuntimeError
+	if !errors.As(err, &re) || !strings.Contains(re.Msg, "gave up after 100 steps") {
+		t.Errorf("Run: err = %v, want gave up after 100 steps", err)
+	}
+}
+
+func TestMaxStringLen(t *testing.T) {
+	tests := []struct {
+		src    string
+		errMsg string // Empty when the program must succeed.
+	}{
+		// 2^20 bytes is exactly the limit.
+		{`s := "x"; for i := 0; i < 20; i++ { s += s }; print(len(s))`, ""},
+		{`s := "x"; for i := 0; i < 21; i++ { s += s }`, "string concatenation of 2097152 bytes exceeds the limit of 1048576"},
+		{`s := "x"; for i := 0; i < 20; i++ { s = s + s }; t := s + "y"`, "string concatenation of 1048577 bytes exceeds the limit of 1048576"},
+		// Without the limit this would need about 2^60 bytes within 60 steps.
+		{`s := "x"; for { s = s + s }`, "exceeds the limit"},
+	}
+	for _, tt := range tests {
+		stmts, err := Parse(tt.src, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out strings.Builder
+		err = NewInterpreter(&out).Run(stmts, nil)
+		if tt.errMsg == "" {
+			if err != nil || out.String() != "1048576\n" {
+				t.Errorf("%s: output %q, err %v; want 1048576", tt.src, out.String(), err)
+			}
+			continue
+		}
+		var re *RuntimeError
+		if !errors.As(err, &re) || !strings.Contains(re.Msg, tt.errMsg) {
+			t.Errorf("%s: err = %v, want %q", tt.src, err, tt.errMsg)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pos is a 1-based line and column in the source.
+type Pos struct {
+	Line, Col int
+}
+
+func (p Pos) String() string { return fmt.Sprintf("%d:%d", p.Line, p.Col) }
+
+// SyntaxError reports source that could not be lexed or parsed. Incomplete is
+// set when the error is only that the input ended too early, so the REPL can
+// ask for another line instead of failing.
+type SyntaxError struct {
+	Pos        Pos
+	Msg        string
+	Incomplete bool
+}
+
+func (e *SyntaxError) Error() string { return fmt.Sprintf("%v: syntax error: %s", e.Pos, e.Msg) }
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokSemi
+	tokIdent
+	tokInt
+	tokString
+	tokKeyword
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string // Source text; the unquoted value for strings.
+	pos  Pos
+}
+
+func (t token) String() string {
+	//exhaustive:ignore Identifiers, integers, keywords and operators all print as quoted text.
+	switch t.kind {
+	case tokEOF:
+		return "end of input"
+	case tokSemi:
+		if t.text == "\n" {
+			return "newline"
+		}
+		return "';'"
+	case tokString:
+		return strconv.Quote(t.text)
+	}
+	return "'" + t.text + "'"
+}
+
+var keywords = map[string]bool{
+	"if": true, "else": true, "for": true, "range": true, "break": true,
+	"continue": true, "switch": true, "case": true, "default": true,
+	"fallthrough": true, "true": true, "false": true,
+}
+
+// operators is ordered longest first so "<=" wins over "<".
+var operators = []string{
+	":=", "+=", "-=", "*=", "/=", "%=", "++", "--", "==", "!=", "<=", ">=", "&&", "||",
+	"+", "-", "*", "/", "%", "<", ">", "=", "!", "(", ")", "{", "}", ",", ";", ":",
+}
+
+// lex splits src into tokens. As in Go, a newline becomes a semicolon when the
+// line's last token could end a statement, so most semicolons can be left out.
+func lex(src string) ([]token, error) {
+	var toks []token
+	line, col := 1, 1
+	i := 0
+	advance := func(n int) {
+		for k := 0; k < n; k++ {
+			if src[i] == '\n' {
+				line++
+				col = 1
+			} else {
+				col++
+			}
+			i++
+		}
+	}
+	needSemi := func() bool {
+		if len(toks) == 0 {
+			return false
+		}
+		last := toks[len(toks)-1]
+		switch last.kind {
+		case tokIdent, tokInt, tokString:
+			return true
+		case tokKeyword:
+			switch last.text {
+			case "break", "continue", "fallthrough", "true", "false":
+				return true
+			}
+		case tokOp:
+			switch last.text {
+			case "++", "--", ")", "}":
+				return true
+			}
+		case tokEOF, tokSemi:
+			// Never followed by an automatic semicolon.
+		}
+		return false
+	}
+
+	for i < len(src) {
+		c := src[i]
+		pos := Pos{line, col}
+		switch {
+		case c == '\n':
+			if needSemi() {
+				toks = append(toks, token{kind: tokSemi, text: "\n", pos: pos})
+			}
+			advance(1)
+		case c == ' ' || c == '\t' || c == '\r':
+			advance(1)
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				advance(1)
+			}
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '_') {
+				advance(1)
+			}
+			toks = append(toks, token{kind: tokInt, text: src[start:i], pos: pos})
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			start := i
+			for i < len(src) && (src[i] == '_' || src[i] >= 'a' && src[i] <= 'z' || src[i] >= 'A' && src[i] <= 'Z' || src[i] >= '0' && src[i] <= '9') {
+				advance(1)
+			}
+			word := src[start:i]
+			kind := tokIdent
+			if keywords[word] {
+				kind = tokKeyword
+			}
+			toks = append(toks, token{kind: kind, text: word, pos: pos})
+		case c == '"':
+			end := i + 1
+			for end < len(src) && src[end] != '"' && src[end] != '\n' {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(src) || src[end] != '"' {
+				return nil, &SyntaxError{Pos: pos, Msg: "string literal not terminated"}
+			}
+			s, err := strconv.Unquote(src[i : end+1])
+			if err != nil {
+				return nil, &SyntaxError{Pos: pos, Msg: "bad escape in string literal"}
+			}
+			toks = append(toks, token{kind: tokString, text: s, pos: pos})
+			advance(end + 1 - i)
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("unexpected character %q", c)}
+			}
+			kind := tokOp
+			if op == ";" {
+				kind = tokSemi
+			}
+			toks = append(toks, token{kind: kind, text: op, pos: pos})
+			advance(len(op))
+		}
+	}
+	if needSemi() {
+		toks = append(toks, token{kind: tokSemi, text: "\n", pos: Pos{line, col}})
+	}
+	return append(toks, token{kind: tokEOF, pos: Pos{line, col}}), nil
+}
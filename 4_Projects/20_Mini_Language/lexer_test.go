@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// tokenTexts renders toks compactly: inserted semicolons as NL, explicit
+// ones as ';', and the final EOF left out.
+func tokenTexts(toks []token) string {
+	var parts []string
+	for _, t := range toks {
+		switch {
+		case t.kind == tokEOF:
+		case t.kind == tokSemi && t.text == "\n":
+			parts = append(parts, "NL")
+		default:
+			parts = append(parts, t.text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestLexSemicolons(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{"x := 1\ny := 2\n", "x := 1 NL y := 2 NL"},
+		{"x := 1", "x := 1 NL"}, // The end of input ends a statement too.
+		{`print("a")` + "\n", `print ( a ) NL`},
+		{"x++\ny--\n", "x ++ NL y -- NL"},
+		{"break\ncontinue\nfallthrough\n", "break NL continue NL fallthrough NL"},
+		{"ok := true\nno := false\n", "ok := true NL no := false NL"},
+		{"if x {\n}\n", "if x { } NL"},
+
+		// No semicolon after an operator, an opening brace, a keyword
+		// that needs more, or a line with nothing on it.
+		{"x := 1 +\n2\n", "x := 1 + 2 NL"},
+		{"a &&\nb\n", "a && b NL"},
+		{"for\n", "for"},
+		{"else\n", "else"},
+		{"\n\n\n", ""},
+		{"x := 1\n\n\ny := 2", "x := 1 NL y := 2 NL"},
+
+		// Comments are skipped but their newline still counts.
+		{"x := 1 // one\ny := 2", "x := 1 NL y := 2 NL"},
+		{"// only a comment\n", ""},
+
+		// An explicit ';' is kept and suppresses a second one.
+		{"x := 1; y := 2\n", "x := 1 ; y := 2 NL"},
+		{"x := 1;\n", "x := 1 ;"},
+	}
+	for _, tt := range tests {
+		toks, err := lex(tt.src)
+		if err != nil {
+			t.Errorf("lex(%q): %v", tt.src, err)
+			continue
+		}
+		if got := tokenTexts(toks); got != tt.want {
+			t.Errorf("lex(%q) = %q, want %q", tt.src, got, tt.want)
+		}
+		if last := toks[len(toks)-1]; last.kind != tokEOF {
+			t.Errorf("lex(%q) ends with %v, want end of input", tt.src, last)
+		}
+	}
+}
+
+func TestLexPositions(t *testing.T) {
+	toks, err := lex("x := 1\n  print(\"é\", x)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Pos{"x": {1, 1}, ":=": {1, 3}, "print": {2, 3}, "é": {2, 9}}
+	for _, tok := range toks {
+		if p, ok := want[tok.text]; ok {
+			if tok.pos != p {
+				t.Errorf("%v at %v, want %v", tok, tok.pos, p)
+			}
+			delete(want, tok.text)
+		}
+	}
+	for text := range want {
+		t.Errorf("no token %q", text)
+	}
+}
+
+func TestLexErrors(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{`"open`, "1:1: syntax error: string literal not terminated"},
+		{"\"line\nbreak\"", "1:1: syntax error: string literal not terminated"},
+		{`x := "\q"`, "1:6: syntax error: bad escape in string literal"},
+		{"x := 1 @ 2", `1:8: syntax error: unexpected character '@'`},
+	}
+	for _, tt := range tests {
+		_, err := lex(tt.src)
+		var se *SyntaxError
+		if !errors.As(err, &se) || err.Error() != tt.want {
+			t.Errorf("lex(%q): err = %v, want %s", tt.src, err, tt.want)
+		}
+	}
+}
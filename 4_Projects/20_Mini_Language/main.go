@@ -0,0 +1,111 @@
+// Package main implements mini, a tiny interpreted language with exactly the
+// control flow taught in the Control Statements lesson, so its rules can be
+// tried out one line at a time:
+//
+//   - if / else if / else, with an optional init statement
+//   - the four for forms: three-clause, condition only, infinite, and range
+//     over an int or a string
+//   - break and continue
+//   - switch with or without a tag and an init statement, case lists,
+//     default and fallthrough
+//   - int, string and bool values with the operators
+//     := = += -= *= /= %= ++ -- and + - * / % == != < <= > >= && || !
+//   - print(...) and len(s)
+//
+// The syntax is Go's and so are the semantics: no implicit fallthrough,
+// break inside a switch leaves the switch, variables keep their type, and
+// misplaced break, continue or fallthrough are rejected before the program
+// runs. The differences are that the checks Go does at compile time (types,
+// undefined names) happen at run time, unused variables are allowed, and the
+// value in "for i, ch := range s" is a one-character string. A program that
+// runs too long (-max-steps) or builds a string over 1 MiB stops with a
+// runtime error.
+//
+//	go run main.go ast.go interp.go lexer.go parser.go repl.go  # run examples/control.mini, the lesson ported
+//	go run main.go ast.go interp.go lexer.go parser.go repl.go prog.mini
+//	go run main.go ast.go interp.go lexer.go parser.go repl.go -e 'for i := range 3 { print(i) }'
+//	go run main.go ast.go interp.go lexer.go parser.go repl.go -repl
+//	go test *.go                                                # lexer, parser and interpreter
+//
+// Exit codes: 0 on success, 1 on syntax or runtime errors, 2 on invalid usage.
+package main
+
+import (
+	_ "embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// example is run when no program is given.
+//
+//go:embed examples/control.mini
+var example string
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("mini", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	code := flags.String("e", "", "run this program text instead of a file")
+	repl := flags.Bool("repl", false, "start an interactive session")
+	maxSteps := flags.Int("max-steps", DefaultMaxSteps, "give up after this many statements and loop iterations")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if *maxSteps <= 0 {
+		fmt.Fprintln(stderr, "error: -max-steps must be positive")
+		return 2
+	}
+
+	if *repl {
+		if *code != "" || flags.NArg() > 0 {
+			fmt.Fprintln(stderr, "error: -repl takes no program")
+			return 2
+		}
+		if err := REPL(stdin, stdout, *maxSteps); err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+		return 0
+	}
+
+	src := example
+	switch {
+	case *code != "" && flags.NArg() > 0:
+		fmt.Fprintln(stderr, "error: give either -e or a file, not both")
+		return 2
+	case *code != "":
+		src = *code
+	case flags.NArg() == 1:
+		data, err := os.ReadFile(flags.Arg(0))
+		if err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+		src = string(data)
+	case flags.NArg() > 1:
+		fmt.Fprintln(stderr, "error: one program file at a time")
+		return 2
+	}
+
+	stmts, err := Parse(src, false)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	interp := NewInterpreter(stdout)
+	interp.MaxSteps = *maxSteps
+	if err := interp.Run(stmts, nil); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,531 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// builtins are the only functions a program can call, with their arities
+// (-1 for any number of arguments).
+var builtins = map[string]int{"print": -1, "len": 1}
+
+// Parse parses a whole program. In REPL mode a statement may be any
+// expression, whose value is then shown; otherwise, as in Go, only calls may
+// stand alone.
+func Parse(src string, repl bool) ([]Stmt, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, repl: repl}
+	stmts, err := p.stmtList()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.unexpected(t, "statement")
+	}
+	if err := checkBranches(stmts, false, false); err != nil {
+		return nil, err
+	}
+	return stmts, nil
+}
+
+type parser struct {
+	toks []token
+	i    int
+	repl bool
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) is(kind tokenKind, text string) bool {
+	t := p.peek()
+	return t.kind == kind && t.text == text
+}
+
+func (p *parser) isOp(text string) bool { return p.is(tokOp, text) }
+
+func (p *parser) unexpected(t token, want string) error {
+	return &SyntaxError{
+		Pos:        t.pos,
+		Msg:        fmt.Sprintf("unexpected %v, expected %s", t, want),
+		Incomplete: t.kind == tokEOF,
+	}
+}
+
+func (p *parser) expectOp(text string) (token, error) {
+	if !p.isOp(text) {
+		return token{}, p.unexpected(p.peek(), "'"+text+"'")
+	}
+	return p.next(), nil
+}
+
+// stmtList parses statements up to a '}', case, default or the end of input.
+func (p *parser) stmtList() ([]Stmt, error) {
+	var stmts []Stmt
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokSemi:
+			p.next()
+			continue
+		case t.kind == tokEOF, p.isOp("}"), p.is(tokKeyword, "case"), p.is(tokKeyword, "default"):
+			return stmts, nil
+		}
+		s, err := p.stmt()
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, s)
+		// Statements are separated by semicolons (usually inserted newlines)
+		// unless the list ends here.
+		if t := p.peek(); t.kind != tokSemi && t.kind != tokEOF && !p.isOp("}") && !p.is(tokKeyword, "case") && !p.is(tokKeyword, "default") {
+			return nil, p.unexpected(t, "newline or ';' after statement")
+		}
+	}
+}
+
+func (p *parser) stmt() (Stmt, error) {
+	t := p.peek()
+	if t.kind == tokKeyword {
+		switch t.text {
+		case "if":
+			return p.ifStmt()
+		case "for":
+			return p.forStmt()
+		case "switch":
+			return p.switchStmt()
+		case "break", "continue", "fallthrough":
+			p.next()
+			return &Branch{At: t.pos, Keyword: t.text}, nil
+		}
+	}
+	if p.isOp("{") {
+		return p.block()
+	}
+	s, err := p.simpleStmt()
+	if err != nil {
+		return nil, err
+	}
+	if es, ok := s.(*ExprStmt); ok && !p.repl {
+		if call, ok := es.X.(*Call); !ok || call.Func != "print" {
+			return nil, &SyntaxError{Pos: es.X.exprPos(), Msg: "expression is evaluated but not used"}
+		}
+	}
+	return s, nil
+}
+
+// simpleStmt parses an expression, definition, assignment or ++/--.
+func (p *parser) simpleStmt() (Stmt, error) {
+	t := p.peek()
+	if t.kind == tokIdent && p.toks[p.i+1].kind == tokOp {
+		switch op := p.toks[p.i+1].text; op {
+		case ":=", "=", "+=", "-=", "*=", "/=", "%=":
+			p.i += 2
+			value, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if op == ":=" {
+				return &Define{At: t.pos, Name: t.text, Value: value}, nil
+			}
+			if op != "=" {
+				op = op[:1]
+			}
+			return &Assign{At: t.pos, Name: t.text, Op: op, Value: value}, nil
+		case "++", "--":
+			p.i += 2
+			return &IncDec{At: t.pos, Name: t.text, Op: op}, nil
+		}
+	}
+	x, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	return &ExprStmt{X: x}, nil
+}
+
+func (p *parser) block() (*Block, error) {
+	open, err := p.expectOp("{")
+	if err != nil {
+		return nil, err
+	}
+	stmts, err := p.stmtList()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expectOp("}"); err != nil {
+		return nil, err
+	}
+	return &Block{At: open.pos, Stmts: stmts}, nil
+}
+
+// header parses the "[init;] expr" part of an if or switch. expr is nil when
+// the header is just "init;", as in "switch x := 1; {".
+func (p *parser) header() (init Stmt, x Expr, err error) {
+	if p.isOp("{") {
+		return nil, nil, nil
+	}
+	var s Stmt
+	if p.peek().kind != tokSemi {
+		if s, err = p.simpleStmt(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if p.peek().kind == tokSemi {
+		p.next()
+		init = s
+		if p.isOp("{") {
+			return init, nil, nil
+		}
+		if x, err = p.expr(); err != nil {
+			return nil, nil, err
+		}
+		return init, x, nil
+	}
+	es, ok := s.(*ExprStmt)
+	if !ok {
+		return nil, nil, &SyntaxError{Pos: s.stmtPos(), Msg: "expected an expression, not a statement, before '{'"}
+	}
+	return nil, es.X, nil
+}
+
+func (p *parser) ifStmt() (Stmt, error) {
+	at := p.next().pos
+	init, cond, err := p.header()
+	if err != nil {
+		return nil, err
+	}
+	if cond == nil {
+		return nil, &SyntaxError{Pos: at, Msg: "missing condition in if statement"}
+	}
+	then, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	s := &If{At: at, Init: init, Cond: cond, Then: then}
+	if !p.is(tokKeyword, "else") {
+		return s, nil
+	}
+	p.next()
+	switch {
+	case p.is(tokKeyword, "if"):
+		s.Else, err = p.ifStmt()
+	case p.isOp("{"):
+		s.Else, err = p.block()
+	default:
+		err = p.unexpected(p.peek(), "if or '{' after else")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (p *parser) forStmt() (Stmt, error) {
+	at := p.next().pos
+
+	// The range forms: "for range x", "for k := range x", "for k, v := range x".
+	if p.is(tokKeyword, "range") {
+		return p.rangeClause(at, "", "")
+	}
+	if p.peek().kind == tokIdent {
+		j := p.i + 1
+		value := ""
+		if p.toks[j].kind == tokOp && p.toks[j].text == "," && p.toks[j+1].kind == tokIdent {
+			value = p.toks[j+1].text
+			j += 2
+		}
+		if p.toks[j].kind == tokOp && p.toks[j].text == ":=" && p.toks[j+1].kind == tokKeyword && p.toks[j+1].text == "range" {
+			key := p.peek().text
+			p.i = j + 1
+			return p.rangeClause(at, key, value)
+		}
+	}
+
+	s := &For{At: at}
+	if p.isOp("{") {
+		return p.forBody(s)
+	}
+	var first Stmt
+	if p.peek().kind != tokSemi {
+		var err error
+		if first, err = p.simpleStmt(); err != nil {
+			return nil, err
+		}
+	}
+	if p.isOp("{") {
+		es, ok := first.(*ExprStmt)
+		if !ok {
+			return nil, &SyntaxError{Pos: first.stmtPos(), Msg: "expected for loop condition"}
+		}
+		s.Cond = es.X
+		return p.forBody(s)
+	}
+	s.Init = first
+	if p.peek().kind != tokSemi {
+		return nil, p.unexpected(p.peek(), "';' or '{' in for clause")
+	}
+	p.next()
+	if p.peek().kind != tokSemi {
+		cond, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		s.Cond = cond
+	}
+	if p.peek().kind != tokSemi {
+		return nil, p.unexpected(p.peek(), "';' after for loop condition")
+	}
+	p.next()
+	if !p.isOp("{") {
+		post, err := p.simpleStmt()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := post.(*Define); ok {
+			return nil, &SyntaxError{Pos: post.stmtPos(), Msg: "cannot declare in post statement of for loop"}
+		}
+		s.Post = post
+	}
+	return p.forBody(s)
+}
+
+func (p *parser) forBody(s *For) (Stmt, error) {
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	s.Body = body
+	return s, nil
+}
+
+func (p *parser) rangeClause(at Pos, key, value string) (Stmt, error) {
+	p.next() // range
+	x, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	return &ForRange{At: at, Key: key, Value: value, X: x, Body: body}, nil
+}
+
+func (p *parser) switchStmt() (Stmt, error) {
+	at := p.next().pos
+	init, tag, err := p.header()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expectOp("{"); err != nil {
+		return nil, err
+	}
+	s := &Switch{At: at, Init: init, Tag: tag}
+	hasDefault := false
+	for !p.isOp("}") {
+		t := p.peek()
+		c := &Case{At: t.pos}
+		switch {
+		case p.is(tokKeyword, "case"):
+			p.next()
+			for {
+				x, err := p.expr()
+				if err != nil {
+					return nil, err
+				}
+				c.Exprs = append(c.Exprs, x)
+				if !p.isOp(",") {
+					break
+				}
+				p.next()
+			}
+		case p.is(tokKeyword, "default"):
+			p.next()
+			if hasDefault {
+				return nil, &SyntaxError{Pos: t.pos, Msg: "multiple defaults in switch"}
+			}
+			hasDefault = true
+		case t.kind == tokSemi:
+			p.next()
+			continue
+		default:
+			return nil, p.unexpected(t, "case or default")
+		}
+		if _, err := p.expectOp(":"); err != nil {
+			return nil, err
+		}
+		if c.Body, err = p.stmtList(); err != nil {
+			return nil, err
+		}
+		s.Cases = append(s.Cases, c)
+	}
+	p.next()
+	return s, nil
+}
+
+// Binary operator precedence, as in Go.
+var precedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3, "<": 3, "<=": 3, ">": 3, ">=": 3,
+	"+": 4, "-": 4,
+	"*": 5, "/": 5, "%": 5,
+}
+
+func (p *parser) expr() (Expr, error) { return p.binary(1) }
+
+func (p *parser) binary(minPrec int) (Expr, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		prec, ok := precedence[t.text]
+		if t.kind != tokOp || !ok || prec < minPrec {
+			return x, nil
+		}
+		p.next()
+		y, err := p.binary(prec + 1)
+		if err != nil {
+			return nil, err
+		}
+		x = &Binary{At: t.pos, Op: t.text, X: x, Y: y}
+	}
+}
+
+func (p *parser) unary() (Expr, error) {
+	if t := p.peek(); t.kind == tokOp && (t.text == "-" || t.text == "!" || t.text == "+") {
+		p.next()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{At: t.pos, Op: t.text, X: x}, nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokInt:
+		n, err := strconv.ParseInt(t.text, 0, 64)
+		if err != nil {
+			return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("integer %s out of range", t.text)}
+		}
+		return &IntLit{At: t.pos, Value: n}, nil
+	case tokString:
+		return &StringLit{At: t.pos, Value: t.text}, nil
+	case tokKeyword:
+		if t.text == "true" || t.text == "false" {
+			return &BoolLit{At: t.pos, Value: t.text == "true"}, nil
+		}
+	case tokIdent:
+		if !p.isOp("(") {
+			return &Ident{At: t.pos, Name: t.text}, nil
+		}
+		return p.call(t)
+	case tokOp:
+		if t.text == "(" {
+			x, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expectOp(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		}
+	case tokEOF, tokSemi:
+		// Reported below as unexpected.
+	}
+	return nil, p.unexpected(t, "expression")
+}
+
+func (p *parser) call(name token) (Expr, error) {
+	arity, ok := builtins[name.text]
+	if !ok {
+		return nil, &SyntaxError{Pos: name.pos, Msg: fmt.Sprintf("unknown function %s (only print and len exist)", name.text)}
+	}
+	p.next() // (
+	c := &Call{At: name.pos, Func: name.text}
+	for !p.isOp(")") {
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		c.Args = append(c.Args, x)
+		if !p.isOp(",") {
+			break
+		}
+		p.next()
+	}
+	if _, err := p.expectOp(")"); err != nil {
+		return nil, err
+	}
+	if arity >= 0 && len(c.Args) != arity {
+		return nil, &SyntaxError{Pos: name.pos, Msg: fmt.Sprintf("%s takes %d argument(s), got %d", name.text, arity, len(c.Args))}
+	}
+	return c, nil
+}
+
+// checkBranches applies Go's placement rules: break only inside a loop or
+// switch, continue only inside a loop, and fallthrough only as the last
+// statement of a case that is not the switch's last.
+func checkBranches(stmts []Stmt, inLoop, inSwitch bool) error {
+	for _, s := range stmts {
+		var err error
+		switch s := s.(type) {
+		case *Branch:
+			switch {
+			case s.Keyword == "break" && !inLoop && !inSwitch:
+				err = &SyntaxError{Pos: s.At, Msg: "break is not in a loop or switch"}
+			case s.Keyword == "continue" && !inLoop:
+				err = &SyntaxError{Pos: s.At, Msg: "continue is not in a loop"}
+			case s.Keyword == "fallthrough":
+				err = &SyntaxError{Pos: s.At, Msg: "fallthrough statement out of place"}
+			}
+		case *Block:
+			err = checkBranches(s.Stmts, inLoop, inSwitch)
+		case *If:
+			if err = checkBranches(s.Then.Stmts, inLoop, inSwitch); err == nil && s.Else != nil {
+				err = checkBranches([]Stmt{s.Else}, inLoop, inSwitch)
+			}
+		case *For:
+			err = checkBranches(s.Body.Stmts, true, inSwitch)
+		case *ForRange:
+			err = checkBranches(s.Body.Stmts, true, inSwitch)
+		case *Switch:
+			for i, c := range s.Cases {
+				body := c.Body
+				if n := len(body); n > 0 {
+					if b, ok := body[n-1].(*Branch); ok && b.Keyword == "fallthrough" {
+						if i == len(s.Cases)-1 {
+							return &SyntaxError{Pos: b.At, Msg: "cannot fallthrough final case in switch"}
+						}
+						body = body[:n-1]
+					}
+				}
+				if err = checkBranches(body, inLoop, true); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
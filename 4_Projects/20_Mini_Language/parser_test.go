@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckBranches(t *testing.T) {
+	tests := []struct {
+		name, src string
+		want      string // Empty when the program must parse.
+	}{
+		{"break in for", "for { break }", ""},
+		{"continue in for", "for i := range 3 { continue }", ""},
+		{"break in switch", "switch { default: break }", ""},
+		{"break in if in for", "for { if true { break } }", ""},
+		{"break in block in for", "for { { break } }", ""},
+		{"continue in switch in for", "for i := range 3 { switch { case i > 1: continue } }", ""},
+		{"fallthrough to next case", "switch 1 { case 1: fallthrough\ncase 2: }", ""},
+		{"fallthrough into default", "switch 1 { case 1: fallthrough\ndefault: }", ""},
+
+		{"break at top level", "break", "1:1: syntax error: break is not in a loop or switch"},
+		{"break in if", "if true { break }", "1:11: syntax error: break is not in a loop or switch"},
+		{"continue at top level", "continue", "1:1: syntax error: continue is not in a loop"},
+		{"continue in switch", "switch { default: continue }", "1:19: syntax error: continue is not in a loop"},
+		{"continue in else", "x := 1\nif x > 1 {} else { continue }", "2:20: syntax error: continue is not in a loop"},
+		{"fallthrough at top level", "fallthrough", "1:1: syntax error: fallthrough statement out of place"},
+		{"fallthrough in for", "for { fallthrough }", "1:7: syntax error: fallthrough statement out of place"},
+		{"fallthrough in final case", "switch 1 { case 1: fallthrough }", "1:20: syntax error: cannot fallthrough final case in switch"},
+		{"fallthrough not last", "switch 1 { case 1: fallthrough\nprint(1)\ncase 2: }", "1:20: syntax error: fallthrough statement out of place"},
+		{"fallthrough in nested block", "switch 1 { case 1: { fallthrough }\ncase 2: }", "1:22: syntax error: fallthrough statement out of place"},
+		{"fallthrough in if in case", "switch 1 { case 1: if true { fallthrough }\ncase 2: }", "1:30: syntax error: fallthrough statement out of place"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.src, false)
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("Parse: %v", err)
+			case tt.want != "" && (err == nil || err.Error() != tt.want):
+				t.Errorf("Parse: err = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src, want  string
+		incomplete bool
+	}{
+		{"x := 1 +", "1:9: syntax error: unexpected end of input, expected expression", true},
+		{"if x {", "1:7: syntax error: unexpected end of input, expected '}'", true},
+		{"x := 1 2", "1:8: syntax error: unexpected '2', expected newline or ';' after statement", false},
+		{"1 + 2", "1:3: syntax error: expression is evaluated but not used", false},
+		{"if x := 1; {}", "1:1: syntax error: missing condition in if statement", false},
+		{"for i := 0; i < 3; j := 1 {}", "1:20: syntax error: cannot declare in post statement of for loop", false},
+		{"switch { default:\ndefault: }", "2:1: syntax error: multiple defaults in switch", false},
+		{"x := 9223372036854775808", "1:6: syntax error: integer 9223372036854775808 out of range", false},
+		{"f(1)", "1:1: syntax error: unknown function f (only print and len exist)", false},
+		{`x := len("a", "b")`, "1:6: syntax error: len takes 1 argument(s), got 2", false},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.src, false)
+		var se *SyntaxError
+		if !errors.As(err, &se) || err.Error() != tt.want {
+			t.Errorf("Parse(%q): err = %v, want %s", tt.src, err, tt.want)
+			continue
+		}
+		if se.Incomplete != tt.incomplete {
+			t.Errorf("Parse(%q): Incomplete = %v, want %v", tt.src, se.Incomplete, tt.incomplete)
+		}
+	}
+}
+
+func TestParseREPLExpression(t *testing.T) {
+	if _, err := Parse("1 + 2", true); err != nil {
+		t.Errorf("REPL mode: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const replHelp = `Type statements as in Go; an expression on its own shows its value.
+Unfinished input (an open { or a trailing operator) continues on the next line.
+  :vars   list variables
+  :reset  forget all variables
+  :help   show this help
+  :quit   leave (or press Ctrl+D)`
+
+// REPL reads input from in a statement at a time and runs it. Variables
+// persist between inputs; an error is reported and the session goes on.
+func REPL(in io.Reader, out io.Writer, maxSteps int) error {
+	interp := NewInterpreter(out)
+	interp.MaxSteps = maxSteps
+	sc := bufio.NewScanner(in)
+	fmt.Fprintln(out, "mini REPL; :help for help")
+
+	var pending []string
+	for {
+		if len(pending) == 0 {
+			fmt.Fprint(out, "mini> ")
+		} else {
+			fmt.Fprint(out, "....> ")
+		}
+		if !sc.Scan() {
+			fmt.Fprintln(out)
+			return sc.Err()
+		}
+		line := sc.Text()
+
+		if len(pending) == 0 {
+			switch strings.TrimSpace(line) {
+			case "":
+				continue
+			case ":quit", ":q":
+				return nil
+			case ":help":
+				fmt.Fprintln(out, replHelp)
+				continue
+			case ":vars":
+				for _, v := range interp.Globals() {
+					fmt.Fprintln(out, v)
+				}
+				continue
+			case ":reset":
+				interp = NewInterpreter(out)
+				interp.MaxSteps = maxSteps
+				continue
+			}
+		}
+
+		pending = append(pending, line)
+		stmts, err := Parse(strings.Join(pending, "\n"), true)
+		var syntaxErr *SyntaxError
+		if errors.As(err, &syntaxErr) && syntaxErr.Incomplete && strings.TrimSpace(line) != "" {
+			continue // Wait for the rest; an empty line gives up.
+		}
+		pending = nil
+		if err != nil {
+			fmt.Fprintln(out, err)
+			continue
+		}
+		err = interp.Run(stmts, func(v any) {
+			fmt.Fprintln(out, quoteValue(v))
+		})
+		if err != nil {
+			fmt.Fprintln(out, err)
+		}
+	}
+}
@@ -0,0 +1,432 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+// Graph is the control-flow graph of one function body.
+type Graph struct {
+	Blocks []*Block // Blocks[0] is the entry; the last block is the exit.
+}
+
+// Entry returns the block where the function starts.
+func (g *Graph) Entry() *Block { return g.Blocks[0] }
+
+// Exit returns the block every return and panic leads to.
+func (g *Graph) Exit() *Block { return g.Blocks[len(g.Blocks)-1] }
+
+// Block is a basic block: statements that always run one after another,
+// entered only at the top and left only at the bottom.
+type Block struct {
+	Index int
+	Kind  string // What created the block, e.g. "for.body" or "switch.case".
+	Lines []Line
+	Succs []Edge
+	Preds int
+}
+
+// Reachable reports whether control can get to the block: it is the entry
+// or some edge leads to it. Code after a return or an endless loop is not.
+func (b *Block) Reachable() bool { return b.Index == 0 || b.Preds > 0 }
+
+// Line is one statement or condition in a block.
+type Line struct {
+	Line int    // Source line.
+	Text string // The source, shortened to its first line.
+}
+
+// Edge leaves a block. Label says when it is taken: "true", "false",
+// "case 4, 5", "default", "break", "continue", "fallthrough", "return" and
+// so on; it is empty for plain fall-through to the next statement.
+type Edge struct {
+	To    *Block
+	Label string
+}
+
+// Build computes the control-flow graph of body. src is the file's content,
+// used to show statements as written.
+func Build(fset *token.FileSet, src []byte, body *ast.BlockStmt) *Graph {
+	b := &builder{fset: fset, src: src, g: &Graph{}, labels: map[string]*Block{}}
+	exit := &Block{Kind: "exit"}
+	b.exit = exit
+	b.cur = b.newBlock("entry")
+	b.stmtList(body.List)
+	b.jump(exit, "")
+	b.g.Blocks = append(b.g.Blocks, exit)
+	for i, blk := range b.g.Blocks {
+		blk.Index = i
+	}
+	return b.g
+}
+
+type builder struct {
+	fset    *token.FileSet
+	src     []byte
+	g       *Graph
+	cur     *Block // Nil after a jump, until a statement needs a block.
+	exit    *Block
+	targets *targets
+	labels  map[string]*Block // Created on first use, so goto can jump forward.
+	label   string            // Label of the statement being built, consumed by loops and switches.
+}
+
+// targets is a stack of where break, continue and fallthrough go.
+type targets struct {
+	outer     *targets
+	label     string
+	brk, cont *Block
+	fall      *Block // The next case's body, for fallthrough.
+}
+
+func (b *builder) newBlock(kind string) *Block {
+	blk := &Block{Kind: kind}
+	b.g.Blocks = append(b.g.Blocks, blk)
+	return blk
+}
+
+// block returns the current block, starting an unreachable one after a jump.
+func (b *builder) block() *Block {
+	if b.cur == nil {
+		b.cur = b.newBlock("unreachable")
+	}
+	return b.cur
+}
+
+// add appends a node's source, after prefix, to the current block.
+func (b *builder) add(n ast.Node, prefix string) {
+	b.addLine(n.Pos(), prefix+b.text(n))
+}
+
+func (b *builder) addLine(pos token.Pos, text string) {
+	blk := b.block()
+	blk.Lines = append(blk.Lines, Line{Line: b.fset.Position(pos).Line, Text: text})
+}
+
+// text returns a node's source, cut at the first newline.
+func (b *builder) text(n ast.Node) string {
+	start, end := b.fset.Position(n.Pos()).Offset, b.fset.Position(n.End()).Offset
+	s := string(b.src[start:end])
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = strings.TrimRight(s[:i], " {") + " …"
+	}
+	return s
+}
+
+// edge adds an edge from the current block without ending it.
+func (b *builder) edge(to *Block, label string) {
+	from := b.block()
+	from.Succs = append(from.Succs, Edge{To: to, Label: label})
+	to.Preds++
+}
+
+// jump ends the current block with an edge to to. Nothing is added when
+// the current point is already unreachable.
+func (b *builder) jump(to *Block, label string) {
+	if b.cur != nil {
+		b.edge(to, label)
+	}
+	b.cur = nil
+}
+
+// takeLabel returns and clears the label of the statement being built.
+func (b *builder) takeLabel() string {
+	l := b.label
+	b.label = ""
+	return l
+}
+
+func (b *builder) push(t *targets) { t.outer, b.targets = b.targets, t }
+func (b *builder) pop()            { b.targets = b.targets.outer }
+
+func (b *builder) labelBlock(name string) *Block {
+	blk := b.labels[name]
+	if blk == nil {
+		blk = b.newBlock("label " + name)
+		b.labels[name] = blk
+	}
+	return blk
+}
+
+func (b *builder) stmtList(list []ast.Stmt) {
+	for _, s := range list {
+		b.stmt(s)
+	}
+}
+
+func (b *builder) stmt(s ast.Stmt) {
+	switch s := s.(type) {
+	case *ast.BlockStmt:
+		b.stmtList(s.List)
+	case *ast.LabeledStmt:
+		target := b.labelBlock(s.Label.Name)
+		b.jump(target, "")
+		b.cur = target
+		switch s.Stmt.(type) {
+		case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+			b.label = s.Label.Name // For "break L" and "continue L".
+		}
+		b.stmt(s.Stmt)
+	case *ast.IfStmt:
+		b.ifStmt(s)
+	case *ast.ForStmt:
+		b.forStmt(s)
+	case *ast.RangeStmt:
+		b.rangeStmt(s)
+	case *ast.SwitchStmt:
+		if s.Init != nil {
+			b.stmt(s.Init)
+		}
+		if s.Tag != nil {
+			b.add(s.Tag, "switch ")
+		} else {
+			b.addLine(s.Switch, "switch {")
+		}
+		b.clauses(s.Body, "switch", func(c ast.Stmt) (string, []ast.Stmt) {
+			cc := c.(*ast.CaseClause)
+			if cc.List == nil {
+				return "default", cc.Body
+			}
+			return "case " + b.exprList(cc.List), cc.Body
+		})
+	case *ast.TypeSwitchStmt:
+		if s.Init != nil {
+			b.stmt(s.Init)
+		}
+		b.add(s.Assign, "switch ")
+		b.clauses(s.Body, "typeswitch", func(c ast.Stmt) (string, []ast.Stmt) {
+			cc := c.(*ast.CaseClause)
+			if cc.List == nil {
+				return "default", cc.Body
+			}
+			return "case " + b.exprList(cc.List), cc.Body
+		})
+	case *ast.SelectStmt:
+		b.addLine(s.Select, "select {")
+		b.clauses(s.Body, "select", func(c ast.Stmt) (string, []ast.Stmt) {
+			cc := c.(*ast.CommClause)
+			if cc.Comm == nil {
+				return "default", cc.Body
+			}
+			return "case " + b.text(cc.Comm), cc.Body
+		})
+	case *ast.BranchStmt:
+		b.branchStmt(s)
+	case *ast.ReturnStmt:
+		b.add(s, "")
+		b.jump(b.exit, "return")
+	case *ast.ExprStmt:
+		b.add(s, "")
+		if kind := noReturnCall(s.X); kind != "" {
+			b.jump(b.exit, kind)
+		}
+	default:
+		// Assignments, declarations, go, defer, send, ++/-- and empty
+		// statements do not change the flow.
+		if _, empty := s.(*ast.EmptyStmt); !empty {
+			b.add(s, "")
+		}
+	}
+}
+
+// noReturnCall recognizes calls that never return: panic and os.Exit.
+func noReturnCall(x ast.Expr) string {
+	call, ok := x.(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		if fn.Name == "panic" {
+			return "panic"
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := fn.X.(*ast.Ident); ok && pkg.Name == "os" && fn.Sel.Name == "Exit" {
+			return "os.Exit"
+		}
+	}
+	return ""
+}
+
+func (b *builder) exprList(list []ast.Expr) string {
+	parts := make([]string, len(list))
+	for i, x := range list {
+		parts[i] = b.text(x)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (b *builder) ifStmt(s *ast.IfStmt) {
+	if s.Init != nil {
+		b.stmt(s.Init)
+	}
+	b.add(s.Cond, "if ")
+	then := b.newBlock("if.then")
+	var els *Block
+	if s.Else != nil {
+		els = b.newBlock("if.else")
+	}
+	done := b.newBlock("if.done")
+	if els == nil {
+		els = done
+	}
+	b.edge(then, "true")
+	b.jump(els, "false")
+
+	b.cur = then
+	b.stmtList(s.Body.List)
+	b.jump(done, "")
+	if s.Else != nil {
+		b.cur = els
+		b.stmt(s.Else)
+		b.jump(done, "")
+	}
+	b.cur = done
+}
+
+func (b *builder) forStmt(s *ast.ForStmt) {
+	label := b.takeLabel()
+	if s.Init != nil {
+		b.stmt(s.Init)
+	}
+	loop := b.newBlock("for.loop")
+	body := b.newBlock("for.body")
+	cont := loop
+	var post *Block
+	if s.Post != nil {
+		post = b.newBlock("for.post")
+		cont = post
+	}
+	done := b.newBlock("for.done")
+
+	b.jump(loop, "")
+	b.cur = loop
+	if s.Cond != nil {
+		b.add(s.Cond, "for ")
+		b.edge(body, "true")
+		b.jump(done, "false")
+	} else {
+		b.addLine(s.For, "for {")
+		b.jump(body, "")
+	}
+
+	b.cur = body
+	b.push(&targets{label: label, brk: done, cont: cont})
+	b.stmtList(s.Body.List)
+	b.pop()
+	if post != nil {
+		b.jump(post, "")
+		b.cur = post
+		b.stmt(s.Post)
+	}
+	b.jump(loop, "loop")
+	b.cur = done
+}
+
+func (b *builder) rangeStmt(s *ast.RangeStmt) {
+	label := b.takeLabel()
+	loop := b.newBlock("range.loop")
+	body := b.newBlock("range.body")
+	done := b.newBlock("range.done")
+
+	b.jump(loop, "")
+	b.cur = loop
+	head := "for range " + b.text(s.X)
+	if s.Key != nil {
+		head = "for " + b.text(s.Key)
+		if s.Value != nil {
+			head += ", " + b.text(s.Value)
+		}
+		head += " " + s.Tok.String() + " range " + b.text(s.X)
+	}
+	b.addLine(s.For, head)
+	b.edge(body, "next")
+	b.jump(done, "done")
+
+	b.cur = body
+	b.push(&targets{label: label, brk: done, cont: loop})
+	b.stmtList(s.Body.List)
+	b.pop()
+	b.jump(loop, "loop")
+	b.cur = done
+}
+
+// clauses builds the cases of a switch, type switch or select. The head
+// block, already holding the tag, gets one edge per clause; without a
+// default clause, a switch also gets a "no match" edge to the end.
+func (b *builder) clauses(body *ast.BlockStmt, kind string, clause func(ast.Stmt) (label string, stmts []ast.Stmt)) {
+	label := b.takeLabel()
+	head := b.block()
+	bodies := make([]*Block, len(body.List))
+	hasDefault := false
+	for i, c := range body.List {
+		l, _ := clause(c)
+		if l == "default" {
+			hasDefault = true
+			bodies[i] = b.newBlock(kind + ".default")
+		} else {
+			bodies[i] = b.newBlock(kind + ".case")
+		}
+	}
+	done := b.newBlock(kind + ".done")
+
+	for i, c := range body.List {
+		l, _ := clause(c)
+		b.cur = head
+		b.edge(bodies[i], l)
+	}
+	b.cur = head
+	if !hasDefault && kind != "select" {
+		b.jump(done, "no match")
+	}
+	b.cur = nil
+
+	for i, c := range body.List {
+		_, stmts := clause(c)
+		var next *Block
+		if i+1 < len(bodies) {
+			next = bodies[i+1]
+		}
+		b.cur = bodies[i]
+		b.push(&targets{label: label, brk: done, fall: next})
+		b.stmtList(stmts)
+		b.pop()
+		b.jump(done, "")
+	}
+	b.cur = done
+}
+
+func (b *builder) branchStmt(s *ast.BranchStmt) {
+	b.add(s, "")
+	var to *Block
+	switch s.Tok {
+	case token.BREAK, token.CONTINUE:
+		for t := b.targets; t != nil; t = t.outer {
+			if s.Label != nil && t.label != s.Label.Name {
+				continue
+			}
+			if s.Tok == token.BREAK && t.brk != nil {
+				to = t.brk
+				break
+			}
+			if s.Tok == token.CONTINUE && t.cont != nil {
+				to = t.cont
+				break
+			}
+		}
+	case token.FALLTHROUGH:
+		if b.targets != nil {
+			to = b.targets.fall
+		}
+	case token.GOTO:
+		to = b.labelBlock(s.Label.Name)
+	}
+	if to == nil {
+		// The compiler rejects this program; keep the graph going anyway.
+		b.jump(b.exit, fmt.Sprintf("invalid %s", s.Tok))
+		return
+	}
+	b.jump(to, s.Tok.String())
+}
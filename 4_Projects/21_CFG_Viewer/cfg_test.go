@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the .golden files in testdata")
+
+// parseFlow parses testdata/flow.go, which has one function per construct.
+func parseFlow(t *testing.T) (*token.FileSet, []byte, *ast.File) {
+	t.Helper()
+	const file = "testdata/flow.go"
+	src, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fset, src, f
+}
+
+// TestGolden draws every function in testdata/flow.go as text and compares
+// it with testdata/<function>.golden. Run "go test *.go -update" after a
+// deliberate change to the output, and review the diff.
+func TestGolden(t *testing.T) {
+	fset, src, f := parseFlow(t)
+	fns := funcs(f)
+	if len(fns) == 0 {
+		t.Fatal("no functions in testdata/flow.go")
+	}
+	for _, fn := range fns {
+		name := funcKey(fn)
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Build(fset, src, fn.Body).WriteText(&buf); err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join("testdata", name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != string(want) {
+				t.Errorf("%s changed; got\n%s\nwant\n%s", golden, got, want)
+			}
+		})
+	}
+}
+
+// TestUnreachable checks the blocks the golden files mark unreachable.
+func TestUnreachable(t *testing.T) {
+	fset, src, f := parseFlow(t)
+	for _, fn := range funcs(f) {
+		var dead []int
+		for _, blk := range Build(fset, src, fn.Body).Blocks {
+			if !blk.Reachable() {
+				dead = append(dead, blk.Index)
+			}
+		}
+		want := 0
+		if fn.Name.Name == "AfterReturn" {
+			want = 1
+		}
+		if len(dead) != want {
+			t.Errorf("%s: unreachable blocks %v, want %d", fn.Name.Name, dead, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// edgeStyles makes the edges a learner asks about stand out.
+var edgeStyles = map[string]string{
+	"true":        `color="darkgreen", fontcolor="darkgreen"`,
+	"false":       `color="red3", fontcolor="red3"`,
+	"break":       `color="blue", fontcolor="blue", penwidth=2`,
+	"continue":    `color="darkorange", fontcolor="darkorange", penwidth=2`,
+	"fallthrough": `color="purple", fontcolor="purple", penwidth=2`,
+	"goto":        `color="brown", fontcolor="brown", penwidth=2`,
+	"return":      `color="gray40", fontcolor="gray40"`,
+	"panic":       `color="gray40", fontcolor="gray40", style=dashed`,
+	"os.Exit":     `color="gray40", fontcolor="gray40", style=dashed`,
+	"loop":        `style=dashed`,
+}
+
+// WriteDOT writes g in Graphviz DOT format. Each block is a box listing its
+// statements with their source lines; unreachable blocks are drawn gray and
+// dashed.
+func (g *Graph) WriteDOT(w io.Writer, title string) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %s {\n", dotQuote(title))
+	fmt.Fprintf(bw, "\tlabel=%s;\n\tlabelloc=t;\n", dotQuote(title))
+	fmt.Fprintln(bw, "\tnode [shape=box, fontname=\"monospace\", fontsize=10];")
+	fmt.Fprintln(bw, "\tedge [fontname=\"monospace\", fontsize=9];")
+	for _, blk := range g.Blocks {
+		var label strings.Builder
+		fmt.Fprintf(&label, "B%d %s\\l", blk.Index, blk.Kind)
+		for _, l := range blk.Lines {
+			fmt.Fprintf(&label, "%4d: %s\\l", l.Line, dotEscape(l.Text))
+		}
+		attrs := ""
+		switch {
+		case blk == g.Entry(), blk == g.Exit():
+			attrs = ", style=bold"
+		case !blk.Reachable():
+			attrs = `, style=dashed, color="gray60", fontcolor="gray60"`
+		}
+		fmt.Fprintf(bw, "\tB%d [label=\"%s\"%s];\n", blk.Index, label.String(), attrs)
+	}
+	for _, blk := range g.Blocks {
+		for _, e := range blk.Succs {
+			attrs := ""
+			if e.Label != "" {
+				attrs = "label=" + dotQuote(e.Label)
+				if style := edgeStyles[e.Label]; style != "" {
+					attrs += ", " + style
+				}
+			}
+			if attrs != "" {
+				attrs = " [" + attrs + "]"
+			}
+			fmt.Fprintf(bw, "\tB%d -> B%d%s;\n", blk.Index, e.To.Index, attrs)
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// WriteText writes g as a plain listing, for reading without Graphviz.
+func (g *Graph) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for i, blk := range g.Blocks {
+		if i > 0 {
+			fmt.Fprintln(bw)
+		}
+		note := ""
+		if !blk.Reachable() {
+			note = " (unreachable)"
+		}
+		fmt.Fprintf(bw, "B%d %s%s\n", blk.Index, blk.Kind, note)
+		for _, l := range blk.Lines {
+			fmt.Fprintf(bw, "  %4d  %s\n", l.Line, l.Text)
+		}
+		for _, e := range blk.Succs {
+			if e.Label == "" {
+				fmt.Fprintf(bw, "  -> B%d\n", e.To.Index)
+			} else {
+				fmt.Fprintf(bw, "  -> B%d (%s)\n", e.To.Index, e.Label)
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func dotEscape(s string) string { return dotEscaper.Replace(s) }
+
+func dotQuote(s string) string { return `"` + dotEscape(s) + `"` }
@@ -0,0 +1,155 @@
+// Package main draws the control-flow graph of a Go function, to show where
+// break, continue, fallthrough, return and goto actually send control.
+//
+// The function is split into basic blocks (straight-line runs of statements)
+// joined by edges labeled with when they are taken: true/false for
+// conditions, the case for switch arms, and break, continue, fallthrough and
+// friends in their own colors. Every statement is shown with its source line,
+// and code no path reaches is drawn gray.
+//
+// The graph is built from go/ast alone, so the file only has to parse; it
+// need not compile. Conditions with && and || stay in one block.
+//
+//	go run main.go cfg.go dot.go                         # Run in the Control Statements lesson
+//	go run main.go cfg.go dot.go | dot -Tsvg -o cfg.svg  # render with Graphviz
+//	go run main.go cfg.go dot.go -format text
+//	go run main.go cfg.go dot.go -func main ../../1_Foundations/3_Control_Statements/control-statements.go
+//	go run main.go cfg.go dot.go -func Calendar.AddBusinessDays ../18_Business_Calendar/calendar.go
+//	go run main.go cfg.go dot.go -list ../20_Mini_Language/interp.go
+//	go test *.go                                         # compares testdata/flow.go with its .golden files
+//
+// Exit codes: 0 on success, 1 on runtime errors, 2 on invalid usage.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"strings"
+)
+
+// defaultFile is the lesson the tool was written for. Its main only calls
+// Run, where the control statements are.
+const defaultFile = "../../1_Foundations/3_Control_Statements/control-statements.go"
+
+// ErrNoFunc is returned when the file has no function with the given name.
+var ErrNoFunc = errors.New("function not found")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("cfgview", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	funcName := flags.String("func", "Run", "function to draw; methods are written Type.Method")
+	format := flags.String("format", "dot", "output format: dot or text")
+	list := flags.Bool("list", false, "list the functions in the file instead")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if *format != "dot" && *format != "text" {
+		fmt.Fprintf(stderr, "error: unknown format %q (want dot or text)\n", *format)
+		return 2
+	}
+	file := defaultFile
+	switch flags.NArg() {
+	case 0:
+	case 1:
+		file = flags.Arg(0)
+	default:
+		fmt.Fprintln(stderr, "error: one file at a time")
+		return 2
+	}
+
+	src, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, src, 0)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+
+	if *list {
+		for _, fn := range funcs(f) {
+			fmt.Fprintf(stdout, "%s\t%d\n", funcKey(fn), fset.Position(fn.Pos()).Line)
+		}
+		return 0
+	}
+
+	fn, err := findFunc(f, *funcName)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %s: %v\n", file, err)
+		return 2
+	}
+	g := Build(fset, src, fn.Body)
+	if *format == "text" {
+		err = g.WriteText(stdout)
+	} else {
+		title := fmt.Sprintf("%s in %s", funcKey(fn), fset.Position(fn.Pos()).Filename)
+		err = g.WriteDOT(stdout, title)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
+
+// funcs returns the functions and methods with a body, in file order.
+func funcs(f *ast.File) []*ast.FuncDecl {
+	var list []*ast.FuncDecl
+	for _, d := range f.Decls {
+		if fn, ok := d.(*ast.FuncDecl); ok && fn.Body != nil {
+			list = append(list, fn)
+		}
+	}
+	return list
+}
+
+// funcKey names a function "Name" or a method "Type.Name".
+func funcKey(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	t := fn.Recv.List[0].Type
+	for {
+		switch x := t.(type) {
+		case *ast.StarExpr:
+			t = x.X
+			continue
+		case *ast.IndexExpr: // Generic receiver, T[E].
+			t = x.X
+			continue
+		case *ast.IndexListExpr: // T[K, V].
+			t = x.X
+			continue
+		case *ast.Ident:
+			return x.Name + "." + fn.Name.Name
+		}
+		return fn.Name.Name
+	}
+}
+
+func findFunc(f *ast.File, name string) (*ast.FuncDecl, error) {
+	var names []string
+	for _, fn := range funcs(f) {
+		if funcKey(fn) == name {
+			return fn, nil
+		}
+		names = append(names, funcKey(fn))
+	}
+	return nil, fmt.Errorf("%w: %q (have %s)", ErrNoFunc, name, strings.Join(names, ", "))
+}
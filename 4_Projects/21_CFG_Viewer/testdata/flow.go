@@ -0,0 +1,73 @@
+// Package flow holds one function per construct the CFG builder handles
+// specially. TestGolden draws each with -format text and compares the result
+// with the .golden file of the same name.
+package flow
+
+import "fmt"
+
+func LabeledBreak(grid [][]int) {
+outer:
+	for _, row := range grid {
+		for _, v := range row {
+			if v < 0 {
+				break outer
+			}
+			fmt.Println(v)
+		}
+	}
+	fmt.Println("done")
+}
+
+func LabeledContinue(grid [][]int) {
+rows:
+	for i := 0; i < len(grid); i++ {
+		for _, v := range grid[i] {
+			if v == 0 {
+				continue rows
+			}
+			fmt.Println(v)
+		}
+	}
+}
+
+func ForwardGoto(n int) int {
+	if n < 0 {
+		goto fail
+	}
+	n *= 2
+	return n
+fail:
+	fmt.Println("negative")
+	return -1
+}
+
+func Fallthrough(level int) {
+	switch level {
+	case 1:
+		fmt.Println("Level 1")
+		fallthrough
+	case 2:
+		fmt.Println("Level 2")
+	default:
+		fmt.Println("Other")
+	}
+}
+
+func NoMatch(day int) {
+	switch day {
+	case 6, 7:
+		fmt.Println("Weekend")
+	case 1:
+		fmt.Println("Monday")
+	}
+	fmt.Println("after")
+}
+
+func AfterReturn(x int) int {
+	if x > 0 {
+		return x
+	}
+	return -x
+	fmt.Println("never")
+	return 0
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// Func is the complexity of one function or method. Function literals count
+// towards the function they appear in.
+type Func struct {
+	Name       string `json:"name"` // "Name", or "Type.Name" for methods.
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Cyclomatic int    `json:"cyclomatic"`
+	Cognitive  int    `json:"cognitive"`
+}
+
+// Analyze returns the complexity of every function with a body in f, in file
+// order. name is the file name to record in the results.
+func Analyze(fset *token.FileSet, f *ast.File, name string) []Func {
+	var list []Func
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		list = append(list, Func{
+			Name:       funcKey(fn),
+			File:       name,
+			Line:       fset.Position(fn.Pos()).Line,
+			Cyclomatic: Cyclomatic(fn),
+			Cognitive:  Cognitive(fn),
+		})
+	}
+	return list
+}
+
+// Cyclomatic returns McCabe's cyclomatic complexity of fn: one plus the
+// number of decision points, counted the way gocyclo does. Every if, for,
+// range, non-default case and && or || is a decision point; else and default
+// are not, since they add no path of their own.
+func Cyclomatic(fn *ast.FuncDecl) int {
+	n := 1
+	ast.Inspect(fn.Body, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			n++
+		case *ast.CaseClause:
+			if x.List != nil {
+				n++
+			}
+		case *ast.CommClause:
+			if x.Comm != nil {
+				n++
+			}
+		case *ast.BinaryExpr:
+			if x.Op == token.LAND || x.Op == token.LOR {
+				n++
+			}
+		}
+		return true
+	})
+	return n
+}
+
+// Cognitive returns the cognitive complexity of fn, following the
+// SonarSource definition as gocognit applies it to Go:
+//
+//   - if, switch, select, for and range cost 1 plus the current nesting;
+//   - else if, else, goto, a labeled break or continue, each run of the
+//     same logical operator in a condition and a direct recursive call
+//     cost 1;
+//   - the bodies of if, else, switch, select, loops and function literals
+//     are one level deeper.
+//
+// Unlike cyclomatic complexity it charges for nesting, so a flat switch
+// scores low and three nested loops score high.
+func Cognitive(fn *ast.FuncDecl) int {
+	v := &cognitive{name: fn.Name.Name, seen: map[*ast.BinaryExpr]bool{}}
+	if fn.Recv != nil && len(fn.Recv.List) > 0 {
+		v.method = true
+		if len(fn.Recv.List[0].Names) > 0 {
+			v.recv = fn.Recv.List[0].Names[0].Name
+		}
+	}
+	v.walk(fn.Body)
+	return v.score
+}
+
+type cognitive struct {
+	name    string // Function name, to spot recursion.
+	method  bool
+	recv    string // Receiver name for methods, "" when there is none or it is unnamed.
+	nesting int
+	score   int
+	seen    map[*ast.BinaryExpr]bool // Operands of a logical sequence already charged.
+}
+
+// walk visits n at the current nesting. Nodes that change the nesting are
+// handled by visit, which walks their parts itself.
+func (v *cognitive) walk(n ast.Node) {
+	ast.Inspect(n, v.visit)
+}
+
+// walkExpr and walkStmt skip absent optional parts, which ast.Inspect
+// cannot take as typed nils.
+func (v *cognitive) walkExpr(e ast.Expr) {
+	if e != nil {
+		v.walk(e)
+	}
+}
+
+func (v *cognitive) walkStmt(s ast.Stmt) {
+	if s != nil {
+		v.walk(s)
+	}
+}
+
+// nested walks a body one level deeper.
+func (v *cognitive) nested(body *ast.BlockStmt) {
+	v.nesting++
+	v.walk(body)
+	v.nesting--
+}
+
+func (v *cognitive) visit(n ast.Node) bool {
+	switch x := n.(type) {
+	case *ast.IfStmt:
+		v.score += 1 + v.nesting
+		v.ifStmt(x)
+		return false
+	case *ast.SwitchStmt:
+		v.score += 1 + v.nesting
+		v.walkStmt(x.Init)
+		v.walkExpr(x.Tag)
+		v.nested(x.Body)
+		return false
+	case *ast.TypeSwitchStmt:
+		v.score += 1 + v.nesting
+		v.walkStmt(x.Init)
+		v.walkStmt(x.Assign)
+		v.nested(x.Body)
+		return false
+	case *ast.SelectStmt:
+		v.score += 1 + v.nesting
+		v.nested(x.Body)
+		return false
+	case *ast.ForStmt:
+		v.score += 1 + v.nesting
+		v.walkStmt(x.Init)
+		v.walkExpr(x.Cond)
+		v.walkStmt(x.Post)
+		v.nested(x.Body)
+		return false
+	case *ast.RangeStmt:
+		v.score += 1 + v.nesting
+		v.walkExpr(x.Key)
+		v.walkExpr(x.Value)
+		v.walk(x.X)
+		v.nested(x.Body)
+		return false
+	case *ast.FuncLit:
+		v.nested(x.Body)
+		return false
+	case *ast.BranchStmt:
+		if x.Tok == token.GOTO || (x.Label != nil && x.Tok != token.FALLTHROUGH) {
+			v.score++
+		}
+	case *ast.BinaryExpr:
+		if (x.Op == token.LAND || x.Op == token.LOR) && !v.seen[x] {
+			v.score += v.logicalRuns(x)
+		}
+	case *ast.CallExpr:
+		if v.recursive(x) {
+			v.score++
+		}
+	}
+	return true
+}
+
+// ifStmt walks an if whose own increment is already counted, then its else
+// chain: else if and else cost 1 each but add no nesting charge.
+func (v *cognitive) ifStmt(s *ast.IfStmt) {
+	v.walkStmt(s.Init)
+	v.walk(s.Cond)
+	v.nested(s.Body)
+	switch e := s.Else.(type) {
+	case *ast.IfStmt:
+		v.score++
+		v.ifStmt(e)
+	case *ast.BlockStmt:
+		v.score++
+		v.nested(e)
+	}
+}
+
+// logicalRuns returns the number of runs of the same operator in the
+// sequence of && and || that e starts: a && b && c is one run, a && b || c
+// is two. Parentheses do not break a sequence; a negation does, and the
+// expression under it is charged on its own.
+func (v *cognitive) logicalRuns(e *ast.BinaryExpr) int {
+	var ops []token.Token
+	var flatten func(x ast.Expr)
+	flatten = func(x ast.Expr) {
+		switch x := x.(type) {
+		case *ast.ParenExpr:
+			flatten(x.X)
+		case *ast.BinaryExpr:
+			if x.Op != token.LAND && x.Op != token.LOR {
+				return
+			}
+			v.seen[x] = true
+			flatten(x.X)
+			ops = append(ops, x.Op)
+			flatten(x.Y)
+		}
+	}
+	flatten(e)
+	runs := 0
+	for i, op := range ops {
+		if i == 0 || op != ops[i-1] {
+			runs++
+		}
+	}
+	return runs
+}
+
+// recursive reports whether call is a direct call of the function being
+// scored: name(...) for functions, recv.name(...) for methods. A method
+// with an unnamed receiver cannot call itself directly.
+func (v *cognitive) recursive(call *ast.CallExpr) bool {
+	switch f := call.Fun.(type) {
+	case *ast.Ident:
+		return !v.method && f.Name == v.name
+	case *ast.SelectorExpr:
+		x, ok := f.X.(*ast.Ident)
+		return ok && v.recv != "" && x.Name == v.recv && f.Sel.Name == v.name
+	}
+	return false
+}
+
+// funcKey names a function "Name" or a method "Type.Name".
+func funcKey(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	t := fn.Recv.List[0].Type
+	for {
+		switch x := t.(type) {
+		case *ast.StarExpr:
+			t = x.X
+			continue
+		case *ast.IndexExpr: // Generic receiver, T[E].
+			t = x.X
+			continue
+		case *ast.IndexListExpr: // T[K, V].
+			t = x.X
+			continue
+		case *ast.Ident:
+			return x.Name + "." + fn.Name.Name
+		}
+		return fn.Name.Name
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+// scores matches the comment after each function in testdata/refs.go.
+var scores = regexp.MustCompile(`^// cyclomatic (\d+), cognitive (\d+)$`)
+
+// TestReferenceScores checks every function in testdata/refs.go against the
+// scores in the comment after its closing brace.
+func TestReferenceScores(t *testing.T) {
+	const file = "testdata/refs.go"
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type want struct{ cyclomatic, cognitive int }
+	wants := map[int]want{} // By line.
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			if m := scores.FindStringSubmatch(c.Text); m != nil {
+				cyc, _ := strconv.Atoi(m[1])
+				cog, _ := strconv.Atoi(m[2])
+				wants[fset.Position(c.Slash).Line] = want{cyc, cog}
+			}
+		}
+	}
+
+	checked := 0
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		w, ok := wants[fset.Position(fn.End()).Line]
+		if !ok {
+			t.Errorf("%s has no scores comment", funcKey(fn))
+			continue
+		}
+		checked++
+		if got := Cyclomatic(fn); got != w.cyclomatic {
+			t.Errorf("%s: cyclomatic %d, want %d", funcKey(fn), got, w.cyclomatic)
+		}
+		if got := Cognitive(fn); got != w.cognitive {
+			t.Errorf("%s: cognitive %d, want %d", funcKey(fn), got, w.cognitive)
+		}
+	}
+	if checked == 0 || checked != len(wants) {
+		t.Errorf("checked %d functions, but there are %d scores comments", checked, len(wants))
+	}
+}
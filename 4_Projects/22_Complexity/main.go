@@ -0,0 +1,145 @@
+// Package main reports the cyclomatic and cognitive complexity of every Go
+// function in a tree, and flags the ones over a threshold. The lessons' main
+// functions are long chains of branches and loops; this shows which ones and
+// by how much.
+//
+// Cyclomatic complexity counts the independent paths through a function, so
+// it tells how many test cases full branch coverage needs. Cognitive
+// complexity charges extra for nesting and for breaks in the linear flow, so
+// it tracks how hard the function is to read. See Cyclomatic and Cognitive
+// for exactly what is counted.
+//
+// With no arguments the whole repository is analyzed. Directories are
+// walked recursively, skipping testdata, vendor and names starting with "."
+// or "_"; generated files are skipped too. Function literals count towards
+// the function they are in.
+//
+//	go run main.go complexity.go report.go                                 # the whole repository, most complex first
+//	go run main.go complexity.go report.go -over                           # only the flagged functions
+//	go run main.go complexity.go report.go -sort cyclomatic ../../1_Foundations
+//	go run main.go complexity.go report.go -cyclomatic 5 -cognitive 8 ../../1_Foundations/3_Control_Statements
+//	go run main.go complexity.go report.go -format json > complexity.json
+//	go run main.go complexity.go report.go -format html > complexity.html  # click a column header to sort
+//	go test *.go                                                           # scores testdata/refs.go against gocyclo and gocognit
+//
+// Exit codes: 0 on success, 1 on runtime errors, 2 on invalid usage.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultRoot is the repository root, seen from this project.
+const defaultRoot = "../.."
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("complexity", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var th Thresholds
+	flags.IntVar(&th.Cyclomatic, "cyclomatic", 10, "flag functions with a cyclomatic complexity above this")
+	flags.IntVar(&th.Cognitive, "cognitive", 15, "flag functions with a cognitive complexity above this")
+	format := flags.String("format", "text", "output format: text, json or html")
+	sortKey := flags.String("sort", "cognitive", "order: cognitive, cyclomatic, name or file")
+	over := flags.Bool("over", false, "list only the functions over a threshold")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if th.Cyclomatic < 1 || th.Cognitive < 0 {
+		fmt.Fprintln(stderr, "error: -cyclomatic must be at least 1 and -cognitive at least 0")
+		return 2
+	}
+	write := map[string]func(*Report, io.Writer) error{
+		"text": (*Report).WriteText,
+		"json": (*Report).WriteJSON,
+		"html": (*Report).WriteHTML,
+	}[*format]
+	if write == nil {
+		fmt.Fprintf(stderr, "error: unknown format %q (want text, json or html)\n", *format)
+		return 2
+	}
+	if sortKeys[*sortKey] == nil {
+		fmt.Fprintf(stderr, "error: unknown sort order %q (want cognitive, cyclomatic, name or file)\n", *sortKey)
+		return 2
+	}
+
+	roots := flags.Args()
+	if len(roots) == 0 {
+		roots = []string{defaultRoot}
+	}
+	report := &Report{Thresholds: th}
+	fset := token.NewFileSet()
+	for _, root := range roots {
+		if err := analyzeTree(fset, root, len(roots) == 1, report); err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+	}
+	report.Sort(*sortKey)
+	if *over {
+		report.OnlyFlagged()
+	}
+	if err := write(report, stdout); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
+
+// analyzeTree adds the functions of every Go file under root, or of root
+// itself if it is a file. With relative set, files under a directory are
+// named relative to it, which keeps the default report readable.
+func analyzeTree(fset *token.FileSet, root string, relative bool, report *Report) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && skipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path != root && !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		if ast.IsGenerated(f) {
+			return nil
+		}
+		name := path
+		if relative && path != root {
+			if rel, err := filepath.Rel(root, path); err == nil {
+				name = rel
+			}
+		}
+		funcs := Analyze(fset, f, filepath.ToSlash(name))
+		report.Files++
+		report.Total += len(funcs)
+		report.Funcs = append(report.Funcs, funcs...)
+		return nil
+	})
+}
+
+func skipDir(name string) bool {
+	return name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
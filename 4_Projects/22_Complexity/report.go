@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+// Thresholds above which a function is flagged. The defaults are the usual
+// limits: 10 is McCabe's own for cyclomatic complexity and 15 is
+// SonarSource's for cognitive complexity.
+type Thresholds struct {
+	Cyclomatic int `json:"cyclomatic"`
+	Cognitive  int `json:"cognitive"`
+}
+
+// Report is the result of analyzing a set of files.
+type Report struct {
+	Thresholds Thresholds `json:"thresholds"`
+	Files      int        `json:"files"`
+	Total      int        `json:"total"` // Functions analyzed, including any dropped by OnlyFlagged.
+	Funcs      []Func     `json:"functions"`
+}
+
+// Over reports whether f exceeds either threshold.
+func (r *Report) Over(f Func) bool {
+	return f.Cyclomatic > r.Thresholds.Cyclomatic || f.Cognitive > r.Thresholds.Cognitive
+}
+
+// Flagged returns the number of listed functions over a threshold.
+func (r *Report) Flagged() int {
+	n := 0
+	for _, f := range r.Funcs {
+		if r.Over(f) {
+			n++
+		}
+	}
+	return n
+}
+
+// sortKeys are the orders -sort accepts. Scores sort highest first, with
+// the other score and then the location breaking ties.
+var sortKeys = map[string]func(a, b Func) bool{
+	"cognitive": func(a, b Func) bool {
+		if a.Cognitive != b.Cognitive {
+			return a.Cognitive > b.Cognitive
+		}
+		if a.Cyclomatic != b.Cyclomatic {
+			return a.Cyclomatic > b.Cyclomatic
+		}
+		return byLocation(a, b)
+	},
+	"cyclomatic": func(a, b Func) bool {
+		if a.Cyclomatic != b.Cyclomatic {
+			return a.Cyclomatic > b.Cyclomatic
+		}
+		if a.Cognitive != b.Cognitive {
+			return a.Cognitive > b.Cognitive
+		}
+		return byLocation(a, b)
+	},
+	"name": func(a, b Func) bool {
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return byLocation(a, b)
+	},
+	"file": byLocation,
+}
+
+func byLocation(a, b Func) bool {
+	if a.File != b.File {
+		return a.File < b.File
+	}
+	return a.Line < b.Line
+}
+
+// Sort orders the functions by key, one of the keys of sortKeys.
+func (r *Report) Sort(key string) {
+	sort.SliceStable(r.Funcs, func(i, j int) bool { return sortKeys[key](r.Funcs[i], r.Funcs[j]) })
+}
+
+// OnlyFlagged drops the functions within both thresholds.
+func (r *Report) OnlyFlagged() {
+	kept := r.Funcs[:0]
+	for _, f := range r.Funcs {
+		if r.Over(f) {
+			kept = append(kept, f)
+		}
+	}
+	r.Funcs = kept
+}
+
+// WriteText writes the report as a table. Scores over their threshold are
+// marked with "!".
+func (r *Report) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	tw := tabwriter.NewWriter(bw, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CYCLO\tCOGN\tFUNCTION\tLOCATION")
+	for _, f := range r.Funcs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s:%d\n",
+			mark(f.Cyclomatic, r.Thresholds.Cyclomatic), mark(f.Cognitive, r.Thresholds.Cognitive),
+			f.Name, f.File, f.Line)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(bw, "\n%s\n", r.summary())
+	return bw.Flush()
+}
+
+func mark(score, limit int) string {
+	if score > limit {
+		return fmt.Sprintf("%d!", score)
+	}
+	return fmt.Sprint(score)
+}
+
+func (r *Report) summary() string {
+	return fmt.Sprintf("%d functions in %d files; %d over the thresholds (cyclomatic > %d or cognitive > %d)",
+		r.Total, r.Files, r.Flagged(), r.Thresholds.Cyclomatic, r.Thresholds.Cognitive)
+}
+
+// WriteJSON writes the report as indented JSON. Each function carries an
+// "over" field so consumers need not repeat the threshold check.
+func (r *Report) WriteJSON(w io.Writer) error {
+	type funcJSON struct {
+		Func
+		Over bool `json:"over"`
+	}
+	out := struct {
+		Thresholds Thresholds `json:"thresholds"`
+		Files      int        `json:"files"`
+		Total      int        `json:"total"`
+		Flagged    int        `json:"flagged"`
+		Funcs      []funcJSON `json:"functions"`
+	}{r.Thresholds, r.Files, r.Total, r.Flagged(), make([]funcJSON, len(r.Funcs))}
+	for i, f := range r.Funcs {
+		out.Funcs[i] = funcJSON{f, r.Over(f)}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(out)
+}
+
+// WriteHTML writes the report as a standalone page with a table that sorts
+// by any column when its header is clicked.
+func (r *Report) WriteHTML(w io.Writer) error {
+	return reportPage.Execute(w, r)
+}
+
+// reportPage needs no network access: the style and the sorting script are
+// inline. Numeric columns sort highest first on the first click.
+var reportPage = template.Must(template.New("report").Funcs(template.FuncMap{
+	"summary": (*Report).summary,
+	"over":    func(score, limit int) bool { return score > limit },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Complexity report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { padding: 0.3em 0.8em; border-bottom: 1px solid #ddd; text-align: left; }
+th { cursor: pointer; user-select: none; background: #f4f4f4; }
+th.asc::after { content: " \25B2"; }
+th.desc::after { content: " \25BC"; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+td.over { background: #fdd; font-weight: bold; }
+code { font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>Complexity report</h1>
+<p>{{summary .}}</p>
+<table id="report">
+<thead><tr>
+<th data-type="num">Cyclomatic</th>
+<th data-type="num">Cognitive</th>
+<th>Function</th>
+<th>Location</th>
+</tr></thead>
+<tbody>
+{{- range .Funcs}}
+<tr>
+<td class="num{{if over .Cyclomatic $.Thresholds.Cyclomatic}} over{{end}}">{{.Cyclomatic}}</td>
+<td class="num{{if over .Cognitive $.Thresholds.Cognitive}} over{{end}}">{{.Cognitive}}</td>
+<td><code>{{.Name}}</code></td>
+<td data-key="{{.File}}:{{printf "%08d" .Line}}">{{.File}}:{{.Line}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+<script>
+document.querySelectorAll("#report th").forEach(function (th, col) {
+	th.addEventListener("click", function () {
+		var numeric = th.dataset.type === "num";
+		var desc = th.classList.contains("desc") ? false : th.classList.contains("asc") ? true : numeric;
+		document.querySelectorAll("#report th").forEach(function (h) { h.classList.remove("asc", "desc"); });
+		th.classList.add(desc ? "desc" : "asc");
+		var body = document.querySelector("#report tbody");
+		var rows = Array.from(body.rows);
+		var key = function (row) {
+			var cell = row.cells[col];
+			return numeric ? Number(cell.textContent) : (cell.dataset.key || cell.textContent);
+		};
+		rows.sort(function (a, b) {
+			var x = key(a), y = key(b);
+			var c = x < y ? -1 : x > y ? 1 : 0;
+			return desc ? -c : c;
+		});
+		rows.forEach(function (row) { body.appendChild(row); });
+	});
+});
+</script>
+</body>
+</html>
+`))
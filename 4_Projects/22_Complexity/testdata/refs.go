@@ -0,0 +1,221 @@
+// Package refs holds reference functions for the complexity scores. Most
+// come from the gocognit and gocyclo test suites; each ends with the scores
+// both tools give it, and the comments show where the cognitive points come
+// from.
+package refs
+
+import "fmt"
+
+func HelloWorld() string {
+	return "Hello, World!"
+} // cyclomatic 1, cognitive 0
+
+func SimpleCond(n int) string {
+	if n == 100 { // +1
+		return "a hundred"
+	}
+	return "others"
+} // cyclomatic 2, cognitive 1
+
+func IfElseNested(n int) string {
+	if n == 100 { // +1
+		return "a hundred"
+	} else { // +1
+		if n == 200 { // +2 (nesting 1)
+			return "two hundred"
+		}
+	}
+	return "others"
+} // cyclomatic 3, cognitive 4
+
+func IfElseIfNested(n int) string {
+	if n == 100 { // +1
+		return "a hundred"
+	} else if n < 300 { // +1, no nesting charge
+		if n == 200 { // +2 (nesting 1)
+			return "two hundred"
+		}
+	}
+	return "others"
+} // cyclomatic 4, cognitive 4
+
+func ElseIfChain(n int) string {
+	if n < 0 { // +1
+		return "negative"
+	} else if n == 0 { // +1
+		return "zero"
+	} else if n < 10 { // +1
+		return "small"
+	} else { // +1
+		return "large"
+	}
+} // cyclomatic 4, cognitive 4
+
+func SimpleLogicalSeq1(a, b, c, d bool) string {
+	if a && b && c && d { // +1 for if, +1 for the && run
+		return "ok"
+	}
+	return "not ok"
+} // cyclomatic 5, cognitive 2
+
+func SimpleLogicalSeq2(a, b, c, d bool) string {
+	if a || b || c || d { // +1 for if, +1 for the || run
+		return "ok"
+	}
+	return "not ok"
+} // cyclomatic 5, cognitive 2
+
+func ComplexLogicalSeq1(a, b, c, d, e, f bool) string {
+	if a && b && c || d || e && f { // +1 for if, +3 for the runs && || &&
+		return "ok"
+	}
+	return "not ok"
+} // cyclomatic 7, cognitive 4
+
+func ComplexLogicalSeq2(a, b, c, d, e, f bool) string {
+	if a && !(b && c) { // +1 for if, +1 for a && ..., +1 for the run under !
+		return "ok"
+	}
+	return "not ok"
+} // cyclomatic 4, cognitive 3
+
+func ComplexLogicalSeq3(a, b, c, d, e, f bool) string {
+	if (a && b) && (c && d) { // +1 for if, +1: parentheses do not break a run
+		return "ok"
+	}
+	return "not ok"
+} // cyclomatic 5, cognitive 2
+
+func ExprFunc(a, b, c any) bool {
+	if a != nil || b != nil || c != nil { // +1 for if, +1 for the || run
+		return false
+	}
+	return true
+} // cyclomatic 4, cognitive 2
+
+func VarFunc(a, b, c any) bool {
+	na := a != nil || b != nil || c != nil // +1 for the || run
+	return na
+} // cyclomatic 3, cognitive 1
+
+func GetWords(number int) string {
+	switch number { // +1
+	case 1:
+		return "one"
+	case 2:
+		return "a couple"
+	case 3, 4:
+		return "a few"
+	default:
+		return "lots"
+	}
+} // cyclomatic 4, cognitive 1
+
+func SumOfPrimes(max int) int {
+	total := 0
+OUT:
+	for i := 1; i <= max; i++ { // +1
+		for j := 2; j < i; j++ { // +2 (nesting 1)
+			if i%j == 0 { // +3 (nesting 2)
+				continue OUT // +1
+			}
+		}
+		total += i
+	}
+	return total
+} // cyclomatic 4, cognitive 7
+
+func Factorial(n int) int {
+	if n <= 1 { // +1
+		return 1
+	} else { // +1
+		return n * Factorial(n-1) // +1
+	}
+} // cyclomatic 2, cognitive 3
+
+func FuncLitFunc() {
+	fmt.Println("hello")
+	a := func() { // +0, but the body is one level deeper
+		if true { // +2 (nesting 1)
+			fmt.Println("true")
+		}
+	}
+	a()
+} // cyclomatic 2, cognitive 2
+
+func NestedFuncLit(xs []int) {
+	for range xs { // +1
+		func() { // the body is now two levels deep
+			if len(xs) > 1 { // +3 (nesting 2)
+				fmt.Println(xs)
+			}
+		}()
+	}
+} // cyclomatic 3, cognitive 4
+
+func MyFunc(a bool) {
+	if a { // +1
+		for i := 0; i < 10; i++ { // +2 (nesting 1)
+			n := 0
+			for n < 10 { // +3 (nesting 2)
+				n++
+			}
+		}
+	}
+} // cyclomatic 4, cognitive 6
+
+func Goto(n int) int {
+	if n < 0 { // +1
+		goto fail // +1
+	}
+	return n
+fail:
+	return 0
+} // cyclomatic 2, cognitive 2
+
+func Fallthrough(n int) {
+	switch { // +1
+	case n > 1:
+		fmt.Println(">1")
+		fallthrough // free, unlike a labeled branch
+	case n > 0:
+		fmt.Println(">0")
+	}
+} // cyclomatic 3, cognitive 1
+
+func TypeSwitchSelect(v any, ch chan int, done chan bool) {
+	switch v.(type) { // +1
+	case int, int64:
+	case string:
+	}
+	for { // +1
+		select { // +2 (nesting 1)
+		case <-ch:
+		case <-done:
+			return
+		default:
+		}
+	}
+} // cyclomatic 6, cognitive 4
+
+type Tree struct{ Left, Right *Tree }
+
+func (t *Tree) Depth() int {
+	if t == nil { // +1
+		return 0
+	}
+	return 1 + max(t.Left.Depth(), t.Right.Depth()) // not recursion through t
+} // cyclomatic 2, cognitive 1
+
+func (t *Tree) Count(n int) int {
+	if n <= 0 { // +1
+		return 0
+	}
+	return 1 + t.Count(n-1) // +1, recursion through the receiver
+} // cyclomatic 2, cognitive 2
+
+func (Tree) Unnamed() {
+	Unnamed() // a function of the same name, not the method
+} // cyclomatic 1, cognitive 0
+
+func Unnamed() {} // cyclomatic 1, cognitive 0
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// Exhaustive reports switch statements on an enum type that leave out some
+// of its values.
+var Exhaustive = &Analyzer{
+	Name: "exhaustive",
+	Doc: `check that switches on iota enums cover every value
+
+An enum is a named integer type of the package with constants declared in a
+const block that uses iota, such as
+
+	type Priority int
+
+	const (
+		_ Priority = iota
+		Low
+		Medium
+		High
+	)
+
+A switch whose tag has an enum type must have a case for each value; blank
+constants are not values. A default clause does not count unless
+-default-signifies-exhaustive is set. To accept a partial switch, put
+//exhaustive:ignore on the line above it or at the end of the switch line.`,
+	Run: runExhaustive,
+}
+
+var defaultSignifiesExhaustive bool
+
+func init() {
+	Exhaustive.Flags.BoolVar(&defaultSignifiesExhaustive, "default-signifies-exhaustive", false,
+		"accept a switch with a default clause as exhaustive")
+}
+
+// ignoreDirective opts a switch out of the check.
+const ignoreDirective = "//exhaustive:ignore"
+
+// enum is an iota enum type and its constants in declaration order.
+type enum struct {
+	members []*types.Const
+}
+
+func runExhaustive(pass *Pass) (any, error) {
+	enums := findEnums(pass)
+	if len(enums) == 0 {
+		return nil, nil
+	}
+	for _, f := range pass.Files {
+		ignored := ignoredLines(pass.Fset, f)
+		ast.Inspect(f, func(n ast.Node) bool {
+			sw, ok := n.(*ast.SwitchStmt)
+			if !ok || sw.Tag == nil || ignored[pass.Fset.Position(sw.Pos()).Line] {
+				return true
+			}
+			named, ok := types.Unalias(pass.TypesInfo.TypeOf(sw.Tag)).(*types.Named)
+			if !ok {
+				return true
+			}
+			if e := enums[named.Obj()]; e != nil {
+				checkSwitch(pass, sw, named, e)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// findEnums returns the package's iota enums, keyed by type. Only
+// package-level constants in a block that uses iota make a type an enum, so
+// a type with a few unrelated constants is left alone.
+func findEnums(pass *Pass) map[*types.TypeName]*enum {
+	enums := map[*types.TypeName]*enum{}
+	for _, f := range pass.Files {
+		for _, d := range f.Decls {
+			gd, ok := d.(*ast.GenDecl)
+			if !ok || gd.Tok != token.CONST || !usesIota(pass, gd) {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				for _, name := range spec.(*ast.ValueSpec).Names {
+					c, ok := pass.TypesInfo.Defs[name].(*types.Const)
+					if !ok || name.Name == "_" {
+						continue
+					}
+					named, ok := types.Unalias(c.Type()).(*types.Named)
+					if !ok || named.Obj().Pkg() != pass.Pkg {
+						continue
+					}
+					if basic, ok := named.Underlying().(*types.Basic); !ok || basic.Info()&types.IsInteger == 0 {
+						continue
+					}
+					e := enums[named.Obj()]
+					if e == nil {
+						e = &enum{}
+						enums[named.Obj()] = e
+					}
+					e.members = append(e.members, c)
+				}
+			}
+		}
+	}
+	return enums
+}
+
+func usesIota(pass *Pass, gd *ast.GenDecl) bool {
+	iota := types.Universe.Lookup("iota")
+	found := false
+	ast.Inspect(gd, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && pass.TypesInfo.Uses[id] == iota {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// checkSwitch reports the enum values no case of sw lists. Cases are
+// compared by value, so a constant that aliases a member covers it, and
+// members sharing a value are listed once under the first name.
+func checkSwitch(pass *Pass, sw *ast.SwitchStmt, named *types.Named, e *enum) {
+	covered := map[string]bool{}
+	hasDefault := false
+	for _, s := range sw.Body.List {
+		cc := s.(*ast.CaseClause)
+		if cc.List == nil {
+			hasDefault = true
+		}
+		for _, x := range cc.List {
+			if v := pass.TypesInfo.Types[x].Value; v != nil {
+				covered[v.ExactString()] = true
+			}
+		}
+	}
+	if hasDefault && defaultSignifiesExhaustive {
+		return
+	}
+	var missing []string
+	for _, c := range e.members {
+		if v := c.Val().ExactString(); !covered[v] {
+			covered[v] = true
+			missing = append(missing, c.Name())
+		}
+	}
+	if len(missing) > 0 {
+		pass.Reportf(sw.Pos(), "missing cases in switch of type %s: %s",
+			types.TypeString(named, types.RelativeTo(pass.Pkg)), strings.Join(missing, ", "))
+	}
+}
+
+// ignoredLines returns the lines of f on which a switch is exempt: the line
+// after a comment group holding the directive, and the directive's own line.
+func ignoredLines(fset *token.FileSet, f *ast.File) map[int]bool {
+	lines := map[int]bool{}
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			if c.Text == ignoreDirective || strings.HasPrefix(c.Text, ignoreDirective+" ") {
+				lines[fset.Position(c.Slash).Line] = true
+				lines[fset.Position(cg.End()).Line+1] = true
+			}
+		}
+	}
+	return lines
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// expectation is one "// want" pattern and whether a diagnostic matched it.
+type expectation struct {
+	re  *regexp.Regexp
+	met bool
+}
+
+// runPass runs Exhaustive over dir, with -default-signifies-exhaustive set to
+// defaultExhaustive, and returns what it reported.
+func runPass(t *testing.T, dir string, defaultExhaustive bool) (*token.FileSet, []*ast.File, []Diagnostic) {
+	t.Helper()
+	if err := Exhaustive.Flags.Set("default-signifies-exhaustive", strconv.FormatBool(defaultExhaustive)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Exhaustive.Flags.Set("default-signifies-exhaustive", "false") })
+
+	fset := token.NewFileSet()
+	pkg, files, info, err := loadPackage(fset, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var diags []Diagnostic
+	pass := &Pass{
+		Analyzer:  Exhaustive,
+		Fset:      fset,
+		Files:     files,
+		Pkg:       pkg,
+		TypesInfo: info,
+		Report:    func(d Diagnostic) { diags = append(diags, d) },
+	}
+	if _, err := Exhaustive.Run(pass); err != nil {
+		t.Fatal(err)
+	}
+	return fset, files, diags
+}
+
+// quoted matches a Go double-quoted string literal.
+var quoted = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+
+// wantComments collects the "// want" comments of files, keyed by
+// "file:line". Each quoted string after "want" is a regexp, as in analysistest.
+func wantComments(t *testing.T, fset *token.FileSet, files []*ast.File) map[string][]*expectation {
+	t.Helper()
+	want := map[string][]*expectation{}
+	for _, f := range files {
+		for _, cg := range f.Comments {
+			for _, c := range cg.List {
+				rest, ok := strings.CutPrefix(c.Text, "// want ")
+				if !ok {
+					continue
+				}
+				pos := fset.Position(c.Slash)
+				key := fmt.Sprintf("%s:%d", pos.Filename, pos.Line)
+				for _, q := range quoted.FindAllString(rest, -1) {
+					s, err := strconv.Unquote(q)
+					if err != nil {
+						t.Fatalf("%s: bad want pattern %s: %v", pos, q, err)
+					}
+					re, err := regexp.Compile(s)
+					if err != nil {
+						t.Fatalf("%s: bad want pattern %s: %v", pos, q, err)
+					}
+					want[key] = append(want[key], &expectation{re: re})
+				}
+			}
+		}
+	}
+	return want
+}
+
+// check reports diagnostics without a matching expectation on their line,
+// and expectations that no diagnostic met.
+func check(t *testing.T, fset *token.FileSet, diags []Diagnostic, want map[string][]*expectation) {
+	t.Helper()
+	for _, d := range diags {
+		pos := fset.Position(d.Pos)
+		matched := false
+		for _, e := range want[fmt.Sprintf("%s:%d", pos.Filename, pos.Line)] {
+			if !e.met && e.re.MatchString(d.Message) {
+				e.met, matched = true, true
+				break
+			}
+		}
+		if !matched {
+			t.Errorf("%s: unexpected diagnostic: %s", pos, d.Message)
+		}
+	}
+	for key, es := range want {
+		for _, e := range es {
+			if !e.met {
+				t.Errorf("%s: no diagnostic was reported matching %q", key, e.re)
+			}
+		}
+	}
+}
+
+func TestExhaustive(t *testing.T) {
+	fset, files, diags := runPass(t, "testdata/days", false)
+	check(t, fset, diags, wantComments(t, fset, files))
+}
+
+// TestDefaultSignifiesExhaustive expects the same diagnostics except for the
+// switch in Describe, which has a default clause.
+func TestDefaultSignifiesExhaustive(t *testing.T) {
+	fset, files, diags := runPass(t, "testdata/days", true)
+	want := wantComments(t, fset, files)
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "Describe" {
+				sw := fn.Body.List[0].(*ast.SwitchStmt)
+				pos := fset.Position(sw.Pos())
+				delete(want, fmt.Sprintf("%s:%d", pos.Filename, pos.Line))
+			}
+		}
+	}
+	check(t, fset, diags, want)
+}
+
+// TestIgnoreDirective checks both placements the directive accepts.
+func TestIgnoreDirective(t *testing.T) {
+	src := `package p
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+
+func Above(c Color) {
+	//exhaustive:ignore Only red matters here.
+	switch c {
+	case Red:
+	}
+}
+
+func SameLine(c Color) {
+	switch c { //exhaustive:ignore
+	case Red:
+	}
+}
+
+func Reported(c Color) {
+	switch c {
+	case Red, Green:
+	}
+}
+`
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, _, diags := runPass(t, dir, false)
+	if len(diags) != 1 || diags[0].Message != "missing cases in switch of type Color: Blue" {
+		t.Errorf("got %v, want only the switch in Reported", diags)
+	}
+}
+
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"testdata/days"}, &stdout, &stderr); code != 1 {
+		t.Errorf("exit code = %d, want 1 (stderr: %s)", code, &stderr)
+	}
+	want := "testdata/days/days.go:29:2: missing cases in switch of type Weekday: Saturday, Sunday\n" +
+		"testdata/days/days.go:45:2: missing cases in switch of type Weekday: Sunday\n"
+	if got := stdout.String(); got != want {
+		t.Errorf("stdout =\n%s\nwant\n%s", got, want)
+	}
+
+	// With no arguments only the lessons are checked, and they are exhaustive.
+	stdout.Reset()
+	if code := run(nil, &stdout, &stderr); code != 0 || stdout.Len() > 0 {
+		t.Errorf("default run: exit code = %d, output %q, want 0 and nothing (stderr: %s)", code, &stdout, &stderr)
+	}
+
+	// The projects that switch on their own enums pass the check as well.
+	// (The whole repository takes too long for a unit test.)
+	stdout.Reset()
+	projects := []string{"../4_Task_Manager", "../6_Checked_Convert", "../14_Transcoder", "../17_Rule_Engine", "../20_Mini_Language"}
+	if code := run(projects, &stdout, &stderr); code != 0 || stdout.Len() > 0 {
+		t.Errorf("projects: exit code = %d, findings:\n%s(stderr: %s)", code, &stdout, &stderr)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"-nope"}, &stdout, &stderr); code != 2 {
+		t.Errorf("bad flag: exit code = %d, want 2", code)
+	}
+}
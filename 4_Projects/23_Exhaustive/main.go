@@ -0,0 +1,123 @@
+// Package main runs the exhaustive analysis pass: every switch on an iota
+// enum must list all of the enum's values.
+//
+// The Control Statements lesson's "switch day" leans on default to catch
+// whatever it forgot, and nothing checks that a switch on the Priority iota
+// block from the Variables and Constants lesson handles High as well as Low
+// and Medium. Adding a constant to an enum should point at every switch that
+// now needs a case; this does.
+//
+//	go run main.go analysis.go exhaustive.go                   # the two lessons above
+//	go run main.go analysis.go exhaustive.go ../4_Task_Manager
+//	go run main.go analysis.go exhaustive.go ../../...         # every package in the repository
+//	go run main.go analysis.go exhaustive.go testdata/days     # a demo with findings
+//	go run main.go analysis.go exhaustive.go -default-signifies-exhaustive testdata/days
+//	go test *.go                                               # checks testdata against its "// want" comments
+//
+// Arguments are package directories; with none, the Variables and Constants
+// and Control Statements lessons are checked. The other projects list every
+// case of their internal enums, such as token kinds, or opt out where a
+// switch is partial on purpose, so a whole-repository run is clean too. A
+// trailing "/..." includes the directories below, skipping testdata, vendor
+// and names starting with "." or "_". See Exhaustive for what counts as an enum and how to opt a switch
+// out with //exhaustive:ignore.
+//
+// The pass is written against a small stand-in for
+// golang.org/x/tools/go/analysis (see analysis.go), since the repository has
+// no go.mod to import it with.
+//
+// Exit codes: 0 when every switch is exhaustive, 1 when one is not or on
+// runtime errors, 2 on invalid usage.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// defaultDirs are the lessons that declare Priority and switch on day,
+// seen from this project.
+var defaultDirs = []string{
+	"../../1_Foundations/2_Variables_Constants",
+	"../../1_Foundations/3_Control_Statements",
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(Exhaustive.Name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	Exhaustive.Flags.VisitAll(func(f *flag.Flag) { flags.Var(f.Value, f.Name, f.Usage) })
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: %s [flags] [dir | dir/...]...\n\n%s\n\nFlags:\n", Exhaustive.Name, Exhaustive.Doc)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		patterns = defaultDirs
+	}
+
+	var dirs []string
+	for _, p := range patterns {
+		if root, ok := strings.CutSuffix(p, "/..."); ok {
+			found, err := packageDirs(root)
+			if err != nil {
+				fmt.Fprintln(stderr, "error:", err)
+				return 1
+			}
+			dirs = append(dirs, found...)
+		} else {
+			dirs = append(dirs, p)
+		}
+	}
+
+	fset := token.NewFileSet()
+	var diags []Diagnostic
+	failed := false
+	for _, dir := range dirs {
+		pkg, files, info, err := loadPackage(fset, dir)
+		if err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			failed = true
+			continue
+		}
+		pass := &Pass{
+			Analyzer:  Exhaustive,
+			Fset:      fset,
+			Files:     files,
+			Pkg:       pkg,
+			TypesInfo: info,
+			Report:    func(d Diagnostic) { diags = append(diags, d) },
+		}
+		if _, err := Exhaustive.Run(pass); err != nil {
+			fmt.Fprintf(stderr, "error: %s: %v\n", dir, err)
+			failed = true
+		}
+	}
+
+	sort.SliceStable(diags, func(i, j int) bool { return diags[i].Pos < diags[j].Pos })
+	for _, d := range diags {
+		pos := fset.Position(d.Pos)
+		pos.Filename = filepath.ToSlash(pos.Filename)
+		fmt.Fprintf(stdout, "%s: %s\n", pos, d.Message)
+	}
+	if failed || len(diags) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,83 @@
+// Package days is the "switch day" from the Control Statements lesson with
+// the plain int replaced by an iota enum, so the exhaustive pass can check
+// it. Each switch below shows one case the pass handles, and a "// want"
+// comment gives the diagnostic expected on its line, as analysistest reads it.
+package days
+
+import "fmt"
+
+// Weekday counts from Monday, as the lesson does.
+type Weekday int
+
+const (
+	_ Weekday = iota
+	Monday
+	Tuesday
+	Wednesday
+	Thursday
+	Friday
+	Saturday
+	Sunday
+)
+
+// Midweek is an alias: a case on it covers Wednesday.
+const Midweek = Wednesday
+
+// Describe is the lesson's switch. Its default hides the missing weekend
+// days; it is reported unless -default-signifies-exhaustive is set.
+func Describe(day Weekday) string {
+	switch day { // want "missing cases in switch of type Weekday: Saturday, Sunday"
+	case Monday:
+		return "Monday"
+	case Tuesday:
+		return "Tuesday"
+	case Midweek:
+		return "Wednesday"
+	case Thursday, Friday:
+		return "Thursday or Friday"
+	default:
+		return "Weekend"
+	}
+}
+
+// IsWeekend forgot Sunday and is always reported.
+func IsWeekend(day Weekday) bool {
+	switch day { // want "missing cases in switch of type Weekday: Sunday"
+	case Saturday:
+		return true
+	case Monday, Tuesday, Wednesday, Thursday, Friday:
+		return false
+	}
+	panic(fmt.Sprintf("invalid day %d", day))
+}
+
+// Short covers every day, so it passes.
+func Short(day Weekday) string {
+	switch day {
+	case Monday:
+		return "Mon"
+	case Tuesday:
+		return "Tue"
+	case Wednesday:
+		return "Wed"
+	case Thursday:
+		return "Thu"
+	case Friday:
+		return "Fri"
+	case Saturday:
+		return "Sat"
+	case Sunday:
+		return "Sun"
+	}
+	return "?"
+}
+
+// Opens only cares about one day and says so.
+func Opens(day Weekday) string {
+	//exhaustive:ignore Only Monday has special hours.
+	switch day {
+	case Monday:
+		return "10:00"
+	}
+	return "08:00"
+}
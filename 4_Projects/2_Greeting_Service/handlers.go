@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Media types the /hello endpoint can produce, in order of preference when
+// the client has no preference of its own.
+const (
+	mediaText = "text/plain"
+	mediaJSON = "application/json"
+	mediaHTML = "text/html"
+)
+
+var helloOffers = []string{mediaText, mediaJSON, mediaHTML}
+
+// helloPage renders the HTML variant; html/template escapes the name so
+// "/hello?name=<script>" cannot inject markup.
+var helloPage = template.Must(template.New("hello").Parse(`<!DOCTYPE html>
+<html>
+<head><title>{{.Message}}</title></head>
+<body><h1>{{.Message}}</h1></body>
+</html>
+`))
+
+// greeting is the response body of /hello in JSON and the data for the HTML template.
+type greeting struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// greet returns a greeting message with the provided name, as in the Functions lesson.
+func greet(name string) string {
+	return "Hello, " + name + "!"
+}
+
+// NewServer wires up the routes and wraps them with request logging.
+// It returns a plain http.Handler so it can be served by http.Server or
+// exercised directly with net/http/httptest.
+func NewServer(logger *log.Logger) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hello", handleHello)
+	mux.HandleFunc("/healthz", handleHealth)
+	return logRequests(logger, mux)
+}
+
+// handleHello serves GET /hello?name=..., choosing the format from the Accept header.
+func handleHello(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodHead) {
+		return
+	}
+
+	name := strings.TrimSpace(r.URL.Query().Get("name"))
+	if name == "" {
+		name = "World"
+	}
+	body := greeting{Name: name, Message: greet(name)}
+
+	// Responses differ by Accept, so caches must key on it too.
+	w.Header().Add("Vary", "Accept")
+	switch negotiate(r.Header.Get("Accept"), helloOffers) {
+	case mediaText:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(body.Message + "\n"))
+	case mediaJSON:
+		writeJSON(w, http.StatusOK, body)
+	case mediaHTML:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = helloPage.Execute(w, body)
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusNotAcceptable)
+		_, _ = w.Write([]byte("supported types: " + strings.Join(helloOffers, ", ") + "\n"))
+	}
+}
+
+// handleHealth serves GET /healthz for load balancers and readiness probes.
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodHead) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// allowMethods replies with 405 and an Allow header when r uses any other method.
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if r.Method == m {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// statusRecorder remembers the status code and size written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+// logRequests is middleware that logs one line per request:
+// method, path, status, response size and duration.
+func logRequests(logger *log.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK // Handler wrote nothing at all.
+		}
+		logger.Printf("%s %s %d %dB %s", r.Method, r.URL.RequestURI(), rec.status, rec.bytes, time.Since(start))
+	})
+}
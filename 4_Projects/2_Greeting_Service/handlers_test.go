@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestServer(t *testing.T) (http.Handler, *bytes.Buffer) {
+	t.Helper()
+	var logs bytes.Buffer
+	return NewServer(log.New(&logs, "", 0)), &logs
+}
+
+func TestHelloNegotiation(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"no Accept", "", http.StatusOK, "text/plain; charset=utf-8", "Hello, Gopher!\n"},
+		{"text", "text/plain", http.StatusOK, "text/plain; charset=utf-8", "Hello, Gopher!\n"},
+		{"json", "application/json", http.StatusOK, "application/json", `{"name":"Gopher","message":"Hello, Gopher!"}` + "\n"},
+		{"html", "text/html", http.StatusOK, "text/html; charset=utf-8", "<h1>Hello, Gopher!</h1>"},
+		{"wildcard", "*/*", http.StatusOK, "text/plain; charset=utf-8", "Hello, Gopher!\n"},
+		{"q prefers json", "text/plain;q=0.5, application/json", http.StatusOK, "application/json", `"message":"Hello, Gopher!"`},
+		{"q prefers html", "application/json;q=0.2, text/html;q=0.9", http.StatusOK, "text/html; charset=utf-8", "<h1>"},
+		{"specific range beats wildcard", "text/*;q=0.1, text/html", http.StatusOK, "text/html; charset=utf-8", "<h1>"},
+		{"q=0 excludes", "text/plain;q=0, */*;q=0.5", http.StatusOK, "application/json", `"name":"Gopher"`},
+		{"unsupported", "image/png", http.StatusNotAcceptable, "text/plain; charset=utf-8", "supported types: text/plain, application/json, text/html\n"},
+		{"all refused", "*/*;q=0", http.StatusNotAcceptable, "text/plain; charset=utf-8", "supported types:"},
+	}
+	srv, _ := newTestServer(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/hello?name=Gopher", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept" {
+				t.Errorf("Vary = %q, want %q", got, "Accept")
+			}
+			if !strings.Contains(rec.Body.String(), tt.body) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestHelloDefaultsAndEscaping(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/hello?name=%20%20", nil))
+	if got, want := rec.Body.String(), "Hello, World!\n"; got != want {
+		t.Errorf("blank name: body = %q, want %q", got, want)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/hello?name=%3Cscript%3E", nil)
+	req.Header.Set("Accept", "text/html")
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if body := rec.Body.String(); strings.Contains(body, "<script>") || !strings.Contains(body, "&lt;script&gt;") {
+		t.Errorf("html body not escaped: %q", body)
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	srv, _ := newTestServer(t)
+	for _, path := range []string{"/hello", "/healthz"} {
+		for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodDelete} {
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+			if rec.Code != http.StatusMethodNotAllowed {
+				t.Errorf("%s %s: status = %d, want %d", method, path, rec.Code, http.StatusMethodNotAllowed)
+			}
+			if got, want := rec.Header().Get("Allow"), "GET, HEAD"; got != want {
+				t.Errorf("%s %s: Allow = %q, want %q", method, path, got, want)
+			}
+		}
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	srv, _ := newTestServer(t)
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(method, "/healthz", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, want %d", method, rec.Code, http.StatusOK)
+		}
+		if got := rec.Header().Get("Content-Type"); got != "application/json" {
+			t.Errorf("%s: Content-Type = %q, want application/json", method, got)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding body %q: %v", rec.Body.String(), err)
+	}
+	if body["status"] != "ok" {
+		t.Errorf("status field = %q, want ok", body["status"])
+	}
+}
+
+func TestLogRequests(t *testing.T) {
+	srv, logs := newTestServer(t)
+
+	srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/hello?name=Ada", nil))
+	srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/healthz", nil))
+	srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	want := []string{
+		"GET /hello?name=Ada 200 12B ",
+		"POST /healthz 405 19B ",
+		"GET /missing 404 19B ",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d log lines, want %d:\n%s", len(lines), len(want), logs)
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(lines[i], prefix) {
+			t.Errorf("log line %d = %q, want prefix %q", i, lines[i], prefix)
+		}
+	}
+}
+
+func TestLogRequestsEmptyHandler(t *testing.T) {
+	var logs bytes.Buffer
+	h := logRequests(log.New(&logs, "", 0), http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if !strings.HasPrefix(logs.String(), "GET / 200 0B ") {
+		t.Errorf("log = %q, want a 200 with 0 bytes", logs.String())
+	}
+}
+
+// TestServer exercises the service over a real connection.
+func TestServer(t *testing.T) {
+	srv, _ := newTestServer(t)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/hello?name=Gopher", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	var got greeting
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("decoding %q: %v", data, err)
+	}
+	if want := (greeting{Name: "Gopher", Message: "Hello, Gopher!"}); got != want {
+		t.Errorf("body = %+v, want %+v", got, want)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", mediaText},
+		{"   ", mediaText},
+		{"application/json", mediaJSON},
+		{"APPLICATION/JSON", mediaJSON},
+		{"text/*", mediaText},
+		{"text/*, text/plain;q=0.1", mediaHTML},
+		{"application/json;q=0.9, text/html;q=0.9", mediaJSON},
+		{"application/json;q=bogus", mediaJSON},
+		{"application/json;q=2, text/html;q=0.5", mediaJSON},
+		{"image/*", ""},
+	}
+	for _, tt := range tests {
+		if got := negotiate(tt.accept, helloOffers); got != tt.want {
+			t.Errorf("negotiate(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// acceptRange is one entry of an Accept header, e.g. "text/html;q=0.8".
+type acceptRange struct {
+	mediaType string // "text/html", "text/*" or "*/*"
+	quality   float64
+}
+
+// parseAccept splits an Accept header into its media ranges.
+// Ranges with q=0 are kept so they can explicitly exclude a type.
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+		if mediaType == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range fields[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(key) != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q >= 0 && q <= 1 {
+				quality = q
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, quality: quality})
+	}
+	return ranges
+}
+
+// specificity ranks "type/subtype" above "type/*" above "*/*".
+func specificity(mediaType string) int {
+	switch {
+	case mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(mediaType, "/*"):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// matches reports whether the media range covers the concrete media type.
+func (r acceptRange) matches(mediaType string) bool {
+	if r.mediaType == "*/*" || r.mediaType == mediaType {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(r.mediaType, "/*"); ok {
+		return strings.HasPrefix(mediaType, prefix+"/")
+	}
+	return false
+}
+
+// negotiate picks the best of the offered media types for an Accept header.
+// An empty header accepts anything, so the first offer is returned.
+// It returns "" when none of the offers is acceptable.
+func negotiate(header string, offers []string) string {
+	if strings.TrimSpace(header) == "" {
+		return offers[0]
+	}
+
+	best, bestQuality, bestSpecificity := "", 0.0, -1
+	for _, offer := range offers {
+		// The most specific matching range decides an offer's quality (RFC 9110 §12.5.1).
+		quality, spec := 0.0, -1
+		for _, r := range parseAccept(header) {
+			if s := specificity(r.mediaType); r.matches(offer) && s > spec {
+				quality, spec = r.quality, s
+			}
+		}
+		if quality > bestQuality || (quality == bestQuality && quality > 0 && spec > bestSpecificity) {
+			best, bestQuality, bestSpecificity = offer, quality, spec
+		}
+	}
+	return best
+}
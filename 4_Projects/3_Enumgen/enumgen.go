@@ -0,0 +1,283 @@
+// Package main implements enumgen, a stringer-style code generator for
+// integer enums declared with iota.
+//
+// For every named integer type given with -type it writes <type>_enum.go next
+// to the source, containing:
+//   - String() returning the constant's name ("Priority(7)" for unknown values)
+//   - Parse<Type>(string) accepting the name case-insensitively
+//   - MarshalText / UnmarshalText, so the type works with encoding/json and friends
+//   - <Type>Values() listing every declared value in declaration order
+//   - IsValid() reporting whether a value is one of the declared constants
+//
+// Use it from a go:generate directive in the package that declares the enum:
+//
+//	//go:generate go run ../../4_Projects/3_Enumgen/enumgen.go -type=Priority
+//	type Priority int
+//
+//	const (
+//		_ Priority = iota
+//		Low
+//		Medium
+//		High
+//	)
+//
+// Blank (_) constants are skipped, so reserving the zero value keeps it invalid.
+//
+// The tests compare the output for testdata/priority with golden files and
+// compile it to check the generated methods:
+//
+//	go test *.go          # golden files and the generated Parse/UnmarshalText errors
+//	go test *.go -update  # rewrite the golden files after a deliberate change
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// enumValue is one named constant of an enum type.
+type enumValue struct {
+	Name  string // Go identifier, e.g. "Low"
+	Text  string // Text form used by String/Parse, e.g. "Low"
+	Value string // Exact constant value as Go source, e.g. "1"
+	pos   token.Pos
+}
+
+// enum describes one type to generate code for.
+type enum struct {
+	Type     string
+	Receiver string
+	Values   []enumValue
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("enumgen: ")
+
+	typeNames := flag.String("type", "", "comma-separated list of enum type names (required)")
+	dir := flag.String("dir", ".", "directory of the package that declares the types")
+	trimPrefix := flag.String("trimprefix", "", "prefix to strip from constant names in the text form")
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	pkg, err := loadPackage(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, name := range strings.Split(*typeNames, ",") {
+		e, err := findEnum(pkg, strings.TrimSpace(name), *trimPrefix)
+		if err != nil {
+			log.Fatal(err)
+		}
+		src, err := generate(pkg.Name(), e)
+		if err != nil {
+			log.Fatal(err)
+		}
+		out := filepath.Join(*dir, strings.ToLower(e.Type)+"_enum.go")
+		if err := os.WriteFile(out, src, 0o644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Fprintf(os.Stderr, "enumgen: wrote %s (%d values)\n", out, len(e.Values))
+	}
+}
+
+// loadPackage parses and type-checks the non-test Go files in dir.
+// Files previously written by enumgen are skipped so stale output cannot
+// conflict with a changed declaration. Type errors are tolerated: the package
+// may reference methods that only exist once this generator has run.
+func loadPackage(dir string) (*types.Package, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	var pkgName string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") ||
+			strings.HasSuffix(name, "_test.go") || strings.HasSuffix(name, "_enum.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if pkgName != "" && f.Name.Name != pkgName {
+			return nil, fmt.Errorf("expected one package in %s, found %s and %s", dir, pkgName, f.Name.Name)
+		}
+		pkgName = f.Name.Name
+		files = append(files, f)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {}, // Keep going; constants are still evaluated.
+	}
+	pkg, _ := conf.Check(pkgName, fset, files, nil)
+	return pkg, nil
+}
+
+// findEnum collects the package-level constants of the named type in declaration order.
+func findEnum(pkg *types.Package, typeName, trimPrefix string) (enum, error) {
+	obj, ok := pkg.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return enum{}, fmt.Errorf("type %s not found in package %s", typeName, pkg.Name())
+	}
+	basic, ok := obj.Type().Underlying().(*types.Basic)
+	if !ok || basic.Info()&types.IsInteger == 0 {
+		return enum{}, fmt.Errorf("type %s must have an integer underlying type", typeName)
+	}
+
+	e := enum{Type: typeName, Receiver: receiverName(typeName)}
+	for _, name := range pkg.Scope().Names() {
+		c, ok := pkg.Scope().Lookup(name).(*types.Const)
+		if !ok || !types.Identical(c.Type(), obj.Type()) {
+			continue
+		}
+		e.Values = append(e.Values, enumValue{
+			Name:  c.Name(),
+			Text:  strings.TrimPrefix(c.Name(), trimPrefix),
+			Value: c.Val().ExactString(),
+			pos:   c.Pos(),
+		})
+	}
+	if len(e.Values) == 0 {
+		return enum{}, fmt.Errorf("no constants of type %s found", typeName)
+	}
+	sort.Slice(e.Values, func(i, j int) bool { return e.Values[i].pos < e.Values[j].pos })
+	return e, nil
+}
+
+// generate renders and gofmt's the code for one enum.
+func generate(pkgName string, e enum) ([]byte, error) {
+	var b bytes.Buffer
+	p := func(format string, args ...any) { fmt.Fprintf(&b, format+"\n", args...) }
+
+	p("// Code generated by enumgen -type=%s; DO NOT EDIT.", e.Type)
+	p("")
+	p("package %s", pkgName)
+	p("")
+	p(`import (`)
+	p(`	"fmt"`)
+	p(`	"strings"`)
+	p(`)`)
+	p("")
+
+	// String: the first name wins when several constants share a value.
+	p("// String returns the name of the %s constant, or %s(n) for unknown values.", e.Type, e.Type)
+	p("func (%s %s) String() string {", e.Receiver, e.Type)
+	p("	switch %s {", e.Receiver)
+	seen := make(map[string]bool)
+	for _, v := range e.Values {
+		if seen[v.Value] {
+			continue
+		}
+		seen[v.Value] = true
+		p("	case %s:", v.Name)
+		p("		return %q", v.Text)
+	}
+	p("	}")
+	p(`	return fmt.Sprintf("%s(%%d)", %s)`, e.Type, e.Receiver)
+	p("}")
+	p("")
+
+	p("// _%sByName maps lower-cased names to values for Parse%s.", e.Type, e.Type)
+	p("var _%sByName = map[string]%s{", e.Type, e.Type)
+	for _, v := range e.Values {
+		p("	%q: %s,", strings.ToLower(v.Text), v.Name)
+	}
+	p("}")
+	p("")
+
+	p("// Parse%s returns the %s whose name matches s, ignoring case.", e.Type, e.Type)
+	p("func Parse%s(s string) (%s, error) {", e.Type, e.Type)
+	p("	if v, ok := _%sByName[strings.ToLower(strings.TrimSpace(s))]; ok {", e.Type)
+	p("		return v, nil")
+	p("	}")
+	p(`	return 0, fmt.Errorf("invalid %s %%q", s)`, e.Type)
+	p("}")
+	p("")
+
+	p("// %sValues returns every declared %s value in declaration order.", e.Type, e.Type)
+	p("func %sValues() []%s {", e.Type, e.Type)
+	p("	return []%s{", e.Type)
+	for _, v := range e.Values {
+		p("		%s,", v.Name)
+	}
+	p("	}")
+	p("}")
+	p("")
+
+	p("// IsValid reports whether %s is one of the declared %s constants.", e.Receiver, e.Type)
+	p("func (%s %s) IsValid() bool {", e.Receiver, e.Type)
+	p("	switch %s {", e.Receiver)
+	p("	case %s:", joinNames(e.Values))
+	p("		return true")
+	p("	}")
+	p("	return false")
+	p("}")
+	p("")
+
+	p("// MarshalText implements encoding.TextMarshaler; invalid values are rejected.")
+	p("func (%s %s) MarshalText() ([]byte, error) {", e.Receiver, e.Type)
+	p("	if !%s.IsValid() {", e.Receiver)
+	p(`		return nil, fmt.Errorf("invalid %s %%d", %s)`, e.Type, e.Receiver)
+	p("	}")
+	p("	return []byte(%s.String()), nil", e.Receiver)
+	p("}")
+	p("")
+
+	p("// UnmarshalText implements encoding.TextUnmarshaler using Parse%s.", e.Type)
+	p("func (%s *%s) UnmarshalText(text []byte) error {", e.Receiver, e.Type)
+	p("	parsed, err := Parse%s(string(text))", e.Type)
+	p("	if err != nil {")
+	p("		return err")
+	p("	}")
+	p("	*%s = parsed", e.Receiver)
+	p("	return nil")
+	p("}")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w\n%s", err, b.Bytes())
+	}
+	return src, nil
+}
+
+// joinNames lists the distinct constant names for a case clause.
+func joinNames(values []enumValue) string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, v := range values {
+		if !seen[v.Value] {
+			seen[v.Value] = true
+			names = append(names, v.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// receiverName derives a short receiver from the type name: Priority → p.
+func receiverName(typeName string) string {
+	return string(unicode.ToLower([]rune(typeName)[0]))
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the .golden files in testdata")
+
+// testdata/priority declares the enums the tests generate code for.
+const testDir = "testdata/priority"
+
+// generateFor runs the generator on one type in testdata/priority, the way
+// main does for -trimprefix=Level.
+func generateFor(t *testing.T, typeName string) []byte {
+	t.Helper()
+	pkg, err := loadPackage(testDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := findEnum(pkg, typeName, "Level")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generate(pkg.Name(), e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return src
+}
+
+// TestGolden compares the generated code with testdata/priority/<type>_enum.go.golden.
+// Run "go test *.go -update" after a deliberate change to the output, and
+// review the diff.
+func TestGolden(t *testing.T) {
+	for _, typeName := range []string{"Priority", "Level"} {
+		t.Run(typeName, func(t *testing.T) {
+			src := generateFor(t, typeName)
+			golden := filepath.Join(testDir, strings.ToLower(typeName)+"_enum.go.golden")
+			if *update {
+				if err := os.WriteFile(golden, src, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(src) != string(want) {
+				t.Errorf("%s changed; got\n%s\nwant\n%s", golden, src, want)
+			}
+		})
+	}
+}
+
+func TestFindEnumErrors(t *testing.T) {
+	pkg, err := loadPackage(testDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"Missing": "type Missing not found in package main",
+		"Name":    "type Name must have an integer underlying type",
+		"Unused":  "no constants of type Unused found",
+	}
+	for typeName, want := range tests {
+		if _, err := findEnum(pkg, typeName, ""); err == nil || err.Error() != want {
+			t.Errorf("findEnum(%s): err = %v, want %q", typeName, err, want)
+		}
+	}
+}
+
+func TestLoadPackageErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := loadPackage(dir); err == nil {
+		t.Error("empty directory: no error")
+	}
+	for name, src := range map[string]string{"a.go": "package a\n", "b.go": "package b\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := loadPackage(dir); err == nil || !strings.Contains(err.Error(), "expected one package") {
+		t.Errorf("two packages: err = %v, want expected one package", err)
+	}
+}
+
+// TestGeneratedCode compiles the generated code next to testdata/priority/priority.go
+// and checks what its Parse, MarshalText and UnmarshalText methods do,
+// including the errors for unknown names and invalid values.
+func TestGeneratedCode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles the generated code with go run")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not in PATH")
+	}
+	dir := t.TempDir()
+	src, err := os.ReadFile(filepath.Join(testDir, "priority.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		"priority.go":      src,
+		"priority_enum.go": generateFor(t, "Priority"),
+		"level_enum.go":    generateFor(t, "Level"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command("go", "run", "priority.go", "priority_enum.go", "level_enum.go")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run: %v\n%s", err, out)
+	}
+	want := `ParsePriority("high") = 3, <nil>
+ParsePriority(" LOW ") = 1, <nil>
+ParsePriority("urgent") = 3, <nil>
+ParsePriority("") = 0, invalid Priority ""
+ParsePriority("none") = 0, invalid Priority "none"
+ParsePriority("_") = 0, invalid Priority "_"
+ParsePriority("Priority(2)") = 0, invalid Priority "Priority(2)"
+PriorityValues: [Low Medium High High]
+Priority(0): valid false, MarshalText "", invalid Priority 0
+Medium: valid true, MarshalText "Medium", <nil>
+Priority(9): valid false, MarshalText "", invalid Priority 9
+Unmarshal {"p": "medium", "l": "error"} = Medium Error, <nil>
+Unmarshal {"p": "extreme"} = Priority(0) Debug, invalid Priority "extreme"
+Unmarshal {"l": "LevelInfo"} = Priority(0) Debug, invalid Level "LevelInfo"
+Marshal = {"l":"Error"}, <nil>
+Marshal Level(3): invalid Level 3
+`
+	if string(out) != want {
+		t.Errorf("generated code printed\n%s\nwant\n%s", out, want)
+	}
+}
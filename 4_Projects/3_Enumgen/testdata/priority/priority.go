@@ -0,0 +1,61 @@
+// Command priority exercises the code enumgen writes for its two enums:
+// enumgen_test.go generates priority_enum.go next to a copy of this file and
+// compares what it prints.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type Priority int
+
+const (
+	_ Priority = iota
+	Low
+	Medium
+	High
+	Urgent = High // An alias: String prints the first name.
+)
+
+type Level uint8
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelError Level = 10
+)
+
+// Name is not an integer type, so enumgen refuses it.
+type Name string
+
+const Nobody Name = ""
+
+// Unused has no constants.
+type Unused int
+
+func main() {
+	for _, s := range []string{"high", " LOW ", "urgent", "", "none", "_", "Priority(2)"} {
+		p, err := ParsePriority(s)
+		fmt.Printf("ParsePriority(%q) = %d, %v\n", s, p, err)
+	}
+	fmt.Println("PriorityValues:", PriorityValues())
+	for _, p := range []Priority{0, Medium, 9} {
+		text, err := p.MarshalText()
+		fmt.Printf("%v: valid %t, MarshalText %q, %v\n", p, p.IsValid(), text, err)
+	}
+
+	for _, doc := range []string{`{"p": "medium", "l": "error"}`, `{"p": "extreme"}`, `{"l": "LevelInfo"}`} {
+		var v struct {
+			P Priority `json:"p"`
+			L Level    `json:"l"`
+		}
+		err := json.Unmarshal([]byte(doc), &v)
+		fmt.Printf("Unmarshal %s = %v %v, %v\n", doc, v.P, v.L, err)
+	}
+	out, err := json.Marshal(map[string]Level{"l": LevelError})
+	fmt.Printf("Marshal = %s, %v\n", out, err)
+	_, err = json.Marshal(Level(3))
+	fmt.Println("Marshal Level(3):", errors.Unwrap(err))
+}
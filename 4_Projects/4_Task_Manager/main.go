@@ -0,0 +1,217 @@
+// Package main implements a small task manager built on the Low/Medium/High
+// priority levels from the Variables and Constants lesson.
+//
+// Tasks have a priority, an optional due date and a status. A heap-backed
+// scheduler always yields the highest-priority task, breaking ties by the
+// earliest due date, and tasks are kept in a JSON file between runs.
+//
+//	go build -o tasks main.go priority_enum.go scheduler.go status_enum.go store.go task.go
+//	./tasks add -priority high -due 2026-11-01 "Write the release notes"
+//	./tasks add "Water the plants"
+//	./tasks list            # open tasks, most urgent first
+//	./tasks list -all       # include finished tasks
+//	./tasks next            # the single most urgent task
+//	./tasks start 2
+//	./tasks done 1
+//	./tasks rm 2
+//	go test *.go            # scheduler order, overdue dates and the JSON store
+//
+// Use -file to choose the store (default tasks.json in the current directory).
+// Exit codes: 0 on success, 1 on runtime errors, 2 on invalid usage.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// usageError marks errors caused by invalid command-line input (exit code 2).
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usageErrorf(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr, time.Now()))
+}
+
+func run(args []string, stdout, stderr io.Writer, now time.Time) int {
+	flags := flag.NewFlagSet("tasks", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	file := flags.String("file", "tasks.json", "path of the JSON task store")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: tasks [-file path] add|list|next|start|done|rm [args]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	store, err := OpenStore(*file)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+
+	changed, err := dispatch(store, flags.Arg(0), flags.Args()[1:], stdout, stderr, now)
+	if err == nil && changed {
+		err = store.Save()
+	}
+	var usageErr *usageError
+	switch {
+	case errors.As(err, &usageErr):
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	case err != nil:
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	return 0
+}
+
+// dispatch runs one subcommand and reports whether the store was modified.
+func dispatch(store *Store, cmd string, args []string, stdout, stderr io.Writer, now time.Time) (bool, error) {
+	switch cmd {
+	case "add":
+		return true, cmdAdd(store, args, stdout, stderr, now)
+	case "list":
+		return false, cmdList(store, args, stdout, stderr, now)
+	case "next":
+		return false, cmdNext(store, stdout)
+	case "start":
+		return true, setStatus(store, args, InProgress, stdout)
+	case "done":
+		return true, setStatus(store, args, Done, stdout)
+	case "rm":
+		id, err := parseID(args)
+		if err != nil {
+			return false, err
+		}
+		if err := store.Remove(id); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(stdout, "removed #%d\n", id)
+		return true, nil
+	default:
+		return false, usageErrorf("unknown command %q", cmd)
+	}
+}
+
+func cmdAdd(store *Store, args []string, stdout, stderr io.Writer, now time.Time) error {
+	flags := flag.NewFlagSet("add", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	priorityText := flags.String("priority", "medium", "low, medium or high")
+	dueText := flags.String("due", "", "due date as YYYY-MM-DD")
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf("%v", err)
+	}
+
+	title := strings.TrimSpace(strings.Join(flags.Args(), " "))
+	if title == "" {
+		return usageErrorf("add needs a title")
+	}
+	priority, err := ParsePriority(*priorityText)
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+	var due *time.Time
+	if *dueText != "" {
+		d, err := time.ParseInLocation(dateLayout, *dueText, now.Location())
+		if err != nil {
+			return usageErrorf("invalid -due %q, want YYYY-MM-DD", *dueText)
+		}
+		due = &d
+	}
+
+	t := store.Add(title, priority, due, now)
+	fmt.Fprintf(stdout, "added %s\n", t)
+	return nil
+}
+
+func cmdList(store *Store, args []string, stdout, stderr io.Writer, now time.Time) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	all := flags.Bool("all", false, "include finished tasks")
+	if err := flags.Parse(args); err != nil {
+		return usageErrorf("%v", err)
+	}
+
+	// Draining the scheduler lists open tasks in the order they should be done.
+	scheduler := NewScheduler(store.Tasks)
+	for scheduler.Len() > 0 {
+		t, _ := scheduler.Pop()
+		printTask(stdout, t, now)
+	}
+	if *all {
+		for _, t := range store.Tasks {
+			if !t.Open() {
+				printTask(stdout, t, now)
+			}
+		}
+	}
+	return nil
+}
+
+func cmdNext(store *Store, stdout io.Writer) error {
+	t, ok := NewScheduler(store.Tasks).Peek()
+	if !ok {
+		fmt.Fprintln(stdout, "nothing to do")
+		return nil
+	}
+	fmt.Fprintln(stdout, t)
+	return nil
+}
+
+func setStatus(store *Store, args []string, status Status, stdout io.Writer) error {
+	id, err := parseID(args)
+	if err != nil {
+		return err
+	}
+	t, err := store.Get(id)
+	if err != nil {
+		return err
+	}
+	t.Status = status
+	fmt.Fprintln(stdout, *t)
+	return nil
+}
+
+func parseID(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, usageErrorf("expected exactly one task ID")
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+	if err != nil {
+		return 0, usageErrorf("invalid task ID %q", args[0])
+	}
+	return id, nil
+}
+
+// printTask prints one task, flagging overdue ones with a "!".
+func printTask(w io.Writer, t Task, now time.Time) {
+	marker := " "
+	if t.Overdue(now) {
+		marker = "!"
+	}
+	fmt.Fprintf(w, "%s %s\n", marker, t)
+}
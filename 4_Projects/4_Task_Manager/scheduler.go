@@ -0,0 +1,81 @@
+package main
+
+import "container/heap"
+
+// Scheduler always yields the most urgent open task: highest priority first,
+// then the earliest due date (tasks without one come last), then the oldest ID.
+type Scheduler struct {
+	tasks taskHeap
+}
+
+// NewScheduler builds a scheduler from the open tasks in tasks.
+func NewScheduler(tasks []Task) *Scheduler {
+	s := &Scheduler{}
+	for _, t := range tasks {
+		if t.Open() {
+			s.tasks = append(s.tasks, t)
+		}
+	}
+	heap.Init(&s.tasks)
+	return s
+}
+
+// Len returns the number of tasks waiting in the scheduler.
+func (s *Scheduler) Len() int {
+	return s.tasks.Len()
+}
+
+// Push adds a task to the scheduler.
+func (s *Scheduler) Push(t Task) {
+	heap.Push(&s.tasks, t)
+}
+
+// Peek returns the most urgent task without removing it.
+func (s *Scheduler) Peek() (Task, bool) {
+	if s.tasks.Len() == 0 {
+		return Task{}, false
+	}
+	return s.tasks[0], true
+}
+
+// Pop removes and returns the most urgent task.
+func (s *Scheduler) Pop() (Task, bool) {
+	if s.tasks.Len() == 0 {
+		return Task{}, false
+	}
+	return heap.Pop(&s.tasks).(Task), true
+}
+
+// taskHeap implements heap.Interface; the root is the most urgent task.
+type taskHeap []Task
+
+func (h taskHeap) Len() int      { return len(h) }
+func (h taskHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h taskHeap) Less(i, j int) bool {
+	a, b := h[i], h[j]
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	switch {
+	case a.Due != nil && b.Due != nil && !a.Due.Equal(*b.Due):
+		return a.Due.Before(*b.Due)
+	case a.Due != nil && b.Due == nil:
+		return true
+	case a.Due == nil && b.Due != nil:
+		return false
+	}
+	return a.ID < b.ID
+}
+
+func (h *taskHeap) Push(x any) {
+	*h = append(*h, x.(Task))
+}
+
+func (h *taskHeap) Pop() any {
+	old := *h
+	n := len(old)
+	t := old[n-1]
+	*h = old[:n-1]
+	return t
+}
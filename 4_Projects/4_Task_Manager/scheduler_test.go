@@ -0,0 +1,72 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// day returns a pointer to midnight UTC on the given day of November 2026.
+func day(d int) *time.Time {
+	t := time.Date(2026, 11, d, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+// drain pops every task from s and returns their IDs in order.
+func drain(s *Scheduler) []int {
+	var ids []int
+	for s.Len() > 0 {
+		t, _ := s.Pop()
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+func TestSchedulerOrder(t *testing.T) {
+	tasks := []Task{
+		{ID: 1, Priority: Low, Status: Todo, Due: day(1)},
+		{ID: 2, Priority: High, Status: Todo},
+		{ID: 3, Priority: High, Status: Todo, Due: day(5)},
+		{ID: 4, Priority: High, Status: InProgress, Due: day(3)},
+		{ID: 5, Priority: Medium, Status: Todo, Due: day(3)},
+		{ID: 6, Priority: High, Status: Done, Due: day(1)}, // Finished: skipped.
+		{ID: 7, Priority: High, Status: Todo, Due: day(3)},
+		{ID: 8, Priority: Medium, Status: Todo},
+		{ID: 9, Priority: Medium, Status: Todo},
+	}
+	// High before Medium before Low; within a priority the earliest due date,
+	// then tasks without one; equal due dates by ID.
+	want := []int{4, 7, 3, 2, 5, 8, 9, 1}
+
+	s := NewScheduler(tasks)
+	if s.Len() != len(want) {
+		t.Fatalf("Len = %d, want %d open tasks", s.Len(), len(want))
+	}
+	if next, ok := s.Peek(); !ok || next.ID != want[0] {
+		t.Errorf("Peek = #%d, %v; want #%d", next.ID, ok, want[0])
+	}
+	if got := drain(s); !reflect.DeepEqual(got, want) {
+		t.Errorf("order %v, want %v", got, want)
+	}
+
+	// The same tasks pushed in reverse come out in the same order.
+	s = NewScheduler(nil)
+	for i := len(tasks) - 1; i >= 0; i-- {
+		if tasks[i].Open() {
+			s.Push(tasks[i])
+		}
+	}
+	if got := drain(s); !reflect.DeepEqual(got, want) {
+		t.Errorf("after Push: order %v, want %v", got, want)
+	}
+}
+
+func TestSchedulerEmpty(t *testing.T) {
+	s := NewScheduler([]Task{{ID: 1, Status: Done}})
+	if _, ok := s.Peek(); ok {
+		t.Error("Peek on an empty scheduler reported a task")
+	}
+	if _, ok := s.Pop(); ok {
+		t.Error("Pop on an empty scheduler reported a task")
+	}
+}
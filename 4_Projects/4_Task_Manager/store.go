@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrTaskNotFound is returned when no task has the requested ID.
+var ErrTaskNotFound = errors.New("task not found")
+
+// Store keeps tasks in a JSON file so they survive restarts.
+type Store struct {
+	path   string
+	NextID int    `json:"next_id"`
+	Tasks  []Task `json:"tasks"`
+}
+
+// OpenStore loads the store at path. A missing file is an empty store.
+func OpenStore(path string) (*Store, error) {
+	s := &Store{path: path, NextID: 1}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Save writes the store atomically: it writes a temporary file next to the
+// target and renames it, so a crash never leaves a half-written task list.
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once the rename has succeeded.
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// Add creates a new task and returns it.
+func (s *Store) Add(title string, priority Priority, due *time.Time, now time.Time) Task {
+	t := Task{
+		ID:        s.NextID,
+		Title:     title,
+		Priority:  priority,
+		Status:    Todo,
+		Due:       due,
+		CreatedAt: now,
+	}
+	s.NextID++
+	s.Tasks = append(s.Tasks, t)
+	return t
+}
+
+// Get returns a pointer to the task with the given ID so it can be updated in place.
+func (s *Store) Get(id int) (*Task, error) {
+	for i := range s.Tasks {
+		if s.Tasks[i].ID == id {
+			return &s.Tasks[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: #%d", ErrTaskNotFound, id)
+}
+
+// Remove deletes the task with the given ID.
+func (s *Store) Remove(id int) error {
+	for i := range s.Tasks {
+		if s.Tasks[i].ID == id {
+			s.Tasks = append(s.Tasks[:i], s.Tasks[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: #%d", ErrTaskNotFound, id)
+}
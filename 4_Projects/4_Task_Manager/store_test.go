@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// tmpFiles returns the names of leftover temporary files in dir.
+func tmpFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return matches
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	s, err := OpenStore(path)
+	if err != nil {
+		t.Fatalf("OpenStore on a missing file: %v", err)
+	}
+	now := time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC)
+	s.Add("Write the release notes", High, day(1), now)
+	s.Add("Water the plants", Low, nil, now)
+	done, _ := s.Get(2)
+	done.Status = Done
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.NextID != 3 || !reflect.DeepEqual(loaded.Tasks, s.Tasks) {
+		t.Errorf("loaded next ID %d and tasks\n%v\nwant 3 and\n%v", loaded.NextID, loaded.Tasks, s.Tasks)
+	}
+	if t3 := loaded.Add("Third", Medium, nil, now); t3.ID != 3 {
+		t.Errorf("next task after reload got ID %d, want 3", t3.ID)
+	}
+	if left := tmpFiles(t, filepath.Dir(path)); len(left) != 0 {
+		t.Errorf("Save left temporary files: %v", left)
+	}
+}
+
+func TestStoreSaveKeepsOldFileOnError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tasks.json")
+	s, _ := OpenStore(path)
+	s.Add("Keep me", Medium, nil, time.Now())
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An invalid priority fails to marshal, so nothing may be written.
+	s.Add("Broken", Priority(42), nil, time.Now())
+	if err := s.Save(); err == nil {
+		t.Fatal("Save with an invalid priority succeeded")
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(before) {
+		t.Errorf("a failed Save changed the file to\n%s", after)
+	}
+
+	// A rename that fails removes its temporary file.
+	blocked := filepath.Join(dir, "blocked")
+	if err := os.MkdirAll(filepath.Join(blocked, "not-empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	s2 := &Store{path: blocked, NextID: 1}
+	if err := s2.Save(); err == nil {
+		t.Fatal("Save over a directory succeeded")
+	}
+	if left := tmpFiles(t, dir); len(left) != 0 {
+		t.Errorf("a failed Save left temporary files: %v", left)
+	}
+}
+
+func TestStoreErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenStore(path); err == nil {
+		t.Error("OpenStore accepted a corrupt file")
+	}
+
+	s := &Store{NextID: 1}
+	s.Add("Only", Low, nil, time.Now())
+	if _, err := s.Get(2); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Get(2): err = %v, want ErrTaskNotFound", err)
+	}
+	if err := s.Remove(2); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Remove(2): err = %v, want ErrTaskNotFound", err)
+	}
+	if err := s.Remove(1); err != nil || len(s.Tasks) != 0 {
+		t.Errorf("Remove(1) = %v, leaving %v", err, s.Tasks)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Priority orders tasks; higher values are scheduled first. The levels are the
+// Low/Medium/High iota block from the Variables and Constants lesson.
+//
+//go:generate go run ../3_Enumgen/enumgen.go -type=Priority,Status
+type Priority int
+
+const (
+	_ Priority = iota
+	Low
+	Medium
+	High
+)
+
+// Status tracks where a task is in its lifecycle.
+type Status int
+
+const (
+	_ Status = iota
+	Todo
+	InProgress
+	Done
+)
+
+// dateLayout is the format used for due dates on the command line and in listings.
+const dateLayout = "2006-01-02"
+
+// Task is a single unit of work.
+type Task struct {
+	ID        int        `json:"id"`
+	Title     string     `json:"title"`
+	Priority  Priority   `json:"priority"`
+	Status    Status     `json:"status"`
+	Due       *time.Time `json:"due,omitempty"` // nil means no due date.
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Open reports whether the task still needs to be worked on.
+func (t Task) Open() bool {
+	return t.Status != Done
+}
+
+// Overdue reports whether an open task's due date has passed. Due dates are
+// whole days, stored as midnight, so a task due today is not overdue until
+// the next day starts.
+func (t Task) Overdue(now time.Time) bool {
+	return t.Open() && t.Due != nil && !now.Before(t.Due.AddDate(0, 0, 1))
+}
+
+// String formats a task as one line of the list output.
+func (t Task) String() string {
+	due := "-"
+	if t.Due != nil {
+		due = t.Due.Format(dateLayout)
+	}
+	return fmt.Sprintf("#%-3d %-10s %-6s %-10s %s", t.ID, t.Status, t.Priority, due, t.Title)
+}
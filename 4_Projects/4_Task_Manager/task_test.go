@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOverdue(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	due := time.Date(2026, 11, 1, 0, 0, 0, 0, loc)
+	tests := []struct {
+		name   string
+		status Status
+		now    time.Time
+		want   bool
+	}{
+		{"the day before", Todo, time.Date(2026, 10, 31, 23, 59, 0, 0, loc), false},
+		{"start of the due day", Todo, due, false},
+		{"end of the due day", InProgress, time.Date(2026, 11, 1, 23, 59, 59, 0, loc), false},
+		{"the day after", Todo, time.Date(2026, 11, 2, 0, 0, 0, 0, loc), true},
+		{"done tasks are never overdue", Done, time.Date(2026, 12, 1, 0, 0, 0, 0, loc), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := Task{Status: tt.status, Due: &due}
+			if got := task.Overdue(tt.now); got != tt.want {
+				t.Errorf("Overdue(%s) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+	if (Task{Status: Todo}).Overdue(due.AddDate(1, 0, 0)) {
+		t.Error("a task without a due date is overdue")
+	}
+}
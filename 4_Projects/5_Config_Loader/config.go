@@ -0,0 +1,456 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Struct tags understood by the loader:
+//
+//	json:"name"            key in the config file; also used to build field paths ("server.port")
+//	default:"value"        value applied before any other source
+//	env:"NAME"             environment variable (default: <prefix>_<PATH>, e.g. APP_SERVER_PORT)
+//	flag:"name"            command-line flag (default: the dotted path, e.g. -server.port)
+//	required:"true"        the field must not be left at its zero value
+//	enum:"a,b,c"           the value must be one of the listed strings
+//	min:"n" / max:"n"      inclusive bounds for numeric fields
+//
+// Sources are applied in increasing precedence: defaults < file < environment < flags.
+
+// Source names recorded in Loader.Origins.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// FieldError is one problem with one field.
+type FieldError struct {
+	Path    string // Dotted path, e.g. "server.port".
+	Source  string // Where the bad value came from; empty for validation failures.
+	Message string
+}
+
+func (e FieldError) Error() string {
+	if e.Source != "" {
+		return fmt.Sprintf("%s (%s): %s", e.Path, e.Source, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Errors collects every problem found while loading, so they can be fixed in one go.
+type Errors []FieldError
+
+func (errs Errors) Error() string {
+	lines := make([]string, len(errs))
+	for i, e := range errs {
+		lines[i] = "  - " + e.Error()
+	}
+	return fmt.Sprintf("%d configuration problem(s):\n%s", len(errs), strings.Join(lines, "\n"))
+}
+
+// field is one settable leaf of the config struct.
+type field struct {
+	path  string
+	tag   reflect.StructTag
+	value reflect.Value
+}
+
+// Loader fills a config struct from all sources.
+type Loader struct {
+	// EnvPrefix is prepended to derived environment variable names.
+	EnvPrefix string
+	// LookupEnv reads environment variables; it defaults to os.LookupEnv.
+	LookupEnv func(string) (string, bool)
+	// Origins records which source last set each field path.
+	Origins map[string]string
+
+	fields []field
+	flags  map[string]*flagValue
+}
+
+// NewLoader prepares a loader for target, which must be a pointer to a struct.
+func NewLoader(target any, envPrefix string) (*Loader, error) {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("config target must be a pointer to a struct")
+	}
+	l := &Loader{
+		EnvPrefix: envPrefix,
+		LookupEnv: os.LookupEnv,
+		Origins:   make(map[string]string),
+		flags:     make(map[string]*flagValue),
+	}
+	l.fields = collectFields(v.Elem(), "")
+	for _, f := range l.fields {
+		if !supported(f.value) {
+			return nil, fmt.Errorf("%s: unsupported field type %s", f.path, f.value.Type())
+		}
+	}
+	return l, nil
+}
+
+// collectFields walks nested structs and returns their leaf fields.
+func collectFields(v reflect.Value, prefix string) []field {
+	var fields []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		if sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, collectFields(v.Field(i), path)...)
+			continue
+		}
+		fields = append(fields, field{path: path, tag: sf.Tag, value: v.Field(i)})
+	}
+	return fields
+}
+
+// RegisterFlags adds one flag per field to fs. Only flags that are actually
+// passed override the other sources.
+func (l *Loader) RegisterFlags(fs *flag.FlagSet) {
+	for _, f := range l.fields {
+		name := f.tag.Get("flag")
+		if name == "" {
+			name = f.path
+		}
+		fv := &flagValue{isBool: f.value.Kind() == reflect.Bool}
+		l.flags[f.path] = fv
+		usage := fmt.Sprintf("%s (%s)", f.path, f.value.Type())
+		if enum := f.tag.Get("enum"); enum != "" {
+			usage += ", one of: " + enum
+		}
+		fs.Var(fv, name, usage)
+	}
+}
+
+// Load applies every source in order and validates the result. fileData may
+// be nil when there is no config file. All problems are returned together as Errors.
+func (l *Loader) Load(fileData []byte) error {
+	var errs Errors
+
+	// 1. Defaults.
+	for _, f := range l.fields {
+		if def, ok := f.tag.Lookup("default"); ok {
+			errs = l.set(errs, f, def, SourceDefault)
+		}
+	}
+
+	// 2. Config file. Decoding into the struct only overwrites keys that are present.
+	if len(bytes.TrimSpace(fileData)) > 0 {
+		errs = append(errs, l.decodeFile(fileData)...)
+	}
+
+	// 3. Environment variables.
+	for _, f := range l.fields {
+		if value, ok := l.LookupEnv(l.envName(f)); ok {
+			errs = l.set(errs, f, value, SourceEnv)
+		}
+	}
+
+	// 4. Flags.
+	for _, f := range l.fields {
+		if fv := l.flags[f.path]; fv != nil && fv.set {
+			errs = l.set(errs, f, fv.value, SourceFlag)
+		}
+	}
+
+	errs = append(errs, l.validate()...)
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool { return errs[i].Path < errs[j].Path })
+		return errs
+	}
+	return nil
+}
+
+// decodeFile decodes JSON over the current values and records which fields it set.
+func (l *Loader) decodeFile(data []byte) Errors {
+	var errs Errors
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Errors{{Path: "(file)", Source: SourceFile, Message: err.Error()}}
+	}
+
+	known := make(map[string]bool)
+	for _, f := range l.fields {
+		known[f.path] = true
+	}
+	for _, path := range flattenKeys(raw, "") {
+		if !known[path] {
+			errs = append(errs, FieldError{Path: path, Source: SourceFile, Message: "unknown field"})
+		}
+	}
+
+	// Decode field by field so one bad value does not hide problems in the others.
+	for _, f := range l.fields {
+		value, ok := lookupPath(raw, f.path)
+		if !ok {
+			continue
+		}
+		encoded, _ := json.Marshal(value)
+		ptr := reflect.New(f.value.Type())
+		if f.value.Type() == durationType {
+			// Durations are written as strings like "30s" in config files.
+			if s, isString := value.(string); isString {
+				errs = l.set(errs, f, s, SourceFile)
+				continue
+			}
+		}
+		if err := json.Unmarshal(encoded, ptr.Interface()); err != nil {
+			errs = append(errs, FieldError{Path: f.path, Source: SourceFile, Message: fmt.Sprintf("cannot use %s as %s", encoded, f.value.Type())})
+			continue
+		}
+		f.value.Set(ptr.Elem())
+		l.Origins[f.path] = SourceFile
+	}
+	return errs
+}
+
+// validate checks required, enum, min and max tags on the final values.
+func (l *Loader) validate() Errors {
+	var errs Errors
+	for _, f := range l.fields {
+		if f.tag.Get("required") == "true" && f.value.IsZero() {
+			errs = append(errs, FieldError{Path: f.path, Message: "is required"})
+			continue
+		}
+		if enum := f.tag.Get("enum"); enum != "" && !f.value.IsZero() {
+			allowed := strings.Split(enum, ",")
+			got := fmt.Sprint(f.value.Interface())
+			if !contains(allowed, got) {
+				errs = append(errs, FieldError{Path: f.path, Message: fmt.Sprintf("%q is not one of %s", got, strings.Join(allowed, ", "))})
+			}
+		}
+		if n, ok := numeric(f.value); ok {
+			if lo, err := strconv.ParseFloat(f.tag.Get("min"), 64); err == nil && n < lo {
+				errs = append(errs, FieldError{Path: f.path, Message: fmt.Sprintf("must be >= %s", f.tag.Get("min"))})
+			}
+			if hi, err := strconv.ParseFloat(f.tag.Get("max"), 64); err == nil && n > hi {
+				errs = append(errs, FieldError{Path: f.path, Message: fmt.Sprintf("must be <= %s", f.tag.Get("max"))})
+			}
+		}
+	}
+	return errs
+}
+
+// set parses a string value into the field and records its origin.
+func (l *Loader) set(errs Errors, f field, value, source string) Errors {
+	if err := setFromString(f.value, value); err != nil {
+		return append(errs, FieldError{Path: f.path, Source: source, Message: err.Error()})
+	}
+	l.Origins[f.path] = source
+	return errs
+}
+
+// envName returns the environment variable for a field: the env tag, or
+// PREFIX_PATH with dots replaced by underscores.
+func (l *Loader) envName(f field) string {
+	if name := f.tag.Get("env"); name != "" {
+		return name
+	}
+	name := strings.ToUpper(strings.ReplaceAll(f.path, ".", "_"))
+	if l.EnvPrefix != "" {
+		name = l.EnvPrefix + "_" + name
+	}
+	return name
+}
+
+// EnvNames lists the environment variable for every field path, for help output.
+func (l *Loader) EnvNames() map[string]string {
+	names := make(map[string]string, len(l.fields))
+	for _, f := range l.fields {
+		names[f.path] = l.envName(f)
+	}
+	return names
+}
+
+// Resolved returns the loaded values as a config file that Load accepts
+// again: nested objects keyed by the json names, with durations written as
+// strings like "5s" rather than nanoseconds. Keys are sorted.
+func (l *Loader) Resolved() ([]byte, error) {
+	doc := make(map[string]any)
+	for _, f := range l.fields {
+		parts := strings.Split(f.path, ".")
+		obj := doc
+		for _, p := range parts[:len(parts)-1] {
+			nested, ok := obj[p].(map[string]any)
+			if !ok {
+				nested = make(map[string]any)
+				obj[p] = nested
+			}
+			obj = nested
+		}
+		var value any = f.value.Interface()
+		if f.value.Type() == durationType {
+			value = time.Duration(f.value.Int()).String()
+		}
+		obj[parts[len(parts)-1]] = value
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func supported(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return v.Type().Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+// setFromString converts s to the field's type. Slices of strings are comma-separated.
+func setFromString(v reflect.Value, s string) error {
+	s = strings.TrimSpace(s)
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", s)
+		}
+		v.SetInt(int64(d))
+		return nil
+	case v.Kind() == reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", v.Type(), s)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", v.Type(), s)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", v.Type(), s)
+		}
+		v.SetFloat(n)
+	}
+	return nil
+}
+
+func numeric(v reflect.Value) (float64, bool) {
+	if v.Type() == durationType {
+		return 0, false
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// flattenKeys returns the dotted paths of every leaf in a decoded JSON object.
+func flattenKeys(m map[string]any, prefix string) []string {
+	var keys []string
+	for k, v := range m {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]any); ok {
+			keys = append(keys, flattenKeys(nested, path)...)
+			continue
+		}
+		keys = append(keys, path)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lookupPath finds a dotted path in a decoded JSON object.
+func lookupPath(m map[string]any, path string) (any, bool) {
+	parts := strings.Split(path, ".")
+	var cur any = m
+	for _, p := range parts {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = obj[p]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// flagValue is a flag.Value that remembers whether it was set.
+type flagValue struct {
+	value  string
+	set    bool
+	isBool bool
+}
+
+func (f *flagValue) String() string { return f.value }
+
+func (f *flagValue) Set(s string) error {
+	f.value, f.set = s, true
+	return nil
+}
+
+// IsBoolFlag lets boolean fields be passed as "-debug" without "=true".
+func (f *flagValue) IsBoolFlag() bool { return f.isBool }
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loadFrom fills a fresh AppConfig from data, the given environment and
+// command-line args, and returns the error from Load.
+func loadFrom(t *testing.T, data string, env map[string]string, args ...string) (*AppConfig, *Loader, error) {
+	t.Helper()
+	var cfg AppConfig
+	loader, err := NewLoader(&cfg, "APP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	loader.LookupEnv = func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	loader.RegisterFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		t.Fatalf("Parse(%q): %v", args, err)
+	}
+	return &cfg, loader, loader.Load([]byte(data))
+}
+
+// load fills a fresh AppConfig from data with no environment variables.
+func load(t *testing.T, data string) (*AppConfig, *Loader) {
+	t.Helper()
+	cfg, loader, err := loadFrom(t, data, nil)
+	if err != nil {
+		t.Fatalf("Load(%s): %v", data, err)
+	}
+	return cfg, loader
+}
+
+func TestPrecedence(t *testing.T) {
+	const file = `{"env": "dev", "server": {"port": 9090}, "database": {"url": "postgres://file/app"}}`
+	tests := []struct {
+		name       string
+		data       string
+		env        map[string]string
+		args       []string
+		port       int
+		portOrigin string
+		url        string
+		urlOrigin  string
+	}{
+		{"defaults", `{"env": "dev"}`, nil, nil, 8080, SourceDefault, "postgres://localhost/app", SourceDefault},
+		{"file over defaults", file, nil, nil, 9090, SourceFile, "postgres://file/app", SourceFile},
+		{
+			"env over file", file,
+			map[string]string{"APP_SERVER_PORT": "7070", "DATABASE_URL": "postgres://env/app"}, nil,
+			7070, SourceEnv, "postgres://env/app", SourceEnv,
+		},
+		{
+			// The env tag replaces the derived name, so APP_DATABASE_URL is not read.
+			"env tag", file,
+			map[string]string{"APP_DATABASE_URL": "postgres://ignored/app"}, nil,
+			9090, SourceFile, "postgres://file/app", SourceFile,
+		},
+		{
+			"flags over env", file,
+			map[string]string{"APP_SERVER_PORT": "7070", "DATABASE_URL": "postgres://env/app"},
+			[]string{"-server.port", "6060"},
+			6060, SourceFlag, "postgres://env/app", SourceEnv,
+		},
+		{"flags over defaults", `{"env": "dev"}`, nil, []string{"-database.url", "postgres://flag/app"}, 8080, SourceDefault, "postgres://flag/app", SourceFlag},
+	}
+	for _, tt := range tests {
+		cfg, loader, err := loadFrom(t, tt.data, tt.env, tt.args...)
+		if err != nil {
+			t.Errorf("%s: Load: %v", tt.name, err)
+			continue
+		}
+		if cfg.Server.Port != tt.port || loader.Origins["server.port"] != tt.portOrigin {
+			t.Errorf("%s: server.port = %d from %s, want %d from %s",
+				tt.name, cfg.Server.Port, loader.Origins["server.port"], tt.port, tt.portOrigin)
+		}
+		if cfg.Database.URL != tt.url || loader.Origins["database.url"] != tt.urlOrigin {
+			t.Errorf("%s: database.url = %q from %s, want %q from %s",
+				tt.name, cfg.Database.URL, loader.Origins["database.url"], tt.url, tt.urlOrigin)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		env  map[string]string
+		args []string
+		want []string // Every FieldError, in order.
+	}{
+		{"required", `{}`, nil, nil, []string{"env: is required"}},
+		{
+			"enum", `{"env": "qa", "log_level": "loud"}`, nil, nil,
+			[]string{
+				`env: "qa" is not one of dev, staging, prod`,
+				`log_level: "loud" is not one of debug, info, warn, error`,
+			},
+		},
+		{
+			"min and max", `{"env": "dev", "server": {"port": 70000}, "database": {"max_conns": 0}}`, nil, nil,
+			[]string{"database.max_conns: must be >= 1", "server.port: must be <= 65535"},
+		},
+		{
+			// Problems from every source come back together, sorted by path.
+			"all sources",
+			`{"server": {"host": 1, "port": 0}, "nope": true}`,
+			map[string]string{"APP_DEBUG": "maybe"},
+			[]string{"-log_level", "loud", "-server.read_timeout", "soon"},
+			[]string{
+				"debug (env): invalid boolean \"maybe\"",
+				"env: is required",
+				`log_level: "loud" is not one of debug, info, warn, error`,
+				"nope (file): unknown field",
+				"server.host (file): cannot use 1 as string",
+				"server.port: must be >= 1",
+				"server.read_timeout (flag): invalid duration \"soon\"",
+			},
+		},
+	}
+	for _, tt := range tests {
+		_, _, err := loadFrom(t, tt.data, tt.env, tt.args...)
+		var errs Errors
+		if !errors.As(err, &errs) {
+			t.Errorf("%s: Load = %v, want Errors", tt.name, err)
+			continue
+		}
+		got := make([]string, len(errs))
+		for i, e := range errs {
+			got[i] = e.Error()
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Load errors\n  %s\nwant\n  %s", tt.name, strings.Join(got, "\n  "), strings.Join(tt.want, "\n  "))
+		}
+	}
+}
+
+func TestResolvedRoundTrips(t *testing.T) {
+	for _, data := range []string{
+		rawConfig,
+		`{"env": "dev", "features": ["a", "b"], "server": {"port": 9090, "read_timeout": "1m30s"}}`,
+		`{"env": "staging", "server": {"read_timeout": "250ms"}, "database": {"max_conns": 3}}`,
+	} {
+		cfg, loader := load(t, data)
+		out, err := loader.Resolved()
+		if err != nil {
+			t.Fatalf("Resolved: %v", err)
+		}
+		again, _ := load(t, string(out))
+		if !reflect.DeepEqual(cfg, again) {
+			t.Errorf("%s\nresolved to\n%s\nwhich loads as %+v, want %+v", data, out, *again, *cfg)
+		}
+	}
+}
+
+func TestResolvedDurationsAreStrings(t *testing.T) {
+	cfg, loader := load(t, `{"env": "prod", "server": {"read_timeout": "1m30s"}}`)
+	if cfg.Server.ReadTimeout != 90*time.Second {
+		t.Fatalf("ReadTimeout = %v, want 1m30s", cfg.Server.ReadTimeout)
+	}
+	out, err := loader.Resolved()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"read_timeout": "1m30s"`) {
+		t.Errorf("Resolved:\n%s\nwant \"read_timeout\": \"1m30s\"", out)
+	}
+}
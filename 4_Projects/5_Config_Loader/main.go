@@ -0,0 +1,103 @@
+// Package main implements a typed configuration loader for JSON documents
+// like the raw config in SECTION 8 of the Variables and Constants lesson:
+//
+//	{"env": "prod", "debug": false}
+//
+// Values are layered from lowest to highest precedence:
+// struct-tag defaults < config file < environment variables < flags.
+// Required fields, enums and numeric bounds are validated at the end, and every
+// problem is reported at once with its field path.
+//
+//	go run main.go config.go                             # decode the lesson's raw config
+//	go run main.go config.go -config app.json            # read a file instead
+//	APP_SERVER_PORT=9090 go run main.go config.go -debug # env and flags override the file
+//	go run main.go config.go -env qa -server.port 0      # two problems, reported together
+//	go test *.go                                         # precedence, validation and round trips
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// rawConfig is the document from the lesson, used when no -config file is given.
+const rawConfig = `{
+	"env": "prod",
+	"debug": false
+	}`
+
+// AppConfig is the typed shape of the application's configuration.
+type AppConfig struct {
+	Env      string   `json:"env" required:"true" enum:"dev,staging,prod"`
+	Debug    bool     `json:"debug"`
+	LogLevel string   `json:"log_level" default:"info" enum:"debug,info,warn,error"`
+	Features []string `json:"features"`
+	Server   struct {
+		Host        string        `json:"host" default:"0.0.0.0"`
+		Port        int           `json:"port" default:"8080" min:"1" max:"65535"`
+		ReadTimeout time.Duration `json:"read_timeout" default:"5s"`
+	} `json:"server"`
+	Database struct {
+		URL      string `json:"url" env:"DATABASE_URL" default:"postgres://localhost/app"`
+		MaxConns int    `json:"max_conns" default:"10" min:"1"`
+	} `json:"database"`
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	var cfg AppConfig
+	loader, err := NewLoader(&cfg, "APP")
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+
+	flags := flag.NewFlagSet("config", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := flags.String("config", "", "path to a JSON config file (default: the lesson's raw config)")
+	loader.RegisterFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	data := []byte(rawConfig)
+	if *configPath != "" {
+		if data, err = os.ReadFile(*configPath); err != nil {
+			fmt.Fprintln(stderr, "error:", err)
+			return 1
+		}
+	}
+
+	if err := loader.Load(data); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+
+	out, err := loader.Resolved()
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Resolved config:\n%s\n\nOrigins:\n", out)
+	paths := make([]string, 0, len(loader.Origins))
+	for path := range loader.Origins {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	envNames := loader.EnvNames()
+	for _, path := range paths {
+		fmt.Fprintf(stdout, "  %-22s %-8s (env %s)\n", path, loader.Origins[path], envNames[path])
+	}
+	return 0
+}
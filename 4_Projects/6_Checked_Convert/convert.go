@@ -0,0 +1,243 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Number is every built-in integer and floating-point type, including named
+// types built on them (such as time.Duration).
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Sentinel errors wrapped by ConversionError; test for them with errors.Is.
+var (
+	ErrOverflow  = errors.New("value out of range")
+	ErrNegative  = errors.New("negative value for unsigned type")
+	ErrNaN       = errors.New("NaN cannot be converted")
+	ErrInfinity  = errors.New("infinity cannot be converted")
+	ErrTruncated = errors.New("fractional part would be truncated")
+	ErrInexact   = errors.New("value cannot be represented exactly")
+)
+
+// ConversionError describes a failed conversion.
+type ConversionError struct {
+	Value any
+	To    string
+	Err   error
+}
+
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("cannot convert %v (%T) to %s: %v", e.Value, e.Value, e.To, e.Err)
+}
+
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// class groups numeric kinds by how they are converted.
+type class int
+
+const (
+	signed class = iota
+	unsigned
+	floating
+)
+
+// numKind describes a numeric type: its class and width in bits.
+type numKind struct {
+	class class
+	bits  int
+	name  string
+}
+
+func kindOf[N Number]() numKind {
+	t := reflect.TypeOf(N(0))
+	k := numKind{bits: t.Bits(), name: t.String()}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		k.class = signed
+	case reflect.Float32, reflect.Float64:
+		k.class = floating
+	default:
+		k.class = unsigned
+	}
+	return k
+}
+
+// bounds of an integer kind; only meaningful for signed and unsigned classes.
+func (k numKind) minInt() int64   { return -1 << (k.bits - 1) }
+func (k numKind) maxInt() int64   { return 1<<(k.bits-1) - 1 }
+func (k numKind) maxUint() uint64 { return math.MaxUint64 >> (64 - k.bits) }
+
+// To converts v to T, returning an error instead of silently wrapping,
+// truncating or producing an implementation-defined value. Call it with the
+// target type only; the source type is inferred:
+//
+//	b, err := To[uint8](300)         // ErrOverflow
+//	u, err := To[uint](-1)           // ErrNegative
+//	i, err := To[int](2.5)           // ErrTruncated
+//	f, err := To[float64](1<<53 + 1) // ErrInexact
+//
+// Conversions between float types round to the nearest representable value;
+// only results outside the target range are errors.
+func To[T, F Number](v F) (T, error) {
+	from, to := kindOf[F](), kindOf[T]()
+	fail := func(err error) (T, error) {
+		return 0, &ConversionError{Value: v, To: to.name, Err: err}
+	}
+
+	switch from.class {
+	case signed:
+		i := int64(v)
+		switch to.class {
+		case signed:
+			if i < to.minInt() || i > to.maxInt() {
+				return fail(ErrOverflow)
+			}
+		case unsigned:
+			if i < 0 {
+				return fail(ErrNegative)
+			}
+			if uint64(i) > to.maxUint() {
+				return fail(ErrOverflow)
+			}
+		case floating:
+			if !exactFromInt(i, to.bits) {
+				return fail(ErrInexact)
+			}
+		}
+		return T(v), nil
+
+	case unsigned:
+		u := uint64(v)
+		switch to.class {
+		case signed:
+			if u > uint64(to.maxInt()) {
+				return fail(ErrOverflow)
+			}
+		case unsigned:
+			if u > to.maxUint() {
+				return fail(ErrOverflow)
+			}
+		case floating:
+			if !exactFromUint(u, to.bits) {
+				return fail(ErrInexact)
+			}
+		}
+		return T(v), nil
+
+	case floating:
+		// Handled after the switch, starting with NaN and infinities.
+	}
+
+	// Floating-point source.
+	f := float64(v)
+	switch {
+	case math.IsNaN(f):
+		return fail(ErrNaN)
+	case math.IsInf(f, 0):
+		return fail(ErrInfinity)
+	}
+	switch to.class {
+	case floating:
+		if to.bits == 32 && math.IsInf(float64(float32(f)), 0) {
+			return fail(ErrOverflow)
+		}
+		return T(v), nil
+	case signed:
+		// The range is [-2^(bits-1), 2^(bits-1)); both ends are exact in float64.
+		if f < math.Ldexp(-1, to.bits-1) || f >= math.Ldexp(1, to.bits-1) {
+			return fail(ErrOverflow)
+		}
+	case unsigned:
+		// Any negative value, even -0.5, is a sign error rather than a fraction.
+		if f < 0 {
+			return fail(ErrNegative)
+		}
+		if f >= math.Ldexp(1, to.bits) {
+			return fail(ErrOverflow)
+		}
+	}
+	if math.Trunc(f) != f {
+		return fail(ErrTruncated)
+	}
+	return T(v), nil
+}
+
+// ToSaturating is the opt-in lenient mode: instead of failing it clamps to the
+// nearest representable value. Out-of-range values become T's minimum or
+// maximum, fractions are truncated toward zero, and NaN becomes 0.
+func ToSaturating[T, F Number](v F) T {
+	result, err := To[T](v)
+	if err == nil {
+		return result
+	}
+
+	to := kindOf[T]()
+	low, high := saturationBounds[T](to)
+	switch {
+	case errors.Is(err, ErrNaN):
+		return 0
+	case errors.Is(err, ErrNegative):
+		return low
+	case errors.Is(err, ErrTruncated), errors.Is(err, ErrInexact):
+		// Within range: an ordinary conversion already truncates or rounds.
+		return T(v)
+	}
+
+	// ErrOverflow or ErrInfinity: clamp in the direction of the value's sign.
+	if isNegative(v) {
+		return low
+	}
+	return high
+}
+
+// saturationBounds returns the smallest and largest values of T.
+func saturationBounds[T Number](k numKind) (T, T) {
+	switch k.class {
+	case signed:
+		return T(k.minInt()), T(k.maxInt())
+	case unsigned:
+		return 0, T(k.maxUint())
+	case floating:
+		// Handled after the switch.
+	}
+	// Typed variables: untyped float constants cannot convert to the integer types in T's set.
+	limit := math.MaxFloat64
+	if k.bits == 32 {
+		limit = math.MaxFloat32
+	}
+	return T(-limit), T(limit)
+}
+
+func isNegative[F Number](v F) bool {
+	if kindOf[F]().class == unsigned {
+		return false
+	}
+	return float64(v) < 0
+}
+
+// exactFromInt reports whether i survives a round trip through a float of the given width.
+func exactFromInt(i int64, bits int) bool {
+	if bits == 32 {
+		f := float64(float32(i))
+		return f >= -math.Ldexp(1, 63) && f < math.Ldexp(1, 63) && int64(f) == i
+	}
+	f := float64(i)
+	return f < math.Ldexp(1, 63) && int64(f) == i
+}
+
+// exactFromUint reports whether u survives a round trip through a float of the given width.
+func exactFromUint(u uint64, bits int) bool {
+	f := float64(u)
+	if bits == 32 {
+		f = float64(float32(u))
+	}
+	return f < math.Ldexp(1, 64) && uint64(f) == u
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// convCase is one conversion with its expected result. The generic call is
+// wrapped in a closure so cases of different types fit in one table.
+type convCase struct {
+	name    string
+	convert func() (any, error)
+	want    any
+	err     error // nil when the conversion must succeed.
+}
+
+// to builds a case for To[T](v).
+func to[T, F Number](name string, v F, want T, err error) convCase {
+	return convCase{name, func() (any, error) { return To[T](v) }, want, err}
+}
+
+// fails builds a case for To[T](v) that must fail with err.
+func fails[T, F Number](name string, v F, err error) convCase {
+	return to[T](name, v, T(0), err)
+}
+
+// below returns the largest float64 smaller than f.
+func below(f float64) float64 { return math.Nextafter(f, math.Inf(-1)) }
+
+func TestToSignedBoundaries(t *testing.T) {
+	runCases(t, []convCase{
+		to[int8]("int8 min", int64(math.MinInt8), int8(math.MinInt8), nil),
+		to[int8]("int8 max", int64(math.MaxInt8), int8(math.MaxInt8), nil),
+		fails[int8]("int8 min-1", int64(math.MinInt8-1), ErrOverflow),
+		fails[int8]("int8 max+1", int64(math.MaxInt8+1), ErrOverflow),
+
+		to[int16]("int16 min", int64(math.MinInt16), int16(math.MinInt16), nil),
+		to[int16]("int16 max", int64(math.MaxInt16), int16(math.MaxInt16), nil),
+		fails[int16]("int16 min-1", int64(math.MinInt16-1), ErrOverflow),
+		fails[int16]("int16 max+1", int64(math.MaxInt16+1), ErrOverflow),
+
+		to[int32]("int32 min", int64(math.MinInt32), int32(math.MinInt32), nil),
+		to[int32]("int32 max", int64(math.MaxInt32), int32(math.MaxInt32), nil),
+		fails[int32]("int32 min-1", int64(math.MinInt32-1), ErrOverflow),
+		fails[int32]("int32 max+1", int64(math.MaxInt32+1), ErrOverflow),
+
+		// int64 has no wider signed source, so ±1 comes from uint64 and float64.
+		to[int64]("int64 min", int64(math.MinInt64), int64(math.MinInt64), nil),
+		to[int64]("int64 max", int64(math.MaxInt64), int64(math.MaxInt64), nil),
+		fails[int64]("int64 max+1 from uint64", uint64(math.MaxInt64)+1, ErrOverflow),
+		to[int64]("int64 min from float64", float64(math.MinInt64), int64(math.MinInt64), nil),
+		fails[int64]("int64 below min from float64", below(math.MinInt64), ErrOverflow),
+		fails[int64]("int64 2^63 from float64", math.Ldexp(1, 63), ErrOverflow),
+		to[int64]("int64 largest float64 below 2^63", below(math.Ldexp(1, 63)), int64(1<<63-1024), nil),
+
+		to[int]("int max", int64(math.MaxInt), int(math.MaxInt), nil),
+		to[int]("int min", int64(math.MinInt), int(math.MinInt), nil),
+
+		// Narrowing between signed widths in both directions.
+		to[int8]("int8 from int16 -128", int16(-128), int8(-128), nil),
+		fails[int8]("int8 from int16 -129", int16(-129), ErrOverflow),
+		to[int64]("int64 from int8", int8(math.MinInt8), int64(math.MinInt8), nil),
+	})
+}
+
+func TestToUnsignedBoundaries(t *testing.T) {
+	runCases(t, []convCase{
+		to[uint8]("uint8 0", int64(0), uint8(0), nil),
+		to[uint8]("uint8 max", int64(math.MaxUint8), uint8(math.MaxUint8), nil),
+		fails[uint8]("uint8 -1", int64(-1), ErrNegative),
+		fails[uint8]("uint8 max+1", int64(math.MaxUint8+1), ErrOverflow),
+
+		to[uint16]("uint16 max", int64(math.MaxUint16), uint16(math.MaxUint16), nil),
+		fails[uint16]("uint16 -1", int64(-1), ErrNegative),
+		fails[uint16]("uint16 max+1", int64(math.MaxUint16+1), ErrOverflow),
+
+		to[uint32]("uint32 max", int64(math.MaxUint32), uint32(math.MaxUint32), nil),
+		fails[uint32]("uint32 -1", int64(-1), ErrNegative),
+		fails[uint32]("uint32 max+1", int64(math.MaxUint32+1), ErrOverflow),
+		fails[uint32]("uint32 max+1 from uint64", uint64(math.MaxUint32+1), ErrOverflow),
+
+		to[uint64]("uint64 max", uint64(math.MaxUint64), uint64(math.MaxUint64), nil),
+		fails[uint64]("uint64 -1", int64(-1), ErrNegative),
+		fails[uint64]("uint64 2^64 from float64", math.Ldexp(1, 64), ErrOverflow),
+		to[uint64]("uint64 largest float64 below 2^64", below(math.Ldexp(1, 64)), uint64(1<<64-2048), nil),
+
+		to[uint]("uint max", uint64(math.MaxUint), uint(math.MaxUint), nil),
+		fails[uint]("uint -1", -1, ErrNegative),
+		to[uint8]("uint8 from uint16 255", uint16(255), uint8(255), nil),
+		fails[uint8]("uint8 from uint16 256", uint16(256), ErrOverflow),
+	})
+}
+
+func TestToUint64Int64Edges(t *testing.T) {
+	runCases(t, []convCase{
+		to[int64]("uint64 MaxInt64 to int64", uint64(math.MaxInt64), int64(math.MaxInt64), nil),
+		fails[int64]("uint64 MaxInt64+1 to int64", uint64(math.MaxInt64)+1, ErrOverflow),
+		fails[int64]("uint64 max to int64", uint64(math.MaxUint64), ErrOverflow),
+		to[int64]("uint64 0 to int64", uint64(0), int64(0), nil),
+
+		to[uint64]("int64 max to uint64", int64(math.MaxInt64), uint64(math.MaxInt64), nil),
+		to[uint64]("int64 0 to uint64", int64(0), uint64(0), nil),
+		fails[uint64]("int64 -1 to uint64", int64(-1), ErrNegative),
+		fails[uint64]("int64 min to uint64", int64(math.MinInt64), ErrNegative),
+	})
+}
+
+func TestToFloatExactness(t *testing.T) {
+	const p53, p24 = 1 << 53, 1 << 24
+	runCases(t, []convCase{
+		to[float64]("2^53-1 to float64", int64(p53-1), float64(p53-1), nil),
+		to[float64]("2^53 to float64", int64(p53), float64(p53), nil),
+		fails[float64]("2^53+1 to float64", int64(p53+1), ErrInexact),
+		fails[float64]("-(2^53+1) to float64", int64(-(p53 + 1)), ErrInexact),
+		to[float64]("2^53+2 to float64", int64(p53+2), float64(p53+2), nil),
+		fails[float64]("uint64 2^53+1 to float64", uint64(p53+1), ErrInexact),
+		fails[float64]("uint64 max to float64", uint64(math.MaxUint64), ErrInexact),
+		fails[float64]("int64 max to float64", int64(math.MaxInt64), ErrInexact),
+		to[float64]("int64 min to float64", int64(math.MinInt64), float64(math.MinInt64), nil),
+
+		to[float32]("2^24-1 to float32", int64(p24-1), float32(p24-1), nil),
+		to[float32]("2^24 to float32", int64(p24), float32(p24), nil),
+		fails[float32]("2^24+1 to float32", int64(p24+1), ErrInexact),
+		fails[float32]("-(2^24+1) to float32", int32(-(p24 + 1)), ErrInexact),
+		fails[float32]("uint32 2^24+1 to float32", uint32(p24+1), ErrInexact),
+
+		// Between float widths values round; only overflow is an error.
+		to[float32]("float32 max from float64", float64(math.MaxFloat32), float32(math.MaxFloat32), nil),
+		fails[float32]("float32 overflow", 1e39, ErrOverflow),
+		fails[float32]("float32 negative overflow", -1e39, ErrOverflow),
+		to[float32]("0.1 rounds to float32", 0.1, float32(0.1), nil),
+		to[float64]("float32 to float64", float32(0.5), 0.5, nil),
+	})
+}
+
+func TestToSpecialFloats(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	runCases(t, []convCase{
+		fails[int]("NaN to int", nan, ErrNaN),
+		fails[uint8]("NaN to uint8", nan, ErrNaN),
+		fails[float32]("NaN to float32", nan, ErrNaN),
+		fails[int64]("+Inf to int64", inf, ErrInfinity),
+		fails[int64]("-Inf to int64", -inf, ErrInfinity),
+		fails[uint]("+Inf to uint", inf, ErrInfinity),
+		fails[float32]("+Inf to float32", inf, ErrInfinity),
+		fails[int]("float32 NaN to int", float32(nan), ErrNaN),
+		fails[int]("float32 -Inf to int", float32(-inf), ErrInfinity),
+
+		fails[int]("0.5 to int", 0.5, ErrTruncated),
+		fails[int]("-0.5 to int", -0.5, ErrTruncated),
+		fails[uint]("0.5 to uint", 0.5, ErrTruncated),
+		fails[uint]("-0.5 to uint", -0.5, ErrNegative),
+		fails[uint8]("-0.5 to uint8", float32(-0.5), ErrNegative),
+		to[uint]("-0 to uint", math.Copysign(0, -1), uint(0), nil),
+		to[int8]("-128.0 to int8", -128.0, int8(-128), nil),
+		fails[int8]("127.5 to int8", 127.5, ErrTruncated),
+		fails[int8]("128.0 to int8", 128.0, ErrOverflow),
+		fails[int8]("-128.5 to int8", -128.5, ErrOverflow),
+		to[uint8]("255.0 to uint8", 255.0, uint8(255), nil),
+		fails[uint8]("256.0 to uint8", 256.0, ErrOverflow),
+	})
+}
+
+func runCases(t *testing.T, cases []convCase) {
+	t.Helper()
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.convert()
+			if tt.err == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got != tt.want {
+					t.Fatalf("got %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+				}
+				return
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			var convErr *ConversionError
+			if !errors.As(err, &convErr) {
+				t.Fatalf("error %v is not a *ConversionError", err)
+			}
+			if got != tt.want {
+				t.Errorf("failed conversion returned %v, want the zero value", got)
+			}
+		})
+	}
+}
+
+func TestConversionError(t *testing.T) {
+	_, err := To[uint8](300)
+	var convErr *ConversionError
+	if !errors.As(err, &convErr) {
+		t.Fatalf("error %v is not a *ConversionError", err)
+	}
+	if convErr.Value != 300 || convErr.To != "uint8" || convErr.Err != ErrOverflow {
+		t.Errorf("got %+v", *convErr)
+	}
+	if got, want := err.Error(), "cannot convert 300 (int) to uint8: value out of range"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+// saturate builds a case for ToSaturating[T](v); it never fails.
+func saturate[T, F Number](name string, v F, want T) convCase {
+	return convCase{name, func() (any, error) { return ToSaturating[T](v), nil }, want, nil}
+}
+
+func TestToSaturating(t *testing.T) {
+	nan, inf := math.NaN(), math.Inf(1)
+	runCases(t, []convCase{
+		saturate("in range", 42, int8(42)),
+		saturate("uint8 overflow", 300, uint8(math.MaxUint8)),
+		saturate("int8 overflow", 200, int8(math.MaxInt8)),
+		saturate("int8 underflow", -200, int8(math.MinInt8)),
+		saturate("uint negative", -1, uint(0)),
+		saturate("uint -0.5", -0.5, uint(0)),
+		saturate("uint64 from int64 min", int64(math.MinInt64), uint64(0)),
+		saturate("int64 from uint64 max", uint64(math.MaxUint64), int64(math.MaxInt64)),
+		saturate("truncate positive", 2.7, int(2)),
+		saturate("truncate negative", -2.7, int(-2)),
+		saturate("NaN", nan, int(0)),
+		saturate("+Inf to int64", inf, int64(math.MaxInt64)),
+		saturate("-Inf to int64", -inf, int64(math.MinInt64)),
+		saturate("+Inf to uint32", inf, uint32(math.MaxUint32)),
+		saturate("float64 2^63 to int64", math.Ldexp(1, 63), int64(math.MaxInt64)),
+		saturate("float32 overflow", 1e39, float32(math.MaxFloat32)),
+		saturate("float32 negative overflow", -1e39, float32(-math.MaxFloat32)),
+		saturate("inexact rounds", int64(1<<24+1), float32(1<<24)),
+	})
+}
@@ -0,0 +1,175 @@
+// Package main implements checked numeric conversions, following up on the
+// warning in SECTION 6 of the Variables and Constants lesson that uint(floatVal)
+// silently truncates, and on the int8 bounds shown in SECTION 5.
+//
+// To[T](v) converts between any integer and float types and returns an error
+// on overflow, negative-to-unsigned, NaN/Inf, truncated fractions and integers
+// a float cannot hold exactly. ToSaturating[T](v) is the opt-in lenient mode
+// that clamps instead.
+//
+//	go run main.go convert.go                          # boundary-value table for common conversions
+//	go run main.go convert.go -to uint8 255 256 -1 3.5
+//	go run main.go convert.go -to int8 -saturate 1000 -1000 NaN
+//	go test *.go                                       # the boundary tests in convert_test.go
+//
+// The files are named because "go run *.go" would also pick up the test file.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	target := flags.String("to", "", "target type: "+strings.Join(targetNames(), ", "))
+	saturate := flags.Bool("saturate", false, "clamp out-of-range values instead of failing")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	if *target == "" {
+		printBoundaryTable(stdout)
+		return 0
+	}
+	convert, ok := targets[*target]
+	if !ok {
+		fmt.Fprintf(stderr, "error: unknown -to %q\n", *target)
+		return 2
+	}
+
+	status := 0
+	for _, arg := range flags.Args() {
+		result, err := convert(arg, *saturate)
+		if err != nil {
+			fmt.Fprintf(stdout, "%s → %s: error: %v\n", arg, *target, err)
+			status = 1
+			continue
+		}
+		fmt.Fprintf(stdout, "%s → %s: %s\n", arg, *target, result)
+	}
+	return status
+}
+
+// converter converts a number given on the command line to one target type.
+type converter func(input string, saturate bool) (string, error)
+
+var targets = map[string]converter{
+	"int":     convertTo[int],
+	"int8":    convertTo[int8],
+	"int16":   convertTo[int16],
+	"int32":   convertTo[int32],
+	"int64":   convertTo[int64],
+	"uint":    convertTo[uint],
+	"uint8":   convertTo[uint8],
+	"uint16":  convertTo[uint16],
+	"uint32":  convertTo[uint32],
+	"uint64":  convertTo[uint64],
+	"float32": convertTo[float32],
+	"float64": convertTo[float64],
+}
+
+func targetNames() []string {
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// convertTo parses input as the narrowest fitting source type (int64, then
+// uint64, then float64) and converts it to T.
+func convertTo[T Number](input string, saturate bool) (string, error) {
+	if i, err := strconv.ParseInt(input, 0, 64); err == nil {
+		return convertFrom[T](i, saturate)
+	}
+	if u, err := strconv.ParseUint(input, 0, 64); err == nil {
+		return convertFrom[T](u, saturate)
+	}
+	f, err := strconv.ParseFloat(input, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return "", fmt.Errorf("not a number: %q", input)
+	}
+	return convertFrom[T](f, saturate)
+}
+
+func convertFrom[T, F Number](v F, saturate bool) (string, error) {
+	if saturate {
+		return fmt.Sprint(ToSaturating[T](v)), nil
+	}
+	result, err := To[T](v)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(result), nil
+}
+
+// printBoundaryTable shows how To and ToSaturating treat the values at and
+// just past the edges of each type, next to what a plain Go conversion does.
+func printBoundaryTable(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONVERSION\tPLAIN GO\tTo\tToSaturating")
+	row := func(label string, plain any, result any, err error, saturated any) {
+		checked := fmt.Sprint(result)
+		if err != nil {
+			checked = "error: " + errors.Unwrap(err).Error()
+		}
+		fmt.Fprintf(tw, "%s\t%v\t%s\t%v\n", label, plain, checked, saturated)
+	}
+
+	for _, v := range []int64{-129, -128, 127, 128} {
+		r, err := To[int8](v)
+		row(fmt.Sprintf("int8(%d)", v), int8(v), r, err, ToSaturating[int8](v))
+	}
+	for _, v := range []int64{-1, 0, 255, 256} {
+		r, err := To[uint8](v)
+		row(fmt.Sprintf("uint8(%d)", v), uint8(v), r, err, ToSaturating[uint8](v))
+	}
+	for _, v := range []uint64{math.MaxInt64, math.MaxInt64 + 1, math.MaxUint64} {
+		r, err := To[int64](v)
+		row(fmt.Sprintf("int64(uint64 %d)", v), int64(v), r, err, ToSaturating[int64](v))
+	}
+	for _, v := range []float64{42, 42.9, -0.5, -1} {
+		r, err := To[uint](v)
+		row(fmt.Sprintf("uint(%v)", v), plainFloatToUint(v), r, err, ToSaturating[uint](v))
+	}
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1), 1e19} {
+		r, err := To[int64](v)
+		row(fmt.Sprintf("int64(%v)", v), "implementation-defined", r, err, ToSaturating[int64](v))
+	}
+	for _, v := range []int64{1 << 53, 1<<53 + 1} {
+		r, err := To[float64](v)
+		row(fmt.Sprintf("float64(%d)", v), fmt.Sprintf("%.0f", float64(v)), fmt.Sprintf("%.0f", r), err, fmt.Sprintf("%.0f", ToSaturating[float64](v)))
+	}
+	for _, v := range []float64{math.MaxFloat32, 1e39} {
+		r, err := To[float32](v)
+		row(fmt.Sprintf("float32(%g)", v), float32(v), r, err, ToSaturating[float32](v))
+	}
+	tw.Flush()
+}
+
+// plainFloatToUint shows what uint(f) yields for in-range values; negative
+// floats are implementation-defined in Go, so they are not converted here.
+func plainFloatToUint(f float64) any {
+	if f < 0 {
+		return "implementation-defined"
+	}
+	return uint(f)
+}
@@ -0,0 +1,198 @@
+package main
+
+import "unicode"
+
+// gcbProperty is the Grapheme_Cluster_Break property of a code point (UAX #29).
+type gcbProperty int
+
+const (
+	gcbOther gcbProperty = iota
+	gcbCR
+	gcbLF
+	gcbControl
+	gcbExtend
+	gcbZWJ
+	gcbRegionalIndicator
+	gcbPrepend
+	gcbSpacingMark
+	gcbL
+	gcbV
+	gcbT
+	gcbLV
+	gcbLVT
+)
+
+// prepend lists the Prepend code points (mostly Arabic and Brahmic signs that attach forward).
+var prepend = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x0600, Hi: 0x0605, Stride: 1},
+		{Lo: 0x06DD, Hi: 0x06DD, Stride: 1},
+		{Lo: 0x070F, Hi: 0x070F, Stride: 1},
+		{Lo: 0x0890, Hi: 0x0891, Stride: 1},
+		{Lo: 0x08E2, Hi: 0x08E2, Stride: 1},
+		{Lo: 0x0D4E, Hi: 0x0D4E, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x110BD, Hi: 0x110BD, Stride: 1},
+		{Lo: 0x110CD, Hi: 0x110CD, Stride: 1},
+		{Lo: 0x111C2, Hi: 0x111C3, Stride: 1},
+		{Lo: 0x1193F, Hi: 0x1193F, Stride: 1},
+		{Lo: 0x11941, Hi: 0x11941, Stride: 1},
+		{Lo: 0x11A3A, Hi: 0x11A3A, Stride: 1},
+		{Lo: 0x11A84, Hi: 0x11A89, Stride: 1},
+		{Lo: 0x11D46, Hi: 0x11D46, Stride: 1},
+	},
+}
+
+// extendedPictographic approximates the Extended_Pictographic property from
+// emoji-data.txt, which the standard library does not ship: the emoji and
+// symbol blocks plus the handful of older symbols that have emoji forms.
+var extendedPictographic = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x00A9, Hi: 0x00A9, Stride: 1},
+		{Lo: 0x00AE, Hi: 0x00AE, Stride: 1},
+		{Lo: 0x203C, Hi: 0x203C, Stride: 1},
+		{Lo: 0x2049, Hi: 0x2049, Stride: 1},
+		{Lo: 0x2122, Hi: 0x2122, Stride: 1},
+		{Lo: 0x2139, Hi: 0x2139, Stride: 1},
+		{Lo: 0x2194, Hi: 0x2199, Stride: 1},
+		{Lo: 0x21A9, Hi: 0x21AA, Stride: 1},
+		{Lo: 0x231A, Hi: 0x231B, Stride: 1},
+		{Lo: 0x2328, Hi: 0x2328, Stride: 1},
+		{Lo: 0x23CF, Hi: 0x23CF, Stride: 1},
+		{Lo: 0x23E9, Hi: 0x23F3, Stride: 1},
+		{Lo: 0x23F8, Hi: 0x23FA, Stride: 1},
+		{Lo: 0x24C2, Hi: 0x24C2, Stride: 1},
+		{Lo: 0x25AA, Hi: 0x25AB, Stride: 1},
+		{Lo: 0x25B6, Hi: 0x25B6, Stride: 1},
+		{Lo: 0x25C0, Hi: 0x25C0, Stride: 1},
+		{Lo: 0x25FB, Hi: 0x25FE, Stride: 1},
+		{Lo: 0x2600, Hi: 0x27BF, Stride: 1},
+		{Lo: 0x2934, Hi: 0x2935, Stride: 1},
+		{Lo: 0x2B05, Hi: 0x2B07, Stride: 1},
+		{Lo: 0x2B1B, Hi: 0x2B1C, Stride: 1},
+		{Lo: 0x2B50, Hi: 0x2B50, Stride: 1},
+		{Lo: 0x2B55, Hi: 0x2B55, Stride: 1},
+		{Lo: 0x3030, Hi: 0x3030, Stride: 1},
+		{Lo: 0x303D, Hi: 0x303D, Stride: 1},
+		{Lo: 0x3297, Hi: 0x3297, Stride: 1},
+		{Lo: 0x3299, Hi: 0x3299, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1F000, Hi: 0x1F1E5, Stride: 1},
+		{Lo: 0x1F200, Hi: 0x1F3FA, Stride: 1},
+		{Lo: 0x1F400, Hi: 0x1FAFF, Stride: 1},
+		{Lo: 0x1FC00, Hi: 0x1FFFD, Stride: 1},
+	},
+}
+
+// emojiModifier is the Fitzpatrick skin tone block; UAX #29 treats it as Extend.
+var emojiModifier = &unicode.RangeTable{
+	R32: []unicode.Range32{{Lo: 0x1F3FB, Hi: 0x1F3FF, Stride: 1}},
+}
+
+// graphemeBreak returns the Grapheme_Cluster_Break property of r.
+func graphemeBreak(r rune) gcbProperty {
+	switch {
+	case r == '\r':
+		return gcbCR
+	case r == '\n':
+		return gcbLF
+	case r == 0x200D:
+		return gcbZWJ
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return gcbRegionalIndicator
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return gcbL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return gcbV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return gcbT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return gcbLV
+		}
+		return gcbLVT
+	case unicode.Is(prepend, r):
+		return gcbPrepend
+	case r == 0x200C, unicode.In(r, unicode.Mn, unicode.Me, unicode.Other_Grapheme_Extend, emojiModifier):
+		return gcbExtend
+	case unicode.In(r, unicode.Cc, unicode.Zl, unicode.Zp, unicode.Cf):
+		return gcbControl
+	case unicode.Is(unicode.Mc, r), r == 0x0E33, r == 0x0EB3:
+		return gcbSpacingMark
+	}
+	return gcbOther
+}
+
+// Graphemes splits s into extended grapheme clusters following the UAX #29
+// boundary rules GB3–GB13 (the Indic conjunct rule GB9c is not implemented).
+// Invalid UTF-8 bytes are treated as U+FFFD, like a range loop does.
+func Graphemes(s string) []string {
+	var clusters []string
+	start := 0
+	var prev gcbProperty
+	riCount := 0            // Regional indicators in the current run (GB12/GB13).
+	inPictographic := false // Inside Extended_Pictographic Extend* (GB11).
+	lastWasZWJAfterPict := false
+
+	for i, r := range s {
+		prop := graphemeBreak(r)
+		if i > 0 && isBoundary(prev, prop, riCount, lastWasZWJAfterPict, r) {
+			clusters = append(clusters, s[start:i])
+			start = i
+			riCount = 0
+		}
+
+		// Track state for GB11: ExtPict Extend* ZWJ × ExtPict.
+		switch {
+		case unicode.Is(extendedPictographic, r):
+			inPictographic, lastWasZWJAfterPict = true, false
+		case prop == gcbExtend && inPictographic:
+			lastWasZWJAfterPict = false
+		case prop == gcbZWJ && inPictographic:
+			lastWasZWJAfterPict = true
+		default:
+			inPictographic, lastWasZWJAfterPict = false, false
+		}
+		if prop == gcbRegionalIndicator {
+			riCount++
+		} else {
+			riCount = 0
+		}
+		prev = prop
+	}
+	if start < len(s) {
+		clusters = append(clusters, s[start:])
+	}
+	return clusters
+}
+
+// isBoundary applies the pair rules between the previous and the current code point.
+func isBoundary(prev, cur gcbProperty, riCount int, zwjAfterPict bool, r rune) bool {
+	switch {
+	case prev == gcbCR && cur == gcbLF: // GB3
+		return false
+	case prev == gcbCR || prev == gcbLF || prev == gcbControl: // GB4
+		return true
+	case cur == gcbCR || cur == gcbLF || cur == gcbControl: // GB5
+		return true
+	case prev == gcbL && (cur == gcbL || cur == gcbV || cur == gcbLV || cur == gcbLVT): // GB6
+		return false
+	case (prev == gcbLV || prev == gcbV) && (cur == gcbV || cur == gcbT): // GB7
+		return false
+	case (prev == gcbLVT || prev == gcbT) && cur == gcbT: // GB8
+		return false
+	case cur == gcbExtend || cur == gcbZWJ: // GB9
+		return false
+	case cur == gcbSpacingMark: // GB9a
+		return false
+	case prev == gcbPrepend: // GB9b
+		return false
+	case zwjAfterPict && unicode.Is(extendedPictographic, r): // GB11
+		return false
+	case prev == gcbRegionalIndicator && cur == gcbRegionalIndicator: // GB12, GB13
+		return riCount%2 == 0
+	}
+	return true // GB999
+}
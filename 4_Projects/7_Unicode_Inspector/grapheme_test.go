@@ -0,0 +1,91 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraphemes(t *testing.T) {
+	tests := []struct {
+		rule  string
+		input string
+		want  []string
+	}{
+		{"empty", "", nil},
+		{"GB999", "ab", []string{"a", "b"}},
+		{"GB3 CR LF", "a\r\nb", []string{"a", "\r\n", "b"}},
+		{"GB4 after LF", "\n\u0301", []string{"\n", "\u0301"}},
+		{"GB5 before control", "a\tb", []string{"a", "\t", "b"}},
+
+		// Hangul: conjoining jamo and precomposed syllables.
+		{"GB6 L V", "\u1100\u1161", []string{"\u1100\u1161"}},
+		{"GB6 L L LV", "\u1100\u1100\uAC00", []string{"\u1100\u1100\uAC00"}},
+		{"GB7 LV T", "\uAC00\u11A8", []string{"\uAC00\u11A8"}},
+		{"GB7 V V T", "\u1100\u1161\u1162\u11A8", []string{"\u1100\u1161\u1162\u11A8"}},
+		{"GB8 LVT T", "\uAC01\u11A8", []string{"\uAC01\u11A8"}},
+		{"syllable syllable", "\uD55C\uAD6D", []string{"\uD55C", "\uAD6D"}},
+		{"T L", "\u11A8\u1100", []string{"\u11A8", "\u1100"}},
+
+		// Combining marks.
+		{"GB9 combining accent", "e\u0301x", []string{"e\u0301", "x"}},
+		{"GB9 stacked marks", "e\u0301\u0308", []string{"e\u0301\u0308"}},
+		{"GB9 ZWJ", "a\u200Db", []string{"a\u200D", "b"}},
+		{"GB9 enclosing mark", "1\u20DD", []string{"1\u20DD"}},
+		{"GB9 skin tone", "\U0001F44D\U0001F3FD", []string{"\U0001F44D\U0001F3FD"}},
+		{"GB9a spacing mark", "\u0915\u093F", []string{"\u0915\u093F"}},
+		{"GB9b prepend", "\u0600\u0661", []string{"\u0600\u0661"}},
+
+		// ZWJ emoji sequences.
+		{"GB11 ZWJ sequence", "\U0001F469\u200D\U0001F4BB", []string{"\U0001F469\u200D\U0001F4BB"}},
+		{"GB11 family", "\U0001F468\u200D\U0001F469\u200D\U0001F467", []string{"\U0001F468\u200D\U0001F469\u200D\U0001F467"}},
+		{"GB11 with modifier", "\U0001F469\U0001F3FD\u200D\U0001F4BB", []string{"\U0001F469\U0001F3FD\u200D\U0001F4BB"}},
+		{"GB11 VS16 before ZWJ", "\u2764\uFE0F\u200D\U0001F525", []string{"\u2764\uFE0F\u200D\U0001F525"}},
+		{"GB11 needs a pictograph first", "a\u200D\U0001F4BB", []string{"a\u200D", "\U0001F4BB"}},
+		{"emoji emoji", "\U0001F680\U0001F680", []string{"\U0001F680", "\U0001F680"}},
+
+		// Regional indicator pairs: DE, FR.
+		{"GB12 flag", "\U0001F1E9\U0001F1EA", []string{"\U0001F1E9\U0001F1EA"}},
+		{"GB13 two flags", "\U0001F1E9\U0001F1EA\U0001F1EB\U0001F1F7", []string{"\U0001F1E9\U0001F1EA", "\U0001F1EB\U0001F1F7"}},
+		{"GB13 odd indicator", "\U0001F1E9\U0001F1EA\U0001F1EB", []string{"\U0001F1E9\U0001F1EA", "\U0001F1EB"}},
+		{"GB13 after text", "a\U0001F1E9\U0001F1EA", []string{"a", "\U0001F1E9\U0001F1EA"}},
+		{"GB13 run restarts", "\U0001F1E9\U0001F1EAx\U0001F1EB\U0001F1F7", []string{"\U0001F1E9\U0001F1EA", "x", "\U0001F1EB\U0001F1F7"}},
+
+		{"invalid UTF-8", "\xffa", []string{"\xff", "a"}},
+	}
+	for _, tt := range tests {
+		if got := Graphemes(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Graphemes(%+q) = %+q, want %+q", tt.rule, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestGraphemeBreak(t *testing.T) {
+	tests := []struct {
+		r    rune
+		want gcbProperty
+	}{
+		{'\r', gcbCR},
+		{'\n', gcbLF},
+		{0x0000, gcbControl},
+		{0x200B, gcbControl},
+		{0x200D, gcbZWJ},
+		{0x200C, gcbExtend},
+		{0x0301, gcbExtend},
+		{0x1F3FB, gcbExtend},
+		{0x1F1E6, gcbRegionalIndicator},
+		{0x0600, gcbPrepend},
+		{0x093F, gcbSpacingMark},
+		{0x1100, gcbL},
+		{0x1161, gcbV},
+		{0x11A8, gcbT},
+		{0xAC00, gcbLV},
+		{0xAC01, gcbLVT},
+		{'a', gcbOther},
+		{0x1F680, gcbOther},
+	}
+	for _, tt := range tests {
+		if got := graphemeBreak(tt.r); got != tt.want {
+			t.Errorf("graphemeBreak(%U) = %d, want %d", tt.r, got, tt.want)
+		}
+	}
+}
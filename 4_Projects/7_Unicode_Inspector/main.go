@@ -0,0 +1,166 @@
+// Package main implements a Unicode text inspector, extending the rune/byte
+// discussion in SECTION 5 of the Variables and Constants lesson.
+//
+// []rune("🚀") has length 1, but real text quickly breaks that intuition:
+// "👩‍💻" is three runes joined by ZERO WIDTH JOINER, "é" may be "e" plus a
+// combining accent, and "🇮🇳" is two regional indicators. The inspector reports
+// the four different "lengths" of a string — bytes, runes, user-perceived
+// characters (grapheme clusters, UAX #29) and terminal display width — and
+// lists every code point with its category and name.
+//
+//	go run main.go grapheme.go width.go names.go "🚀"
+//	go run main.go grapheme.go width.go names.go "👩‍💻 é 🇮🇳 한국어"
+//	echo "Hello, 世界" | go run main.go grapheme.go width.go names.go
+//	go run main.go grapheme.go width.go names.go -json "👍🏽"
+//	go test *.go
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Report is the full analysis of one input string.
+type Report struct {
+	Text      string        `json:"text"`
+	Bytes     int           `json:"bytes"`
+	Runes     int           `json:"runes"`
+	Graphemes int           `json:"graphemes"`
+	Width     int           `json:"width"`
+	ValidUTF8 bool          `json:"valid_utf8"`
+	Clusters  []ClusterInfo `json:"clusters"`
+}
+
+// ClusterInfo describes one grapheme cluster and the code points inside it.
+type ClusterInfo struct {
+	Text       string      `json:"text"`
+	Offset     int         `json:"offset"` // Byte offset in the input.
+	Width      int         `json:"width"`
+	CodePoints []CodePoint `json:"code_points"`
+}
+
+// CodePoint describes a single rune.
+type CodePoint struct {
+	Rune     string `json:"rune"` // "U+1F680"
+	UTF8     string `json:"utf8"` // "F0 9F 9A 80"
+	Category string `json:"category"`
+	Name     string `json:"name,omitempty"`
+}
+
+// Inspect analyzes s.
+func Inspect(s string) Report {
+	rep := Report{
+		Text:      s,
+		Bytes:     len(s),
+		Runes:     utf8.RuneCountInString(s),
+		ValidUTF8: utf8.ValidString(s),
+	}
+	offset := 0
+	for _, cluster := range Graphemes(s) {
+		info := ClusterInfo{Text: cluster, Offset: offset, Width: ClusterWidth(cluster)}
+		for i, r := range cluster {
+			size := utf8.RuneLen(r)
+			if r == utf8.RuneError {
+				_, size = utf8.DecodeRuneInString(cluster[i:])
+			}
+			info.CodePoints = append(info.CodePoints, CodePoint{
+				Rune:     fmt.Sprintf("%U", r),
+				UTF8:     fmt.Sprintf("% X", cluster[i:i+size]),
+				Category: Category(r),
+				Name:     Name(r),
+			})
+		}
+		rep.Clusters = append(rep.Clusters, info)
+		rep.Width += info.Width
+		offset += len(cluster)
+	}
+	rep.Graphemes = len(rep.Clusters)
+	return rep
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("unicode-inspector", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "print the report as JSON")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	// Each argument is inspected separately; without arguments, each line of stdin is.
+	inputs := flags.Args()
+	if len(inputs) == 0 {
+		scanner := bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			inputs = append(inputs, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			fmt.Fprintln(stderr, "error: reading stdin:", err)
+			return 1
+		}
+	}
+
+	for i, input := range inputs {
+		rep := Inspect(input)
+		if *asJSON {
+			enc := json.NewEncoder(stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(rep); err != nil {
+				fmt.Fprintln(stderr, "error:", err)
+				return 1
+			}
+			continue
+		}
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		printReport(stdout, rep)
+	}
+	return 0
+}
+
+// printReport writes the summary line and one table row per code point.
+func printReport(w io.Writer, rep Report) {
+	fmt.Fprintf(w, "%q\n", rep.Text)
+	fmt.Fprintf(w, "bytes: %d, runes: %d, graphemes: %d, width: %d", rep.Bytes, rep.Runes, rep.Graphemes, rep.Width)
+	if !rep.ValidUTF8 {
+		fmt.Fprint(w, " (contains invalid UTF-8)")
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "#\tCLUSTER\tCODE POINT\tUTF-8\tCATEGORY\tNAME")
+	for n, c := range rep.Clusters {
+		for i, cp := range c.CodePoints {
+			label, text := "", ""
+			if i == 0 {
+				label, text = fmt.Sprint(n+1), printable(c.Text)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s %s\t%s\n", label, text, cp.Rune, cp.UTF8, cp.Category, CategoryName(cp.Category), cp.Name)
+		}
+	}
+	tw.Flush()
+}
+
+// printable quotes clusters that would otherwise be invisible or break the table.
+func printable(cluster string) string {
+	if !utf8.ValidString(cluster) || ClusterWidth(cluster) == 0 || strings.IndexFunc(cluster, unicode.IsSpace) >= 0 {
+		return fmt.Sprintf("%q", cluster)
+	}
+	return cluster
+}
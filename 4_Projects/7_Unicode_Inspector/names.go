@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// categoryNames gives the long form of each two-letter general category.
+var categoryNames = map[string]string{
+	"Lu": "Uppercase Letter", "Ll": "Lowercase Letter", "Lt": "Titlecase Letter",
+	"Lm": "Modifier Letter", "Lo": "Other Letter",
+	"Mn": "Nonspacing Mark", "Mc": "Spacing Mark", "Me": "Enclosing Mark",
+	"Nd": "Decimal Number", "Nl": "Letter Number", "No": "Other Number",
+	"Pc": "Connector Punctuation", "Pd": "Dash Punctuation", "Ps": "Open Punctuation",
+	"Pe": "Close Punctuation", "Pi": "Initial Punctuation", "Pf": "Final Punctuation",
+	"Po": "Other Punctuation",
+	"Sm": "Math Symbol", "Sc": "Currency Symbol", "Sk": "Modifier Symbol", "So": "Other Symbol",
+	"Zs": "Space Separator", "Zl": "Line Separator", "Zp": "Paragraph Separator",
+	"Cc": "Control", "Cf": "Format", "Co": "Private Use", "Cs": "Surrogate",
+}
+
+// categoryOrder lists the two-letter categories in a stable order for lookups.
+var categoryOrder = func() []string {
+	codes := make([]string, 0, len(categoryNames))
+	for code := range categoryNames {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}()
+
+// Category returns the general category of r, e.g. "Lu", or "Cn" (unassigned).
+func Category(r rune) string {
+	for _, code := range categoryOrder {
+		if table, ok := unicode.Categories[code]; ok && unicode.Is(table, r) {
+			return code
+		}
+	}
+	return "Cn"
+}
+
+// CategoryName returns the long name of a two-letter category.
+func CategoryName(code string) string {
+	if name, ok := categoryNames[code]; ok {
+		return name
+	}
+	return "Unassigned"
+}
+
+// knownNames holds character names for code points that are common in
+// examples but cannot be derived algorithmically. The standard library has no
+// Unicode name database, so this is intentionally a small subset.
+var knownNames = map[rune]string{
+	0x0009: "CHARACTER TABULATION", 0x000A: "LINE FEED", 0x000D: "CARRIAGE RETURN",
+	0x0020: "SPACE", 0x0021: "EXCLAMATION MARK", 0x0022: "QUOTATION MARK",
+	0x0023: "NUMBER SIGN", 0x0024: "DOLLAR SIGN", 0x0025: "PERCENT SIGN",
+	0x0026: "AMPERSAND", 0x0027: "APOSTROPHE", 0x0028: "LEFT PARENTHESIS",
+	0x0029: "RIGHT PARENTHESIS", 0x002A: "ASTERISK", 0x002B: "PLUS SIGN",
+	0x002C: "COMMA", 0x002D: "HYPHEN-MINUS", 0x002E: "FULL STOP", 0x002F: "SOLIDUS",
+	0x003A: "COLON", 0x003B: "SEMICOLON", 0x003C: "LESS-THAN SIGN", 0x003D: "EQUALS SIGN",
+	0x003E: "GREATER-THAN SIGN", 0x003F: "QUESTION MARK", 0x0040: "COMMERCIAL AT",
+	0x005B: "LEFT SQUARE BRACKET", 0x005C: "REVERSE SOLIDUS", 0x005D: "RIGHT SQUARE BRACKET",
+	0x005E: "CIRCUMFLEX ACCENT", 0x005F: "LOW LINE", 0x0060: "GRAVE ACCENT",
+	0x007B: "LEFT CURLY BRACKET", 0x007C: "VERTICAL LINE", 0x007D: "RIGHT CURLY BRACKET",
+	0x007E: "TILDE", 0x00A0: "NO-BREAK SPACE", 0x00A9: "COPYRIGHT SIGN",
+	0x00AD: "SOFT HYPHEN", 0x00AE: "REGISTERED SIGN", 0x00E9: "LATIN SMALL LETTER E WITH ACUTE",
+	0x00C9: "LATIN CAPITAL LETTER E WITH ACUTE", 0x00F1: "LATIN SMALL LETTER N WITH TILDE",
+	0x00FC: "LATIN SMALL LETTER U WITH DIAERESIS",
+	0x0300: "COMBINING GRAVE ACCENT", 0x0301: "COMBINING ACUTE ACCENT",
+	0x0302: "COMBINING CIRCUMFLEX ACCENT", 0x0303: "COMBINING TILDE",
+	0x0308: "COMBINING DIAERESIS", 0x20DD: "COMBINING ENCLOSING CIRCLE",
+	0x200B: "ZERO WIDTH SPACE", 0x200C: "ZERO WIDTH NON-JOINER", 0x200D: "ZERO WIDTH JOINER",
+	0x2013: "EN DASH", 0x2014: "EM DASH", 0x2018: "LEFT SINGLE QUOTATION MARK",
+	0x2019: "RIGHT SINGLE QUOTATION MARK", 0x201C: "LEFT DOUBLE QUOTATION MARK",
+	0x201D: "RIGHT DOUBLE QUOTATION MARK", 0x2026: "HORIZONTAL ELLIPSIS",
+	0x20AC: "EURO SIGN", 0x2122: "TRADE MARK SIGN", 0x2640: "FEMALE SIGN",
+	0x2642: "MALE SIGN", 0x2695: "STAFF OF AESCULAPIUS", 0x2764: "HEAVY BLACK HEART",
+	0xFEFF: "ZERO WIDTH NO-BREAK SPACE", 0xFFFD: "REPLACEMENT CHARACTER",
+	0x1F30D: "EARTH GLOBE EUROPE-AFRICA", 0x1F389: "PARTY POPPER",
+	0x1F3F3: "WAVING WHITE FLAG", 0x1F308: "RAINBOW",
+	0x1F466: "BOY", 0x1F467: "GIRL", 0x1F468: "MAN", 0x1F469: "WOMAN",
+	0x1F44B: "WAVING HAND SIGN", 0x1F44D: "THUMBS UP SIGN", 0x1F4BB: "PERSONAL COMPUTER",
+	0x1F600: "GRINNING FACE", 0x1F602: "FACE WITH TEARS OF JOY", 0x1F60A: "SMILING FACE WITH SMILING EYES",
+	0x1F680: "ROCKET", 0x1F9D1: "ADULT", 0x1F9EA: "TEST TUBE",
+}
+
+// greekLetters and cyrillicLetters name the basic alphabets in code point
+// order; U+03A2 is unassigned and U+03C2 is FINAL SIGMA.
+var (
+	greekLetters = []string{
+		"ALPHA", "BETA", "GAMMA", "DELTA", "EPSILON", "ZETA", "ETA", "THETA", "IOTA", "KAPPA", "LAMDA", "MU",
+		"NU", "XI", "OMICRON", "PI", "RHO", "FINAL SIGMA", "SIGMA", "TAU", "UPSILON", "PHI", "CHI", "PSI", "OMEGA",
+	}
+	cyrillicLetters = []string{
+		"A", "BE", "VE", "GHE", "DE", "IE", "ZHE", "ZE", "I", "SHORT I", "KA", "EL", "EM", "EN", "O", "PE",
+		"ER", "ES", "TE", "U", "EF", "HA", "TSE", "CHE", "SHA", "SHCHA", "HARD SIGN", "YERU", "SOFT SIGN", "E", "YU", "YA",
+	}
+)
+
+var digitNames = []string{"ZERO", "ONE", "TWO", "THREE", "FOUR", "FIVE", "SIX", "SEVEN", "EIGHT", "NINE"}
+
+// Hangul syllable name parts (Unicode chapter 3.12).
+var (
+	hangulL = []string{"G", "GG", "N", "D", "DD", "R", "M", "B", "BB", "S", "SS", "", "J", "JJ", "C", "K", "T", "P", "H"}
+	hangulV = []string{"A", "AE", "YA", "YAE", "EO", "E", "YEO", "YE", "O", "WA", "WAE", "OE", "YO", "U", "WEO", "WE", "WI", "YU", "EU", "YI", "I"}
+	hangulT = []string{"", "G", "GG", "GS", "N", "NJ", "NH", "D", "L", "LG", "LM", "LB", "LS", "LT", "LP", "LH", "M", "B", "BS", "S", "SS", "NG", "J", "C", "K", "T", "P", "H"}
+)
+
+// Name returns the Unicode character name of r: names that follow a rule
+// (ASCII letters and digits, the Greek and basic Cyrillic alphabets, CJK
+// ideographs, Hangul syllables, regional indicators, skin tones) are
+// computed, and a small table covers common punctuation and emoji. Anything
+// else gets a code point label built from its category, in the style of
+// Unicode's "<control-0001>": "<math-symbol-2211>", "<reserved-0378>".
+func Name(r rune) string {
+	switch {
+	case r >= 'A' && r <= 'Z':
+		return "LATIN CAPITAL LETTER " + string(r)
+	case r >= 'a' && r <= 'z':
+		return "LATIN SMALL LETTER " + strings.ToUpper(string(r))
+	case r >= '0' && r <= '9':
+		return "DIGIT " + digitNames[r-'0']
+	case r >= 0x0391 && r <= 0x03A9 && r != 0x03A2:
+		return "GREEK CAPITAL LETTER " + greekLetters[r-0x0391]
+	case r >= 0x03B1 && r <= 0x03C9:
+		return "GREEK SMALL LETTER " + greekLetters[r-0x03B1]
+	case r >= 0x0410 && r <= 0x042F:
+		return "CYRILLIC CAPITAL LETTER " + cyrillicLetters[r-0x0410]
+	case r >= 0x0430 && r <= 0x044F:
+		return "CYRILLIC SMALL LETTER " + cyrillicLetters[r-0x0430]
+	case r >= 0x4E00 && r <= 0x9FFF, r >= 0x3400 && r <= 0x4DBF, r >= 0x20000 && r <= 0x2A6DF:
+		return fmt.Sprintf("CJK UNIFIED IDEOGRAPH-%04X", r)
+	case r >= 0xAC00 && r <= 0xD7A3:
+		s := int(r - 0xAC00)
+		return "HANGUL SYLLABLE " + hangulL[s/588] + hangulV[(s%588)/28] + hangulT[s%28]
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return "REGIONAL INDICATOR SYMBOL LETTER " + string('A'+r-0x1F1E6)
+	case r >= 0x1F3FB && r <= 0x1F3FF:
+		return "EMOJI MODIFIER FITZPATRICK TYPE-" + [...]string{"1-2", "3", "4", "5", "6"}[r-0x1F3FB]
+	case r >= 0xFE00 && r <= 0xFE0F:
+		return fmt.Sprintf("VARIATION SELECTOR-%d", r-0xFE00+1)
+	}
+	if name, ok := knownNames[r]; ok {
+		return name
+	}
+	return codePointLabel(r)
+}
+
+// codePointLabel returns the label for a code point Name has no name for.
+// Reserved, private-use, surrogate and control code points use the labels
+// Unicode defines for them; other categories use their long name.
+func codePointLabel(r rune) string {
+	var kind string
+	switch cat := Category(r); cat {
+	case "Cn":
+		kind = "reserved"
+	case "Co":
+		kind = "private-use"
+	case "Cs":
+		kind = "surrogate"
+	case "Cc":
+		kind = "control"
+	default:
+		kind = strings.ReplaceAll(strings.ToLower(CategoryName(cat)), " ", "-")
+	}
+	return fmt.Sprintf("<%s-%04X>", kind, r)
+}
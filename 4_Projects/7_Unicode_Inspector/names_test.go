@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		r    rune
+		want string
+	}{
+		{'A', "LATIN CAPITAL LETTER A"},
+		{'z', "LATIN SMALL LETTER Z"},
+		{'7', "DIGIT SEVEN"},
+		{'\t', "CHARACTER TABULATION"},
+		{0x01, "<control-0001>"},
+		{0x85, "<control-0085>"},
+		{'Ω', "GREEK CAPITAL LETTER OMEGA"},
+		{'Σ', "GREEK CAPITAL LETTER SIGMA"},
+		{'ς', "GREEK SMALL LETTER FINAL SIGMA"},
+		{'λ', "GREEK SMALL LETTER LAMDA"},
+		{'Ж', "CYRILLIC CAPITAL LETTER ZHE"},
+		{'я', "CYRILLIC SMALL LETTER YA"},
+		{'世', "CJK UNIFIED IDEOGRAPH-4E16"},
+		{'한', "HANGUL SYLLABLE HAN"},
+		{0x1F1E9, "REGIONAL INDICATOR SYMBOL LETTER D"},
+		{0x1F3FD, "EMOJI MODIFIER FITZPATRICK TYPE-4"},
+		{0xFE0F, "VARIATION SELECTOR-16"},
+		{0x200D, "ZERO WIDTH JOINER"},
+		{'∑', "<math-symbol-2211>"},
+		{'ß', "<lowercase-letter-00DF>"},
+		{0x03A2, "<reserved-03A2>"},
+		{0x0378, "<reserved-0378>"},
+		{0xE000, "<private-use-E000>"},
+	}
+	for _, tt := range tests {
+		if got := Name(tt.r); got != tt.want {
+			t.Errorf("Name(%U) = %q, want %q", tt.r, got, tt.want)
+		}
+	}
+}
+
+// TestNameNeverEmpty checks that every code point gets some name or label.
+func TestNameNeverEmpty(t *testing.T) {
+	for r := rune(0); r <= 0x10FFFF; r += 97 {
+		if Name(r) == "" {
+			t.Fatalf("Name(%U) is empty", r)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestClusterWidth(t *testing.T) {
+	tests := []struct {
+		name, cluster string
+		want          int
+	}{
+		{"ASCII", "a", 1},
+		{"Greek", "Ω", 1},
+		{"combining accent", "e\u0301", 1},
+		{"CJK", "世", 2},
+		{"Hangul syllable", "한", 2},
+		{"Hangul jamo", "\u1112\u1161\u11AB", 2},
+		{"emoji", "🚀", 2},
+		{"skin tone", "👍🏽", 2},
+		{"ZWJ sequence", "👩\u200D💻", 2},
+		{"text symbol", "❤", 1},
+		{"VS16 emoji style", "❤\uFE0F", 2},
+		{"flag", "🇩🇪", 2},
+		{"lone regional indicator", "🇩", 1},
+		{"zero width joiner", "\u200D", 0},
+		{"control", "\x00", 0},
+	}
+	for _, tt := range tests {
+		if got := ClusterWidth(tt.cluster); got != tt.want {
+			t.Errorf("%s: ClusterWidth(%q) = %d, want %d", tt.name, tt.cluster, got, tt.want)
+		}
+	}
+}
+
+func TestStringWidth(t *testing.T) {
+	tests := map[string]int{
+		"":              0,
+		"Hello":         5,
+		"Ω🇩🇪é":          4,
+		"Hello, 世界":     11,
+		"🇮🇳🇩🇪":          4,
+		"e\u0301\u0301": 1,
+	}
+	for s, want := range tests {
+		if got := StringWidth(s); got != want {
+			t.Errorf("StringWidth(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
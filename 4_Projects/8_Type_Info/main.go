@@ -0,0 +1,137 @@
+// Package main implements typeinfo, a reflection-based explorer for Go types.
+//
+// SECTIONS 3 and 5 of the Variables and Constants lesson list a few zero values
+// and ranges by hand. typeinfo derives them for any type with the reflect
+// package: the zero value, unsafe.Sizeof-style size, alignment, struct field
+// offsets and padding, numeric min/max and whether the type is comparable.
+//
+//	go run main.go typeinfo.go                # every registered type
+//	go run main.go typeinfo.go -list          # just the names
+//	go run main.go typeinfo.go int8 Employee  # selected types
+//	go test *.go                              # layouts checked against unsafe.Sizeof and Offsetof
+//
+// To explore your own type, add it to the registry below with Of[YourType]().
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// Person and Employee mirror the structs from the Structs and Methods lesson.
+type Person struct {
+	Name string
+	Age  int
+}
+
+type Employee struct {
+	Person
+	Position   string
+	Salary     float64
+	Department string
+}
+
+// Padded shows how field order affects size: the bools force padding
+// around the int64, which Reordered avoids.
+type Padded struct {
+	A bool
+	B int64
+	C bool
+}
+
+type Reordered struct {
+	B int64
+	A bool
+	C bool
+}
+
+// registry maps the names accepted on the command line to their descriptions.
+var registry = map[string]Info{
+	// SECTION 3: zero values.
+	"string":  Of[string](),
+	"int":     Of[int](),
+	"float64": Of[float64](),
+	"bool":    Of[bool](),
+	// SECTION 5: data types and ranges.
+	"int8":       Of[int8](),
+	"int16":      Of[int16](),
+	"int32":      Of[int32](),
+	"int64":      Of[int64](),
+	"uint":       Of[uint](),
+	"uint8":      Of[uint8](),
+	"uint16":     Of[uint16](),
+	"uint32":     Of[uint32](),
+	"uint64":     Of[uint64](),
+	"uintptr":    Of[uintptr](),
+	"float32":    Of[float32](),
+	"complex64":  Of[complex64](),
+	"complex128": Of[complex128](),
+	"rune":       Of[rune](),
+	"byte":       Of[byte](),
+	// Composite and reference types.
+	"[3]int":         Of[[3]int](),
+	"[]int":          Of[[]int](),
+	"map[string]int": Of[map[string]int](),
+	"*int":           Of[*int](),
+	"func()":         Of[func()](),
+	"chan int":       Of[chan int](),
+	"error":          Of[error](),
+	"any":            Of[any](),
+	"time.Time":      Of[time.Time](),
+	"time.Duration":  Of[time.Duration](),
+	// User-defined structs.
+	"Person":    Of[Person](),
+	"Employee":  Of[Employee](),
+	"Padded":    Of[Padded](),
+	"Reordered": Of[Reordered](),
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("typeinfo", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	list := flags.Bool("list", false, "list the registered type names")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	names := flags.Args()
+	if len(names) == 0 {
+		for name := range registry {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	if *list {
+		for _, name := range names {
+			fmt.Fprintln(stdout, name)
+		}
+		return 0
+	}
+
+	status := 0
+	for i, name := range names {
+		info, ok := registry[name]
+		if !ok {
+			fmt.Fprintf(stderr, "error: unknown type %q (see -list)\n", name)
+			status = 2
+			continue
+		}
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		info.Print(stdout)
+	}
+	return status
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Info is everything typeinfo reports about one type.
+type Info struct {
+	Name       string
+	Kind       reflect.Kind
+	Zero       string // Zero value formatted with %#v.
+	Size       uintptr
+	Align      int
+	FieldAlign int
+	Comparable bool
+	Min, Max   string      // Only set for numeric kinds.
+	Fields     []FieldInfo // Only set for structs.
+	Padding    uintptr     // Bytes of padding inside a struct, including the tail.
+}
+
+// FieldInfo describes one struct field's place in memory.
+type FieldInfo struct {
+	Name     string
+	Type     string
+	Offset   uintptr
+	Size     uintptr
+	Align    int
+	Embedded bool
+}
+
+// Of describes T; it also works for interface types, unlike reflect.TypeOf(v).
+func Of[T any]() Info {
+	return Describe(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Describe reports the zero value, layout and range of t using reflection only.
+func Describe(t reflect.Type) Info {
+	info := Info{
+		Name:       t.String(),
+		Kind:       t.Kind(),
+		Zero:       formatZero(t),
+		Size:       t.Size(),
+		Align:      t.Align(),
+		FieldAlign: t.FieldAlign(),
+		Comparable: t.Comparable(),
+	}
+	info.Min, info.Max = numericRange(t)
+
+	if t.Kind() == reflect.Struct {
+		var end uintptr // End of the previous field, to measure padding between fields.
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			info.Padding += f.Offset - end
+			end = f.Offset + f.Type.Size()
+			info.Fields = append(info.Fields, FieldInfo{
+				Name:     f.Name,
+				Type:     f.Type.String(),
+				Offset:   f.Offset,
+				Size:     f.Type.Size(),
+				Align:    f.Type.Align(),
+				Embedded: f.Anonymous,
+			})
+		}
+		info.Padding += t.Size() - end
+	}
+	return info
+}
+
+// formatZero prints the zero value of t as Go syntax. Unsigned integers are
+// shown in decimal rather than %#v's hexadecimal.
+func formatZero(t reflect.Type) string {
+	zero := reflect.Zero(t).Interface()
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return fmt.Sprintf("%d", zero)
+	}
+	return fmt.Sprintf("%#v", zero)
+}
+
+// numericRange returns the smallest and largest values of a numeric type.
+// Floats report their largest finite magnitude; complex types report the
+// range of each component.
+func numericRange(t reflect.Type) (string, string) {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := uint(t.Bits())
+		return fmt.Sprint(int64(-1) << (bits - 1)), fmt.Sprint(int64(1)<<(bits-1) - 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "0", fmt.Sprint(uint64(math.MaxUint64) >> (64 - uint(t.Bits())))
+	case reflect.Float32:
+		return fmt.Sprint(float32(-math.MaxFloat32)), fmt.Sprint(float32(math.MaxFloat32))
+	case reflect.Float64:
+		return fmt.Sprint(-math.MaxFloat64), fmt.Sprint(math.MaxFloat64)
+	case reflect.Complex64:
+		return fmt.Sprint(float32(-math.MaxFloat32)), fmt.Sprint(float32(math.MaxFloat32), " (real and imaginary parts)")
+	case reflect.Complex128:
+		return fmt.Sprint(-math.MaxFloat64), fmt.Sprint(math.MaxFloat64, " (real and imaginary parts)")
+	}
+	return "", ""
+}
+
+// Print writes a human-readable report of info.
+func (info Info) Print(w io.Writer) {
+	fmt.Fprintf(w, "%s (%s)\n", info.Name, info.Kind)
+	fmt.Fprintf(w, "  zero value:  %s\n", info.Zero)
+	fmt.Fprintf(w, "  size:        %d bytes\n", info.Size)
+	fmt.Fprintf(w, "  alignment:   %d (as a field: %d)\n", info.Align, info.FieldAlign)
+	fmt.Fprintf(w, "  comparable:  %t\n", info.Comparable)
+	if info.Min != "" {
+		fmt.Fprintf(w, "  range:       %s … %s\n", info.Min, info.Max)
+	}
+	if len(info.Fields) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "  fields (%d bytes of padding):\n", info.Padding)
+	for _, f := range info.Fields {
+		name := f.Name
+		if f.Embedded {
+			name += " (embedded)"
+		}
+		fmt.Fprintf(w, "    %-3d %-24s %-12s size %-3d align %d\n", f.Offset, name, f.Type, f.Size, f.Align)
+	}
+}
+
+// String returns the report as a string.
+func (info Info) String() string {
+	var b strings.Builder
+	info.Print(&b)
+	return b.String()
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// layout is what the compiler says about T, to check Of[T] against.
+type layout struct {
+	info                    Info
+	size, align, fieldAlign uintptr
+}
+
+func layoutOf[T any]() layout {
+	var v T
+	var s struct{ f T }
+	return layout{Of[T](), unsafe.Sizeof(v), unsafe.Alignof(v), unsafe.Alignof(s.f)}
+}
+
+func TestLayout(t *testing.T) {
+	for _, l := range []layout{
+		layoutOf[bool](), layoutOf[int8](), layoutOf[int](), layoutOf[uint64](), layoutOf[uintptr](),
+		layoutOf[float32](), layoutOf[complex128](), layoutOf[string](), layoutOf[[3]int](),
+		layoutOf[[]int](), layoutOf[map[string]int](), layoutOf[*int](), layoutOf[func()](),
+		layoutOf[chan int](), layoutOf[error](), layoutOf[any](), layoutOf[time.Time](),
+		layoutOf[Person](), layoutOf[Employee](), layoutOf[Padded](), layoutOf[Reordered](),
+		layoutOf[struct{}](), layoutOf[[0]int64](),
+	} {
+		if l.info.Size != l.size || uintptr(l.info.Align) != l.align || uintptr(l.info.FieldAlign) != l.fieldAlign {
+			t.Errorf("%s: size %d, align %d, field align %d; unsafe says %d, %d, %d",
+				l.info.Name, l.info.Size, l.info.Align, l.info.FieldAlign, l.size, l.align, l.fieldAlign)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	var e Employee
+	var p Padded
+	var r Reordered
+	tests := []struct {
+		info    Info
+		offsets []uintptr
+		sizes   []uintptr
+		padding uintptr
+	}{
+		{
+			Of[Employee](),
+			[]uintptr{unsafe.Offsetof(e.Person), unsafe.Offsetof(e.Position), unsafe.Offsetof(e.Salary), unsafe.Offsetof(e.Department)},
+			[]uintptr{unsafe.Sizeof(e.Person), unsafe.Sizeof(e.Position), unsafe.Sizeof(e.Salary), unsafe.Sizeof(e.Department)},
+			0,
+		},
+		{
+			Of[Padded](),
+			[]uintptr{unsafe.Offsetof(p.A), unsafe.Offsetof(p.B), unsafe.Offsetof(p.C)},
+			[]uintptr{unsafe.Sizeof(p.A), unsafe.Sizeof(p.B), unsafe.Sizeof(p.C)},
+			unsafe.Sizeof(p) - unsafe.Sizeof(p.A) - unsafe.Sizeof(p.B) - unsafe.Sizeof(p.C),
+		},
+		{
+			Of[Reordered](),
+			[]uintptr{unsafe.Offsetof(r.B), unsafe.Offsetof(r.A), unsafe.Offsetof(r.C)},
+			[]uintptr{unsafe.Sizeof(r.B), unsafe.Sizeof(r.A), unsafe.Sizeof(r.C)},
+			unsafe.Sizeof(r) - unsafe.Sizeof(r.A) - unsafe.Sizeof(r.B) - unsafe.Sizeof(r.C),
+		},
+	}
+	for _, tt := range tests {
+		if len(tt.info.Fields) != len(tt.offsets) {
+			t.Errorf("%s: %d fields, want %d", tt.info.Name, len(tt.info.Fields), len(tt.offsets))
+			continue
+		}
+		for i, f := range tt.info.Fields {
+			if f.Offset != tt.offsets[i] || f.Size != tt.sizes[i] {
+				t.Errorf("%s.%s: offset %d, size %d; unsafe says %d, %d", tt.info.Name, f.Name, f.Offset, f.Size, tt.offsets[i], tt.sizes[i])
+			}
+		}
+		if tt.info.Padding != tt.padding {
+			t.Errorf("%s: %d bytes of padding, want %d", tt.info.Name, tt.info.Padding, tt.padding)
+		}
+	}
+	if f := Of[Employee]().Fields[0]; !f.Embedded || f.Type != "main.Person" {
+		t.Errorf("Employee.Person = %+v, want embedded main.Person", f)
+	}
+	if Of[Padded]().Size <= Of[Reordered]().Size {
+		t.Error("Padded is not larger than Reordered")
+	}
+}
+
+func TestValues(t *testing.T) {
+	tests := []struct {
+		info           Info
+		zero, min, max string
+		comparable     bool
+	}{
+		{Of[int8](), "0", fmt.Sprint(math.MinInt8), fmt.Sprint(math.MaxInt8), true},
+		{Of[int64](), "0", fmt.Sprint(math.MinInt64), fmt.Sprint(math.MaxInt64), true},
+		{Of[int](), "0", fmt.Sprint(math.MinInt), fmt.Sprint(math.MaxInt), true},
+		{Of[uint8](), "0", "0", fmt.Sprint(math.MaxUint8), true},
+		{Of[uint](), "0", "0", fmt.Sprint(uint(math.MaxUint)), true},
+		{Of[uint64](), "0", "0", fmt.Sprint(uint64(math.MaxUint64)), true},
+		{Of[float32](), "0", fmt.Sprint(float32(-math.MaxFloat32)), fmt.Sprint(float32(math.MaxFloat32)), true},
+		{Of[float64](), "0", fmt.Sprint(-math.MaxFloat64), fmt.Sprint(math.MaxFloat64), true},
+		{Of[string](), `""`, "", "", true},
+		{Of[bool](), "false", "", "", true},
+		{Of[*int](), "(*int)(nil)", "", "", true},
+		{Of[[]int](), "[]int(nil)", "", "", false},
+		{Of[map[string]int](), "map[string]int(nil)", "", "", false},
+		{Of[func()](), "(func())(nil)", "", "", false},
+		{Of[error](), "<nil>", "", "", true},
+		{Of[Person](), `main.Person{Name:"", Age:0}`, "", "", true},
+	}
+	for _, tt := range tests {
+		got := tt.info
+		if got.Zero != tt.zero || got.Min != tt.min || got.Max != tt.max || got.Comparable != tt.comparable {
+			t.Errorf("%s: zero %s, range %q…%q, comparable %t; want %s, %q…%q, %t",
+				got.Name, got.Zero, got.Min, got.Max, got.Comparable, tt.zero, tt.min, tt.max, tt.comparable)
+		}
+	}
+}
+
+// TestDescribeInterface checks that Of sees the interface type itself, where
+// Describe on a value sees the dynamic type inside it.
+func TestDescribeInterface(t *testing.T) {
+	if got := Of[error]().Kind; got != reflect.Interface {
+		t.Errorf("Of[error]().Kind = %s, want interface", got)
+	}
+	var err error = fmt.Errorf("boom")
+	if got := Describe(reflect.TypeOf(err)).Kind; got != reflect.Pointer {
+		t.Errorf("Describe(TypeOf(err)).Kind = %s, want ptr", got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"int8", "nope"}, &stdout, &stderr); code != 2 {
+		t.Errorf("exit code %d, want 2", code)
+	}
+	if !strings.Contains(stdout.String(), "range:       -128 … 127") {
+		t.Errorf("stdout lacks the int8 range:\n%s", &stdout)
+	}
+	if !strings.Contains(stderr.String(), `unknown type "nope"`) {
+		t.Errorf("stderr = %q, want unknown type", &stderr)
+	}
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode selects how digits beyond the requested precision are dropped.
+type RoundingMode int
+
+const (
+	HalfEven RoundingMode = iota // Banker's rounding: 2.5 → 2, 3.5 → 4 (default).
+	HalfUp                       // 2.5 → 3, -2.5 → -3.
+	HalfDown                     // 2.5 → 2, 2.51 → 3.
+	Down                         // Toward zero (truncate).
+	Up                           // Away from zero.
+	Floor                        // Toward negative infinity.
+	Ceiling                      // Toward positive infinity.
+)
+
+var roundingModeNames = map[string]RoundingMode{
+	"half-even": HalfEven, "half-up": HalfUp, "half-down": HalfDown,
+	"down": Down, "up": Up, "floor": Floor, "ceiling": Ceiling,
+}
+
+// ParseRoundingMode accepts the names used on the command line, e.g. "half-up".
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	if m, ok := roundingModeNames[strings.ToLower(s)]; ok {
+		return m, nil
+	}
+	return 0, fmt.Errorf("unknown rounding mode %q", s)
+}
+
+// decimal is a non-negative number as digit strings, so rounding happens on
+// the shortest decimal representation of a float rather than its binary value
+// (2.675 rounds to 2.68, not 2.67).
+type decimal struct {
+	neg  bool
+	int  string // At least one digit.
+	frac string
+}
+
+func newDecimal(v float64) decimal {
+	s := strconv.FormatFloat(math.Abs(v), 'f', -1, 64)
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	return decimal{neg: v < 0, int: intPart, frac: fracPart}
+}
+
+// round returns d with exactly places fractional digits.
+func (d decimal) round(places int, mode RoundingMode) decimal {
+	if len(d.frac) <= places {
+		d.frac += strings.Repeat("0", places-len(d.frac))
+		return d
+	}
+	kept, rest := d.frac[:places], d.frac[places:]
+	restNonZero := strings.Trim(rest, "0") != ""
+	tailNonZero := strings.Trim(rest[1:], "0") != ""
+
+	var increment bool
+	switch mode {
+	case HalfEven:
+		last := d.int + kept
+		odd := (last[len(last)-1]-'0')%2 == 1
+		increment = rest[0] > '5' || (rest[0] == '5' && (tailNonZero || odd))
+	case HalfUp:
+		increment = rest[0] >= '5'
+	case HalfDown:
+		increment = rest[0] > '5' || (rest[0] == '5' && tailNonZero)
+	case Down:
+		increment = false
+	case Up:
+		increment = restNonZero
+	case Floor:
+		increment = restNonZero && d.neg
+	case Ceiling:
+		increment = restNonZero && !d.neg
+	}
+
+	digits := d.int + kept
+	if increment {
+		digits = incrementDigits(digits)
+	}
+	d.int, d.frac = digits[:len(digits)-places], digits[len(digits)-places:]
+	return d
+}
+
+// incrementDigits adds one to a string of decimal digits.
+func incrementDigits(digits string) string {
+	b := []byte(digits)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < '9' {
+			b[i]++
+			return string(b)
+		}
+		b[i] = '0'
+	}
+	return "1" + string(b)
+}
+
+func (d decimal) isZero() bool {
+	return strings.Trim(d.int+d.frac, "0") == ""
+}
+
+// group inserts the locale's grouping separators into the integer digits.
+func group(digits string, loc Locale) string {
+	if len(loc.Grouping) == 0 || loc.Group == "" {
+		return digits
+	}
+	var parts []string
+	for i := 0; len(digits) > 0; i++ {
+		size := loc.Grouping[len(loc.Grouping)-1]
+		if i < len(loc.Grouping) {
+			size = loc.Grouping[i]
+		}
+		if size <= 0 || size >= len(digits) {
+			parts = append(parts, digits)
+			break
+		}
+		parts = append(parts, digits[len(digits)-size:])
+		digits = digits[:len(digits)-size]
+	}
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	return strings.Join(parts, loc.Group)
+}
+
+// formatDecimal renders d (already rounded) with grouping and the decimal separator.
+func formatDecimal(d decimal, loc Locale) string {
+	s := group(d.int, loc)
+	if d.frac != "" {
+		s += loc.Decimal + d.frac
+	}
+	if d.neg && !d.isZero() {
+		s = "-" + s
+	}
+	return s
+}
+
+// FormatNumber formats v with grouping separators and exactly places decimals.
+func FormatNumber(v float64, places int, mode RoundingMode, loc Locale) string {
+	if s, ok := nonFinite(v); ok {
+		return s
+	}
+	return formatDecimal(newDecimal(v).round(places, mode), loc)
+}
+
+// FormatCurrency formats v as a fixed-decimal amount in the currency's minor
+// unit precision, e.g. "$75,000.50", "75.000,50 €" or "¥75,001".
+func FormatCurrency(v float64, cur Currency, mode RoundingMode, loc Locale) string {
+	if s, ok := nonFinite(v); ok {
+		return s
+	}
+	d := newDecimal(v).round(cur.Decimals, mode)
+	neg := d.neg && !d.isZero()
+	d.neg = false
+	s := strings.NewReplacer("{n}", formatDecimal(d, loc), "{s}", cur.Symbol).Replace(loc.CurrencyPattern)
+	if neg {
+		s = "-" + s
+	}
+	return s
+}
+
+// FormatPercent formats a ratio as a percentage: 0.256 → "25.6%".
+func FormatPercent(ratio float64, places int, mode RoundingMode, loc Locale) string {
+	if s, ok := nonFinite(ratio); ok {
+		return s
+	}
+	// Shift the decimal point on the digit string so 0.07 becomes exactly 7.
+	n := formatDecimal(newDecimal(ratio).shift(2).round(places, mode), loc)
+	return strings.Replace(loc.PercentPattern, "{n}", n, 1)
+}
+
+// shift multiplies d by 10^n by moving digits from frac to int.
+func (d decimal) shift(n int) decimal {
+	frac := d.frac + strings.Repeat("0", max0(n-len(d.frac)))
+	d.int, d.frac = strings.TrimLeft(d.int+frac[:n], "0"), frac[n:]
+	if d.int == "" {
+		d.int = "0"
+	}
+	return d
+}
+
+// FormatCompact abbreviates large numbers with at most one decimal:
+// 75000.5 → "75K", 1234567 → "1.2M". Values below 1000 are printed as is.
+func FormatCompact(v float64, loc Locale) string {
+	if s, ok := nonFinite(v); ok {
+		return s
+	}
+	abs := math.Abs(v)
+	if abs < 999.95 {
+		return FormatNumber(v, trimmedPlaces(v, 1), HalfEven, loc)
+	}
+
+	// 999,950 would round to "1000K"; move it up to "1M" instead.
+	unit := 0
+	scaled := abs / 1e3
+	for unit < len(loc.Compact)-1 && scaled >= 999.95 {
+		scaled /= 1e3
+		unit++
+	}
+	d := newDecimal(scaled).round(1, HalfEven)
+	if strings.Trim(d.frac, "0") == "" {
+		d.frac = ""
+	}
+	d.neg = v < 0
+	return formatDecimal(d, loc) + loc.Compact[unit]
+}
+
+// trimmedPlaces returns how many of up to max decimals v actually needs.
+func trimmedPlaces(v float64, max int) int {
+	d := newDecimal(v).round(max, HalfEven)
+	return len(strings.TrimRight(d.frac, "0"))
+}
+
+func nonFinite(v float64) (string, bool) {
+	switch {
+	case math.IsNaN(v):
+		return "NaN", true
+	case math.IsInf(v, 1):
+		return "∞", true
+	case math.IsInf(v, -1):
+		return "-∞", true
+	}
+	return "", false
+}
+
+func max0(n int) int {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// mustLocale looks tag up or fails the test.
+func mustLocale(t *testing.T, tag string) Locale {
+	t.Helper()
+	loc, err := LookupLocale(tag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestRoundingModes(t *testing.T) {
+	modes := []RoundingMode{HalfEven, HalfUp, HalfDown, Down, Up, Floor, Ceiling}
+	tests := []struct {
+		v    float64
+		want [7]string // In the order of modes.
+	}{
+		{2.5, [7]string{"2", "3", "2", "2", "3", "2", "3"}},
+		{3.5, [7]string{"4", "4", "3", "3", "4", "3", "4"}},
+		{2.51, [7]string{"3", "3", "3", "2", "3", "2", "3"}},
+		{2.4, [7]string{"2", "2", "2", "2", "3", "2", "3"}},
+		{-2.5, [7]string{"-2", "-3", "-2", "-2", "-3", "-3", "-2"}},
+		{-2.4, [7]string{"-2", "-2", "-2", "-2", "-3", "-3", "-2"}},
+		{3, [7]string{"3", "3", "3", "3", "3", "3", "3"}},
+		{9.5, [7]string{"10", "10", "9", "9", "10", "9", "10"}},
+	}
+	loc := mustLocale(t, "en-US")
+	for _, tt := range tests {
+		for i, mode := range modes {
+			if got := FormatNumber(tt.v, 0, mode, loc); got != tt.want[i] {
+				t.Errorf("FormatNumber(%v, 0, mode %d) = %q, want %q", tt.v, mode, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestRoundingShortestDecimal(t *testing.T) {
+	loc := mustLocale(t, "en-US")
+	tests := []struct {
+		v      float64
+		places int
+		mode   RoundingMode
+		want   string
+	}{
+		{2.675, 2, HalfUp, "2.68"}, // %.2f gives 2.67.
+		{2.675, 2, HalfEven, "2.68"},
+		{2.665, 2, HalfEven, "2.66"},
+		{1.005, 2, HalfUp, "1.01"},
+		{0.125, 2, HalfDown, "0.12"},
+		{0.1251, 2, HalfDown, "0.13"},
+		{999.995, 2, HalfUp, "1,000.00"},
+		{1.5, 3, HalfEven, "1.500"},
+	}
+	for _, tt := range tests {
+		if got := FormatNumber(tt.v, tt.places, tt.mode, loc); got != tt.want {
+			t.Errorf("FormatNumber(%v, %d, mode %d) = %q, want %q", tt.v, tt.places, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestGrouping(t *testing.T) {
+	tests := []struct {
+		tag  string
+		v    float64
+		want string
+	}{
+		{"en-US", 999, "999.00"},
+		{"en-US", 1000, "1,000.00"},
+		{"en-US", 1234567.5, "1,234,567.50"},
+		{"de-DE", 1234567.5, "1.234.567,50"},
+		{"fr-FR", 1234567.5, "1" + narrowNbsp + "234" + narrowNbsp + "567,50"},
+		{"en-IN", 1000, "1,000.00"},
+		{"en-IN", 100000, "1,00,000.00"},
+		{"en-IN", 12345678.9, "1,23,45,678.90"},
+		{"en-IN", 1234567890, "1,23,45,67,890.00"},
+	}
+	for _, tt := range tests {
+		if got := FormatNumber(tt.v, 2, HalfEven, mustLocale(t, tt.tag)); got != tt.want {
+			t.Errorf("%s: FormatNumber(%v) = %q, want %q", tt.tag, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestNegativeZero(t *testing.T) {
+	loc := mustLocale(t, "en-US")
+	usd := currencies["USD"]
+	tests := []struct {
+		name, got, want string
+	}{
+		{"negative zero", FormatNumber(math.Copysign(0, -1), 2, HalfEven, loc), "0.00"},
+		{"rounds to zero", FormatNumber(-0.004, 2, HalfEven, loc), "0.00"},
+		{"does not round to zero", FormatNumber(-0.005, 2, HalfUp, loc), "-0.01"},
+		{"currency", FormatCurrency(-0.001, usd, HalfEven, loc), "$0.00"},
+		{"negative currency", FormatCurrency(-5, usd, HalfEven, loc), "-$5.00"},
+		{"percent", FormatPercent(-0.00001, 1, HalfEven, loc), "0.0%"},
+		{"compact", FormatCompact(math.Copysign(0, -1), loc), "0"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestFormatCurrencyAndPercent(t *testing.T) {
+	tests := []struct {
+		tag, code string
+		v         float64
+		want      string
+	}{
+		{"en-US", "USD", 75000.5, "$75,000.50"},
+		{"de-DE", "EUR", 75000.5, "75.000,50" + nbsp + "€"},
+		{"en-IN", "INR", 7500000.5, "₹75,00,000.50"},
+		{"ja-JP", "JPY", 75000.5, "¥75,000"}, // Half-even, no minor unit.
+		{"ja-JP", "JPY", 75001.5, "¥75,002"},
+	}
+	for _, tt := range tests {
+		got := FormatCurrency(tt.v, currencies[tt.code], HalfEven, mustLocale(t, tt.tag))
+		if got != tt.want {
+			t.Errorf("%s: FormatCurrency(%v, %s) = %q, want %q", tt.tag, tt.v, tt.code, got, tt.want)
+		}
+	}
+
+	if got := FormatPercent(0.07, 0, HalfEven, mustLocale(t, "en-US")); got != "7%" {
+		t.Errorf("FormatPercent(0.07) = %q, want 7%%", got)
+	}
+	if got := FormatPercent(0.256, 1, HalfEven, mustLocale(t, "fr-FR")); got != "25,6"+narrowNbsp+"%" {
+		t.Errorf("fr-FR: FormatPercent(0.256) = %q", got)
+	}
+}
+
+func TestFormatCompact(t *testing.T) {
+	loc := mustLocale(t, "en-US")
+	tests := []struct {
+		v    float64
+		want string
+	}{
+		{999, "999"},
+		{999.94, "999.9"},
+		{999.96, "1K"},
+		{75000.5, "75K"},
+		{1234567, "1.2M"},
+		{999950, "1M"},
+		{-1500, "-1.5K"},
+		{2.5e15, "2,500T"},
+		{math.Inf(-1), "-∞"},
+		{math.NaN(), "NaN"},
+	}
+	for _, tt := range tests {
+		if got := FormatCompact(tt.v, loc); got != tt.want {
+			t.Errorf("FormatCompact(%v) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Locale holds the number formatting conventions of one region.
+type Locale struct {
+	Tag      string
+	Decimal  string // Decimal separator, e.g. "." or ",".
+	Group    string // Grouping separator, e.g. "," or "." or a narrow no-break space.
+	Grouping []int  // Group sizes from the right; the last size repeats ([3] or Indian [3, 2]).
+
+	// Patterns use "{n}" for the formatted number and "{s}" for the currency symbol.
+	CurrencyPattern string // e.g. "{s}{n}" or "{n} {s}"
+	PercentPattern  string // e.g. "{n}%" or "{n} %"
+
+	// Compact suffixes for thousands, millions, billions and trillions.
+	Compact [4]string
+}
+
+const (
+	nbsp       = " " // NO-BREAK SPACE
+	narrowNbsp = " " // NARROW NO-BREAK SPACE
+)
+
+// locales lists the built-in locales by tag.
+var locales = map[string]Locale{
+	"en-US": {
+		Tag: "en-US", Decimal: ".", Group: ",", Grouping: []int{3},
+		CurrencyPattern: "{s}{n}", PercentPattern: "{n}%",
+		Compact: [4]string{"K", "M", "B", "T"},
+	},
+	"en-GB": {
+		Tag: "en-GB", Decimal: ".", Group: ",", Grouping: []int{3},
+		CurrencyPattern: "{s}{n}", PercentPattern: "{n}%",
+		Compact: [4]string{"K", "M", "B", "T"},
+	},
+	"en-IN": {
+		Tag: "en-IN", Decimal: ".", Group: ",", Grouping: []int{3, 2},
+		CurrencyPattern: "{s}{n}", PercentPattern: "{n}%",
+		Compact: [4]string{"K", "M", "B", "T"},
+	},
+	"de-DE": {
+		Tag: "de-DE", Decimal: ",", Group: ".", Grouping: []int{3},
+		CurrencyPattern: "{n}" + nbsp + "{s}", PercentPattern: "{n}" + nbsp + "%",
+		Compact: [4]string{nbsp + "Tsd.", nbsp + "Mio.", nbsp + "Mrd.", nbsp + "Bio."},
+	},
+	"fr-FR": {
+		Tag: "fr-FR", Decimal: ",", Group: narrowNbsp, Grouping: []int{3},
+		CurrencyPattern: "{n}" + nbsp + "{s}", PercentPattern: "{n}" + narrowNbsp + "%",
+		Compact: [4]string{nbsp + "k", nbsp + "M", nbsp + "Md", nbsp + "Bn"},
+	},
+	"ja-JP": {
+		Tag: "ja-JP", Decimal: ".", Group: ",", Grouping: []int{3},
+		CurrencyPattern: "{s}{n}", PercentPattern: "{n}%",
+		Compact: [4]string{"K", "M", "B", "T"},
+	},
+}
+
+// LookupLocale finds a built-in locale, accepting "de_DE" and "de-de" spellings
+// and falling back from a bare language ("de") to its first region.
+func LookupLocale(tag string) (Locale, error) {
+	tag = strings.ReplaceAll(tag, "_", "-")
+	for _, known := range LocaleTags() {
+		if strings.EqualFold(known, tag) {
+			return locales[known], nil
+		}
+	}
+	for _, known := range LocaleTags() {
+		if strings.EqualFold(strings.SplitN(known, "-", 2)[0], tag) {
+			return locales[known], nil
+		}
+	}
+	return Locale{}, fmt.Errorf("unknown locale %q (known: %s)", tag, strings.Join(LocaleTags(), ", "))
+}
+
+// LocaleTags returns the built-in locale tags in sorted order.
+func LocaleTags() []string {
+	tags := make([]string, 0, len(locales))
+	for tag := range locales {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Currency describes an ISO 4217 currency.
+type Currency struct {
+	Code     string
+	Symbol   string
+	Decimals int // Minor unit digits: 2 for cents, 0 for yen.
+}
+
+// currencies lists the built-in currencies by ISO code.
+var currencies = map[string]Currency{
+	"USD": {Code: "USD", Symbol: "$", Decimals: 2},
+	"EUR": {Code: "EUR", Symbol: "€", Decimals: 2},
+	"GBP": {Code: "GBP", Symbol: "£", Decimals: 2},
+	"INR": {Code: "INR", Symbol: "₹", Decimals: 2},
+	"JPY": {Code: "JPY", Symbol: "¥", Decimals: 0},
+	"CHF": {Code: "CHF", Symbol: "CHF", Decimals: 2},
+}
+
+// LookupCurrency finds a built-in currency by its ISO code.
+func LookupCurrency(code string) (Currency, error) {
+	if c, ok := currencies[strings.ToUpper(code)]; ok {
+		return c, nil
+	}
+	return Currency{}, fmt.Errorf("unknown currency %q", code)
+}
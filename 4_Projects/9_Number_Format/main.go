@@ -0,0 +1,154 @@
+// Package main implements locale-aware number and currency formatting.
+//
+// The Structs and Methods lesson prints salaries with "Salary: %.2f", which
+// gives "75000.50" everywhere. Readers expect "$75,000.50" in the US,
+// "75.000,50 €" in Germany and "₹75,000.50" with lakh grouping in India. This
+// project formats numbers with grouping separators, fixed-decimal currency
+// amounts with an explicit rounding mode, compact forms ("75K"), percentages,
+// and parses each of those back.
+//
+//	go run main.go format.go locale.go parse.go                # the lesson's salaries in every locale
+//	go run main.go format.go locale.go parse.go -locale de-DE -currency EUR 1234.5 0.07
+//	go run main.go format.go locale.go parse.go -locale en-IN -currency INR -round down 12345678.999
+//	go run main.go format.go locale.go parse.go -locale fr -parse "75 000,50 €"
+//	go test *.go                                               # rounding, grouping and parse round-trips
+//
+// Rounding happens on the shortest decimal form of each float, so 2.675 with
+// half-up gives 2.68 rather than the 2.67 that fmt's %.2f prints.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+// salaries are the Employee values from the Structs and Methods lesson.
+var salaries = []struct {
+	Name   string
+	Salary float64
+}{
+	{"Bob", 75000.50},
+	{"Eve", 90000.00},
+}
+
+// localeCurrency picks a currency for each locale in the salary table.
+var localeCurrency = map[string]string{
+	"de-DE": "EUR", "en-GB": "GBP", "en-IN": "INR",
+	"en-US": "USD", "fr-FR": "EUR", "ja-JP": "JPY",
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("numfmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	localeTag := flags.String("locale", "en-US", "locale tag, e.g. de-DE or fr")
+	currencyCode := flags.String("currency", "USD", "ISO 4217 currency code")
+	roundName := flags.String("round", "half-even", "rounding mode: half-even, half-up, half-down, down, up, floor, ceiling")
+	places := flags.Int("places", 2, "decimal places for plain numbers and percentages")
+	parse := flags.Bool("parse", false, "parse the arguments instead of formatting them")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	loc, err := LookupLocale(*localeTag)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	cur, err := LookupCurrency(*currencyCode)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	mode, err := ParseRoundingMode(*roundName)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 2
+	}
+	if *places < 0 {
+		fmt.Fprintln(stderr, "error: -places must not be negative")
+		return 2
+	}
+
+	if flags.NArg() == 0 {
+		if *parse {
+			fmt.Fprintln(stderr, "error: -parse needs at least one argument")
+			return 2
+		}
+		printSalaries(stdout, mode)
+		return 0
+	}
+
+	status := 0
+	for _, arg := range flags.Args() {
+		if *parse {
+			if err := parseAny(stdout, arg, loc); err != nil {
+				fmt.Fprintln(stderr, "error:", err)
+				status = 1
+			}
+			continue
+		}
+		v, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %q is not a number (use Go syntax, e.g. 1234.5)\n", arg)
+			status = 2
+			continue
+		}
+		fmt.Fprintf(stdout, "%s\n", arg)
+		fmt.Fprintf(stdout, "  number:   %s\n", FormatNumber(v, *places, mode, loc))
+		fmt.Fprintf(stdout, "  currency: %s\n", FormatCurrency(v, cur, mode, loc))
+		fmt.Fprintf(stdout, "  compact:  %s\n", FormatCompact(v, loc))
+		fmt.Fprintf(stdout, "  percent:  %s\n", FormatPercent(v, *places, mode, loc))
+	}
+	return status
+}
+
+// printSalaries shows each lesson salary in every built-in locale.
+func printSalaries(w io.Writer, mode RoundingMode) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "LOCALE\tEMPLOYEE\tRAW\tCURRENCY\tCOMPACT\tRAISE")
+	for _, tag := range LocaleTags() {
+		loc := locales[tag]
+		cur := currencies[localeCurrency[tag]]
+		for _, e := range salaries {
+			fmt.Fprintf(tw, "%s\t%s\t%.2f\t%s\t%s\t%s\n", tag, e.Name, e.Salary,
+				FormatCurrency(e.Salary, cur, mode, loc),
+				FormatCompact(e.Salary, loc),
+				FormatPercent(0.035, 1, mode, loc)) // A 3.5% raise, as a percentage.
+		}
+	}
+	tw.Flush()
+}
+
+// parseAny tries the currency, percent, compact and plain number forms in turn.
+func parseAny(w io.Writer, s string, loc Locale) error {
+	if v, cur, err := ParseCurrency(s, loc); err == nil {
+		fmt.Fprintf(w, "%q → %s %s\n", s, cur.Code, strconv.FormatFloat(v, 'f', -1, 64))
+		return nil
+	}
+	if v, err := ParsePercent(s, loc); err == nil {
+		fmt.Fprintf(w, "%q → ratio %s\n", s, strconv.FormatFloat(v, 'f', -1, 64))
+		return nil
+	}
+	if v, err := ParseNumber(s, loc); err == nil {
+		fmt.Fprintf(w, "%q → %s\n", s, strconv.FormatFloat(v, 'f', -1, 64))
+		return nil
+	}
+	v, err := ParseCompact(s, loc)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%q → %s\n", s, strconv.FormatFloat(v, 'f', -1, 64))
+	return nil
+}
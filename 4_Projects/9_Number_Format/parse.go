@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrSyntax is returned when a string is not a number in the given locale.
+var ErrSyntax = errors.New("invalid number")
+
+// ParseError reports which input could not be parsed and why.
+type ParseError struct {
+	Input  string
+	Locale string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parse %q as %s: %v", e.Input, e.Locale, e.Err)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// ParseNumber reads a number written by FormatNumber, e.g. "1.234,5" in de-DE.
+// Grouping separators are optional, but must sit between digits.
+func ParseNumber(s string, loc Locale) (float64, error) {
+	v, err := parseLocalized(strings.TrimSpace(s), loc)
+	if err != nil {
+		return 0, &ParseError{Input: s, Locale: loc.Tag, Err: err}
+	}
+	return v, nil
+}
+
+// ParseCurrency reads an amount written by FormatCurrency and returns it with
+// the currency whose symbol or ISO code appears in s: "75.000,50 €" → 75000.5 EUR.
+func ParseCurrency(s string, loc Locale) (float64, Currency, error) {
+	rest, cur, ok := stripCurrency(strings.TrimSpace(s))
+	if !ok {
+		return 0, Currency{}, &ParseError{Input: s, Locale: loc.Tag, Err: errors.New("no known currency symbol or code")}
+	}
+	v, err := parseLocalized(rest, loc)
+	if err != nil {
+		return 0, Currency{}, &ParseError{Input: s, Locale: loc.Tag, Err: err}
+	}
+	return v, cur, nil
+}
+
+// ParsePercent reads "25.6%" back as the ratio 0.256.
+func ParsePercent(s string, loc Locale) (float64, error) {
+	rest, ok := strings.CutSuffix(strings.TrimRightFunc(strings.TrimSpace(s), isSpace), "%")
+	if !ok {
+		return 0, &ParseError{Input: s, Locale: loc.Tag, Err: errors.New("missing %")}
+	}
+	v, err := parseLocalized(strings.TrimRightFunc(rest, isSpace), loc)
+	if err != nil {
+		return 0, &ParseError{Input: s, Locale: loc.Tag, Err: err}
+	}
+	return v / 100, nil
+}
+
+// ParseCompact reads "75K" or "1,2 Mio." back into a number. The result is
+// only as precise as the compact form: "1.2M" is 1,200,000.
+func ParseCompact(s string, loc Locale) (float64, error) {
+	s = strings.TrimSpace(s)
+	scale := 1.0
+	// Try the longest suffix first so "Mrd." is not mistaken for "M".
+	for unit := len(loc.Compact) - 1; unit >= 0; unit-- {
+		suffix := strings.TrimLeftFunc(loc.Compact[unit], isSpace)
+		if rest, ok := strings.CutSuffix(s, suffix); ok && suffix != "" {
+			s, scale = strings.TrimRightFunc(rest, isSpace), math.Pow(1000, float64(unit+1))
+			break
+		}
+	}
+	v, err := parseLocalized(s, loc)
+	if err != nil {
+		return 0, &ParseError{Input: s, Locale: loc.Tag, Err: err}
+	}
+	return v * scale, nil
+}
+
+// parseLocalized converts the locale's separators to Go syntax and parses the
+// result. A leading minus or accounting-style parentheses mark negatives.
+func parseLocalized(s string, loc Locale) (float64, error) {
+	neg := false
+	if inner, ok := strings.CutPrefix(s, "("); ok {
+		if inner, ok = strings.CutSuffix(inner, ")"); !ok {
+			return 0, ErrSyntax
+		}
+		s, neg = inner, true
+	} else if rest, ok := strings.CutPrefix(s, "-"); ok {
+		s, neg = rest, true
+	}
+
+	intPart, fracPart, hasFrac := strings.Cut(s, loc.Decimal)
+	digits, err := ungroup(intPart, loc)
+	if err != nil {
+		return 0, err
+	}
+	if hasFrac {
+		if fracPart == "" || strings.IndexFunc(fracPart, notDigit) >= 0 {
+			return 0, ErrSyntax
+		}
+		digits += "." + fracPart
+	}
+	v, err := strconv.ParseFloat(digits, 64)
+	if err != nil {
+		return 0, ErrSyntax
+	}
+	if neg {
+		v = -v
+	}
+	return v, nil
+}
+
+// ungroup removes grouping separators after checking that the groups have
+// the locale's sizes, so "1,2,3" is rejected in en-US. A plain space or
+// no-break space is accepted in place of a narrow no-break space since users
+// rarely type the latter.
+func ungroup(s string, loc Locale) (string, error) {
+	if loc.Group != "" && strings.TrimFunc(loc.Group, isSpace) == "" {
+		s = strings.NewReplacer(nbsp, narrowNbsp, " ", narrowNbsp).Replace(s)
+	}
+	var groups []string
+	if loc.Group != "" {
+		groups = strings.Split(s, loc.Group)
+	} else {
+		groups = []string{s}
+	}
+	for i, g := range groups {
+		if g == "" || strings.IndexFunc(g, notDigit) >= 0 {
+			return "", ErrSyntax
+		}
+		if i == 0 || len(loc.Grouping) == 0 {
+			continue
+		}
+		// Group i from the left is group len(groups)-i-1 from the right.
+		fromRight := len(groups) - i - 1
+		size := loc.Grouping[len(loc.Grouping)-1]
+		if fromRight < len(loc.Grouping) {
+			size = loc.Grouping[fromRight]
+		}
+		if len(g) != size || len(groups[0]) > size {
+			return "", ErrSyntax
+		}
+	}
+	return strings.Join(groups, ""), nil
+}
+
+// stripCurrency removes a currency symbol or ISO code from either end of s.
+// Codes are tried before symbols so "CHF" wins over any shorter match.
+func stripCurrency(s string) (string, Currency, bool) {
+	for _, useCode := range []bool{true, false} {
+		for _, cur := range currencies {
+			mark := cur.Symbol
+			if useCode {
+				mark = cur.Code
+			}
+			if rest, ok := strings.CutPrefix(s, mark); ok {
+				return strings.TrimFunc(rest, isSpace), cur, true
+			}
+			// Keep a leading minus that precedes the symbol: "-$5.00".
+			if rest, ok := strings.CutPrefix(s, "-"+mark); ok {
+				return "-" + strings.TrimFunc(rest, isSpace), cur, true
+			}
+			if rest, ok := strings.CutSuffix(s, mark); ok {
+				return strings.TrimFunc(rest, isSpace), cur, true
+			}
+		}
+	}
+	return s, Currency{}, false
+}
+
+func isSpace(r rune) bool {
+	return unicode.IsSpace(r) || r == ' '
+}
+
+func notDigit(r rune) bool {
+	return r < '0' || r > '9'
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestRoundTrip parses what the formatters print, in every locale.
+func TestRoundTrip(t *testing.T) {
+	values := []float64{0, 7, -7, 1234.5, -1234567.25, 100000, 12345678.99}
+	for _, tag := range LocaleTags() {
+		loc := locales[tag]
+		for _, v := range values {
+			s := FormatNumber(v, 2, HalfEven, loc)
+			if got, err := ParseNumber(s, loc); err != nil || got != v {
+				t.Errorf("%s: ParseNumber(%q) = %v, %v; want %v", tag, s, got, err, v)
+			}
+
+			for _, cur := range currencies {
+				if cur.Decimals == 0 {
+					continue // 1234.5 is not a whole number of yen.
+				}
+				s := FormatCurrency(v, cur, HalfEven, loc)
+				got, gotCur, err := ParseCurrency(s, loc)
+				if err != nil || got != v || gotCur.Code != cur.Code {
+					t.Errorf("%s: ParseCurrency(%q) = %v %s, %v; want %v %s", tag, s, got, gotCur.Code, err, v, cur.Code)
+				}
+			}
+
+			s = FormatPercent(v/100, 2, HalfEven, loc)
+			if got, err := ParsePercent(s, loc); err != nil || FormatNumber(got*100, 2, HalfEven, loc) != FormatNumber(v, 2, HalfEven, loc) {
+				t.Errorf("%s: ParsePercent(%q) = %v, %v; want %v", tag, s, got, err, v/100)
+			}
+		}
+	}
+}
+
+func TestStripCurrency(t *testing.T) {
+	tests := []struct {
+		in, rest, code string
+	}{
+		{"$5.00", "5.00", "USD"},
+		{"-$5.00", "-5.00", "USD"},
+		{"75.000,50 €", "75.000,50", "EUR"},
+		{"CHF 1'000", "1'000", "CHF"},
+		{"1,000 CHF", "1,000", "CHF"},
+		{"USD 12", "12", "USD"},
+		{"¥75,001", "75,001", "JPY"},
+	}
+	for _, tt := range tests {
+		rest, cur, ok := stripCurrency(tt.in)
+		if !ok || rest != tt.rest || cur.Code != tt.code {
+			t.Errorf("stripCurrency(%q) = %q, %s, %v; want %q, %s", tt.in, rest, cur.Code, ok, tt.rest, tt.code)
+		}
+	}
+	if _, _, ok := stripCurrency("12.50"); ok {
+		t.Error("stripCurrency found a currency in a plain number")
+	}
+}
+
+func TestParseNumberErrors(t *testing.T) {
+	tests := []struct {
+		tag, in string
+	}{
+		{"en-US", "1,2,3"},
+		{"en-US", "12,34"},
+		{"en-US", "1,000."},
+		{"en-US", ",100"},
+		{"en-US", "1.2.3"},
+		{"en-US", "(5"},
+		{"en-US", "abc"},
+		{"en-IN", "1,000,000"}, // Lakh grouping wants 10,00,000.
+		{"de-DE", "1,234.5"},
+	}
+	for _, tt := range tests {
+		_, err := ParseNumber(tt.in, mustLocale(t, tt.tag))
+		var pe *ParseError
+		if !errors.As(err, &pe) || !errors.Is(err, ErrSyntax) || pe.Input != tt.in {
+			t.Errorf("%s: ParseNumber(%q): err = %v, want a ParseError wrapping ErrSyntax", tt.tag, tt.in, err)
+		}
+	}
+}
+
+func TestParseForms(t *testing.T) {
+	tests := []struct {
+		tag, in string
+		parse   func(string, Locale) (float64, error)
+		want    float64
+	}{
+		{"en-US", "(1,234.50)", ParseNumber, -1234.5},
+		{"en-IN", "10,00,000", ParseNumber, 1e6},
+		{"fr-FR", "75 000,50", ParseNumber, 75000.5}, // A plain space for the narrow one.
+		{"en-US", "25.6%", ParsePercent, 0.256},
+		{"de-DE", "25,6 %", ParsePercent, 0.256},
+		{"en-US", "75K", ParseCompact, 75000},
+		{"de-DE", "1,2 Mrd.", ParseCompact, 1.2e9},
+		{"de-DE", "3 Mio.", ParseCompact, 3e6},
+	}
+	for _, tt := range tests {
+		got, err := tt.parse(tt.in, mustLocale(t, tt.tag))
+		if err != nil || got != tt.want {
+			t.Errorf("%s: parse %q = %v, %v; want %v", tt.tag, tt.in, got, err, tt.want)
+		}
+	}
+}